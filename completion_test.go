@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestCompletionCommandExists(t *testing.T) {
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "completion" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("completion command not found in root command")
+	}
+}
+
+func TestCompletionCommandGeneratesEachShell(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			completionCmd.SetOut(&buf)
+			completionCmd.Run(completionCmd, []string{shell})
+		})
+	}
+}
+
+func TestCompleteRuleNamesFiltersByPrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "ai_rulez.yaml")
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules: []config.Rule{
+			{Name: "Security Rule", Content: "content"},
+			{Name: "Style Rule", Content: "content"},
+		},
+	}
+	if err := config.SaveConfig(cfg, configFile); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	if err := updateRuleCmd.Flags().Set("config", configFile); err != nil {
+		t.Fatalf("failed to set config flag: %v", err)
+	}
+	t.Cleanup(func() { _ = updateRuleCmd.Flags().Set("config", "") })
+
+	names, _ := completeRuleNames(updateRuleCmd, nil, "Security")
+	if len(names) != 1 || names[0] != "Security Rule" {
+		t.Errorf("expected completion [Security Rule], got %v", names)
+	}
+}
+
+func TestCompleteTemplateNamesIncludesBuiltins(t *testing.T) {
+	names, _ := completeTemplateNames(initCmd, nil, "basic")
+	found := false
+	for _, name := range names {
+		if name == "basic" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected completion to include the built-in 'basic' template, got %v", names)
+	}
+}
+
+func TestCompleteTemplateNamesIncludesDiscoveredTmplFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "custom.tmpl"), []byte("{{.Name}}"), 0o644); err != nil {
+		t.Fatalf("failed to write template file: %v", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	names, _ := completeTemplateNames(addOutputCmd, nil, "@")
+	found := false
+	for _, name := range names {
+		if name == "@custom.tmpl" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected completion to include @custom.tmpl, got %v", names)
+	}
+}
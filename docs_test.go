@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDocsCommandExists(t *testing.T) {
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use == "docs" {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Error("docs command not found in root command")
+	}
+	if !docsCmd.Hidden {
+		t.Error("docs command should be hidden")
+	}
+}
+
+func TestDocsCommandGeneratesMarkdownTree(t *testing.T) {
+	outputDir := t.TempDir()
+
+	docsCmd.SetArgs(nil)
+	if err := docsCmd.Flags().Set("format", "md"); err != nil {
+		t.Fatalf("failed to set format flag: %v", err)
+	}
+	if err := docsCmd.Flags().Set("output-dir", outputDir); err != nil {
+		t.Fatalf("failed to set output-dir flag: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = docsCmd.Flags().Set("format", "man")
+		_ = docsCmd.Flags().Set("output-dir", "./docs")
+	})
+
+	if err := docsCmd.RunE(docsCmd, nil); err != nil {
+		t.Fatalf("docs command failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "ai-rulez.md")); err != nil {
+		t.Errorf("expected ai-rulez.md to be generated: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "ai-rulez_add_rule.md")); err != nil {
+		t.Errorf("expected ai-rulez_add_rule.md for the add rule subcommand: %v", err)
+	}
+}
+
+func TestDocsCommandRejectsUnknownFormat(t *testing.T) {
+	if err := docsCmd.Flags().Set("format", "bogus"); err != nil {
+		t.Fatalf("failed to set format flag: %v", err)
+	}
+	if err := docsCmd.Flags().Set("output-dir", t.TempDir()); err != nil {
+		t.Fatalf("failed to set output-dir flag: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = docsCmd.Flags().Set("format", "man")
+		_ = docsCmd.Flags().Set("output-dir", "./docs")
+	})
+
+	if err := docsCmd.RunE(docsCmd, nil); err == nil {
+		t.Error("expected an error for an unknown documentation format")
+	}
+}
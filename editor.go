@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// resolveEditor picks the editor to exec for --editor, following the same
+// $VISUAL/$EDITOR fallback chain as git and most other CLIs, and falling
+// back to a platform default if neither is set.
+func resolveEditor() string {
+	if e := os.Getenv("VISUAL"); e != "" {
+		return e
+	}
+	if e := os.Getenv("EDITOR"); e != "" {
+		return e
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// stdinIsTerminal reports whether stdin is an interactive TTY, used to
+// decide whether add/update should default to opening $EDITOR instead of
+// reading piped content from stdin.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// editInEditor writes frontmatter (read-only context lines shown as "#"
+// comments, e.g. the rule's name and priority) followed by initial into a
+// temp file matching tempPattern, opens it in $EDITOR/$VISUAL with
+// editorArgs, and reads the result back once the editor exits. The
+// frontmatter is stripped from the returned content, which is also
+// trimmed. changed reports whether the edited body differs from initial.
+func editInEditor(tempPattern string, frontmatter []string, initial string, editorArgs []string) (content string, changed bool, err error) {
+	tmpFile, err := os.CreateTemp("", tempPattern)
+	if err != nil {
+		return "", false, fmt.Errorf("creating temp file for editor: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	var buf bytes.Buffer
+	for _, line := range frontmatter {
+		buf.WriteString("# ")
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	if len(frontmatter) > 0 {
+		buf.WriteString("#\n")
+	}
+	buf.WriteString(initial)
+
+	if _, err := tmpFile.WriteString(buf.String()); err != nil {
+		tmpFile.Close()
+		return "", false, fmt.Errorf("writing temp file for editor: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", false, fmt.Errorf("closing temp file for editor: %w", err)
+	}
+
+	editor := resolveEditor()
+	args := append(append([]string{}, editorArgs...), tmpPath)
+	cmd := exec.Command(editor, args...) //nolint:gosec // editor is user-controlled via $EDITOR/$VISUAL/--editor-args
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", false, fmt.Errorf("running editor %q: %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return "", false, fmt.Errorf("reading edited content: %w", err)
+	}
+
+	content = stripEditorFrontmatter(string(edited))
+	return content, content != strings.TrimSpace(initial), nil
+}
+
+// stripEditorFrontmatter drops the leading "#"-commented block written by
+// editInEditor (and its blank "#" separator line, if present) before
+// returning the editable body.
+func stripEditorFrontmatter(raw string) string {
+	lines := strings.Split(raw, "\n")
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], "#") {
+		i++
+	}
+	return strings.TrimSpace(strings.Join(lines[i:], "\n"))
+}
+
+// useEditorForContent decides whether add/update should open $EDITOR
+// instead of reading stdin: an explicit --editor or --editor=false always wins,
+// otherwise it defaults to on when stdin is a TTY to have a real prompt,
+// and off when stdin is piped so scripts keep working unchanged.
+func useEditorForContent(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("editor") {
+		v, _ := cmd.Flags().GetBool("editor")
+		return v
+	}
+	return stdinIsTerminal()
+}
+
+// readOrEditContent gets content for add/update rule|section: via $EDITOR
+// (per useEditorForContent) seeded with frontmatter and initial, or by
+// printing stdinPrompt and reading stdin, matching the existing behavior.
+func readOrEditContent(cmd *cobra.Command, tempPattern string, frontmatter []string, initial, stdinPrompt string) (string, error) {
+	if useEditorForContent(cmd) {
+		editorArgs, _ := cmd.Flags().GetStringSlice("editor-args")
+		content, _, err := editInEditor(tempPattern, frontmatter, initial, editorArgs)
+		return content, err
+	}
+	fmt.Println(stdinPrompt)
+	return readFromStdin()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStripEditorFrontmatter(t *testing.T) {
+	raw := "# Rule: Foo\n# Priority: 5\n#\nActual content\nmore lines\n"
+	got := stripEditorFrontmatter(raw)
+	want := "Actual content\nmore lines"
+	if got != want {
+		t.Errorf("stripEditorFrontmatter() = %q, want %q", got, want)
+	}
+}
+
+func TestStripEditorFrontmatterNoFrontmatter(t *testing.T) {
+	got := stripEditorFrontmatter("just content\n")
+	if got != "just content" {
+		t.Errorf("stripEditorFrontmatter() = %q, want %q", got, "just content")
+	}
+}
+
+func TestResolveEditorFallsBackToVisualThenEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	os.Unsetenv("VISUAL")
+	os.Unsetenv("EDITOR")
+
+	if got := resolveEditor(); got == "" {
+		t.Error("resolveEditor() should never return an empty string")
+	}
+
+	t.Setenv("EDITOR", "my-editor")
+	if got := resolveEditor(); got != "my-editor" {
+		t.Errorf("resolveEditor() = %q, want my-editor", got)
+	}
+
+	t.Setenv("VISUAL", "my-visual-editor")
+	if got := resolveEditor(); got != "my-visual-editor" {
+		t.Errorf("resolveEditor() = %q, want my-visual-editor", got)
+	}
+}
+
+func TestEditInEditorDetectsUnchangedContent(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "noop-editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("failed to write fake editor script: %v", err)
+	}
+	t.Setenv("EDITOR", script)
+	t.Setenv("VISUAL", "")
+	os.Unsetenv("VISUAL")
+
+	content, changed, err := editInEditor("ai-rulez-rule-*.md", []string{"Rule: Foo"}, "unchanged content", nil)
+	if err != nil {
+		t.Fatalf("editInEditor() error = %v", err)
+	}
+	if changed {
+		t.Error("editInEditor() reported changed=true for untouched content")
+	}
+	if content != "unchanged content" {
+		t.Errorf("editInEditor() content = %q, want %q", content, "unchanged content")
+	}
+}
@@ -0,0 +1,147 @@
+// Package cache implements a small on-disk content cache for resolved
+// includes and rendered outputs, modeled after Hugo's filecache: entries
+// are addressed by a stable content hash within a namespaced directory, and
+// are honored only as long as they're younger than a configurable MaxAge.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Namespace names a cache subdirectory; each kind of cached artifact gets
+// its own so Prune/Clear can reason about them independently.
+type Namespace string
+
+const (
+	// NamespaceIncludes caches the merged rules/sections resolved from a
+	// single include, keyed by its raw bytes plus the parent config hash.
+	NamespaceIncludes Namespace = "includes"
+	// NamespaceOutputs caches a fully rendered output file, keyed by its
+	// template source plus the TemplateData it was rendered against.
+	NamespaceOutputs Namespace = "outputs"
+)
+
+// Cache is a namespaced, TTL'd content-addressed store rooted at Dir.
+type Cache struct {
+	Dir    string
+	MaxAge time.Duration
+}
+
+// DefaultDir returns $XDG_CACHE_HOME/ai-rulez (or the platform cache
+// directory equivalent), mirroring how internal/rulectx locates
+// contexts.yaml under the user config directory.
+func DefaultDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "ai-rulez"), nil
+}
+
+// New creates a Cache rooted at dir with the given TTL. A zero maxAge means
+// entries never go stale by age; Get then only misses on a missing file.
+func New(dir string, maxAge time.Duration) *Cache {
+	return &Cache{Dir: dir, MaxAge: maxAge}
+}
+
+// Key hashes parts into a stable hex-encoded sha256 key, e.g.
+// Key(rawIncludeBytes, []byte(parentConfigHash)) or
+// Key([]byte(templateSource), templateDataJSON).
+func Key(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(ns Namespace, key string) string {
+	return filepath.Join(c.Dir, string(ns), key)
+}
+
+// Get returns the cached bytes for key in namespace ns, and false if no
+// entry exists or it's older than MaxAge.
+func (c *Cache) Get(ns Namespace, key string) ([]byte, bool, error) {
+	entryPath := c.path(ns, key)
+
+	info, err := os.Stat(entryPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to stat cache entry %s: %w", entryPath, err)
+	}
+
+	if c.MaxAge > 0 && time.Since(info.ModTime()) > c.MaxAge {
+		return nil, false, nil
+	}
+
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cache entry %s: %w", entryPath, err)
+	}
+	return data, true, nil
+}
+
+// Set writes data to the cache entry for key in namespace ns.
+func (c *Cache) Set(ns Namespace, key string, data []byte) error {
+	entryPath := c.path(ns, key)
+	if err := os.MkdirAll(filepath.Dir(entryPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory %s: %w", filepath.Dir(entryPath), err)
+	}
+	if err := os.WriteFile(entryPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", entryPath, err)
+	}
+	return nil
+}
+
+// Prune removes every entry across all namespaces older than MaxAge,
+// returning the number removed. A zero MaxAge prunes nothing.
+func (c *Cache) Prune() (int, error) {
+	if c.MaxAge <= 0 {
+		return 0, nil
+	}
+	if _, err := os.Stat(c.Dir); errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+
+	removed := 0
+	err := filepath.WalkDir(c.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if time.Since(info.ModTime()) > c.MaxAge {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to prune cache at %s: %w", c.Dir, err)
+	}
+	return removed, nil
+}
+
+// Clear removes the entire cache directory.
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return fmt.Errorf("failed to clear cache at %s: %w", c.Dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/cache"
+)
+
+func TestCache_SetGetRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(t.TempDir(), time.Hour)
+	key := cache.Key([]byte("template source"), []byte(`{"name":"demo"}`))
+
+	_, ok, err := c.Get(cache.NamespaceOutputs, key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Set(cache.NamespaceOutputs, key, []byte("rendered content")))
+
+	data, ok, err := c.Get(cache.NamespaceOutputs, key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "rendered content", string(data))
+}
+
+func TestCache_GetMissesExpiredEntry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	c := cache.New(dir, time.Hour)
+	key := cache.Key([]byte("x"))
+	require.NoError(t, c.Set(cache.NamespaceIncludes, key, []byte("y")))
+
+	stale := cache.New(dir, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, ok, err := stale.Get(cache.NamespaceIncludes, key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCache_Prune(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	c := cache.New(dir, time.Nanosecond)
+	require.NoError(t, c.Set(cache.NamespaceOutputs, "stale", []byte("a")))
+	time.Sleep(time.Millisecond)
+
+	removed, err := c.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	_, err = os.Stat(filepath.Join(dir, "outputs", "stale"))
+	assert.Error(t, err)
+}
+
+func TestCache_Clear(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	c := cache.New(dir, time.Hour)
+	require.NoError(t, c.Set(cache.NamespaceOutputs, "key", []byte("a")))
+
+	require.NoError(t, c.Clear())
+
+	_, err := os.Stat(dir)
+	assert.Error(t, err)
+}
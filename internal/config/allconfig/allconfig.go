@@ -0,0 +1,114 @@
+// Package allconfig provides an introspectable decoding path for
+// configuration, layered on top of internal/config rather than replacing
+// it: config.Config stays the type every other package builds against,
+// and Decode here is the one entry point that also hands back where each
+// field came from in the source YAML, for diagnostics that want more than
+// "some rule is missing a priority" (e.g. "ai-rulez.yaml:12: rule 'Style'
+// has no priority, defaulted to 1").
+package allconfig
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+// SourcePosition is the file:line:column a decoded field's value came
+// from in the source YAML.
+type SourcePosition struct {
+	Line   int
+	Column int
+}
+
+// ConfigSourceMap maps a dotted field path (matching config's own
+// gojsonschema-style convention, e.g. "rules.0.priority"; see
+// config.ValidateWithSchema) to the source position of that value, for
+// every field actually present in the decoded YAML. A field Decode
+// defaulted (see config.ApplyDefaults) rather than read has no entry.
+type ConfigSourceMap map[string]SourcePosition
+
+// Decode parses raw as a configuration file's contents (already
+// normalized to YAML; see config.LoadConfig for format detection), schema
+// validates it, applies config.ApplyDefaults, and returns both the
+// resulting *config.Config and a ConfigSourceMap recording where every
+// present field was defined - the same information config.ValidateWithSchema
+// uses internally to attach file:line:col to a schema violation, exposed
+// here for callers (e.g. `ai-rulez config print --explain`) that want it
+// for a field that isn't in error.
+func Decode(raw []byte, filename string) (*config.Config, ConfigSourceMap, error) {
+	if err := config.ValidateWithSchema(raw, filename); err != nil {
+		return nil, nil, fmt.Errorf("schema validation failed for %s: %w", filename, err)
+	}
+
+	var cfg config.Config
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+
+	config.ApplyDefaults(&cfg)
+
+	sources, err := indexSourcePositions(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to index source positions for %s: %w", filename, err)
+	}
+
+	return &cfg, sources, nil
+}
+
+// indexSourcePositions walks raw's YAML AST, recording the line/column of
+// every reachable value under its dotted field path.
+func indexSourcePositions(raw []byte) (ConfigSourceMap, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, err
+	}
+
+	sources := ConfigSourceMap{}
+	if len(root.Content) > 0 {
+		walkSourcePositions(root.Content[0], "", sources)
+	}
+	return sources, nil
+}
+
+func walkSourcePositions(node *yaml.Node, path string, sources ConfigSourceMap) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		sources[path] = SourcePosition{node.Line, node.Column}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinPath(path, key.Value)
+			sources[childPath] = SourcePosition{value.Line, value.Column}
+			walkSourcePositions(value, childPath, sources)
+		}
+	case yaml.SequenceNode:
+		sources[path] = SourcePosition{node.Line, node.Column}
+		for i, item := range node.Content {
+			childPath := joinPath(path, strconv.Itoa(i))
+			sources[childPath] = SourcePosition{item.Line, item.Column}
+			walkSourcePositions(item, childPath, sources)
+		}
+	default:
+		sources[path] = SourcePosition{node.Line, node.Column}
+	}
+}
+
+func joinPath(path, segment string) string {
+	if path == "" {
+		return segment
+	}
+	return path + "." + segment
+}
+
+// PrintEffective renders cfg as YAML, the same serialization config.SaveConfig
+// writes to disk - the effective, fully-defaulted configuration `ai-rulez
+// config print` shows, as opposed to the source file a user actually wrote.
+func PrintEffective(cfg *config.Config) (string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal effective config: %w", err)
+	}
+	return string(data), nil
+}
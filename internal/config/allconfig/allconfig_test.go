@@ -0,0 +1,53 @@
+package allconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config/allconfig"
+)
+
+const sampleConfig = `
+metadata:
+  name: Test Project
+outputs:
+  - file: CLAUDE.md
+rules:
+  - name: Style Rule
+    content: Use strict mode
+  - name: Testing Rule
+    priority: 5
+    content: Write unit tests
+`
+
+func TestDecode_AppliesDefaultsAndRecordsSourcePositions(t *testing.T) {
+	t.Parallel()
+
+	cfg, sources, err := allconfig.Decode([]byte(sampleConfig), "ai-rulez.yaml")
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, 1, cfg.Rules[0].Priority, "expected the unset priority to be defaulted to 1")
+	assert.Equal(t, 5, cfg.Rules[1].Priority, "expected the explicit priority to be preserved")
+
+	pos, ok := sources["rules.1.priority"]
+	require.True(t, ok, "expected a source position for the explicitly-set priority field")
+	assert.Positive(t, pos.Line)
+
+	_, ok = sources["rules.0.priority"]
+	assert.False(t, ok, "a defaulted field that was never in the source YAML should have no position")
+}
+
+func TestPrintEffective_RoundTrips(t *testing.T) {
+	t.Parallel()
+
+	cfg, _, err := allconfig.Decode([]byte(sampleConfig), "ai-rulez.yaml")
+	require.NoError(t, err)
+
+	rendered, err := allconfig.PrintEffective(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, rendered, "Test Project")
+	assert.Contains(t, rendered, "priority: 1")
+}
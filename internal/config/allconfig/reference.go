@@ -0,0 +1,73 @@
+package allconfig
+
+import "strings"
+
+// FieldDoc documents one configuration field for `ai-rulez config
+// reference`. Path is the dotted yaml key (e.g. "rules[].priority");
+// Default is shown blank when a field has no default. The text mirrors
+// the doc comments on the corresponding config.Config/Rule/Section/Output
+// field - this is a second, human-facing rendering of the same facts,
+// not a second source of truth, so keep it in sync by hand when those
+// doc comments change.
+type FieldDoc struct {
+	Path        string
+	Type        string
+	Default     string
+	Description string
+}
+
+// Fields returns the reference documentation for every top-level
+// configuration field, in the order they'd typically appear in a config
+// file.
+func Fields() []FieldDoc {
+	return []FieldDoc{
+		{"metadata.name", "string", "", "Project name, required."},
+		{"metadata.version", "string", "", "Project version, shown in generated output headers."},
+		{"metadata.description", "string", "", "One-line project description, shown in generated output headers."},
+		{"includes", "[]include", "", "Other config files (local, glob, directory, or remote git::/http(s)://) whose rules and sections merge into this one."},
+		{"outputs[].file", "string", "", "Path to write, relative to the config file. Required."},
+		{"outputs[].template", "string", "\"default\"", "Named template, inline template string, or @file reference."},
+		{"outputs[].engine", "string", "\"go\"", "Template engine: go, handlebars, or mustache."},
+		{"outputs[].selector", "string", "", "Boolean tag expression (see internal/selector) narrowing this output to matching rules/sections."},
+		{"outputs[].compose", "[]fragment", "", "Per-selector template fragments concatenated into this output, instead of a single template."},
+		{"outputs[].for_each", "string", "", "Expands into one file per matching rule/section, e.g. `rules where tag == \"security\"`."},
+		{"rules[].id", "string", "", "Stable identifier, used by an Output's Rules allow-list and by patches."},
+		{"rules[].name", "string", "", "Display name, required."},
+		{"rules[].priority", "int", "1", "Sort weight; higher renders first."},
+		{"rules[].content", "string", "", "Rule body text, required."},
+		{"rules[].when", "string", "", "Selector expression gating whether this rule is active for the current --tag context."},
+		{"rules[].tags", "[]string", "", "Tags this rule can be filtered or selected by (Output.Selector, for_each)."},
+		{"sections[].id", "string", "", "Stable identifier, used by an Output's Sections allow-list and by patches."},
+		{"sections[].title", "string", "", "Display title, required."},
+		{"sections[].priority", "int", "1", "Sort weight; higher renders first."},
+		{"sections[].content", "string", "", "Section body text, required."},
+		{"sections[].when", "string", "", "Selector expression gating whether this section is active for the current --tag context."},
+		{"sections[].tags", "[]string", "", "Tags this section can be filtered or selected by (Output.Selector, for_each)."},
+		{"user_rulez", "object", "", "A second rules/sections tree rendered separately, for user-level (as opposed to project-level) content."},
+		{"profiles", "map[string]profile", "", "Named overlays applied on top of the merged config by LoadConfigWithProfile."},
+		{"delims", "[]string", "[\"{{\", \"}}\"]", "Project-wide default Go template action delimiters, inherited by outputs that don't set their own."},
+		{"header", "string|object", "", "Project-wide default header banner, inherited by outputs that don't set their own Header/HeaderFile."},
+		{"header_file", "string", "", "Project-wide default header file, same fallback as header."},
+		{"config_dir", "string", "", "A conf.d-style directory whose *.yaml/*.yml/*.json fragments are loaded and merged on top of this config."},
+		{"cache.enabled", "bool", "false", "Turns on the on-disk filecache for resolved includes and rendered outputs."},
+		{"cache.dir", "string", "$XDG_CACHE_HOME/ai-rulez", "Overrides the cache root."},
+		{"cache.maxAge", "string", "\"24h\"", "A time.ParseDuration string after which a cache entry is treated as stale."},
+	}
+}
+
+// Reference renders Fields as a Markdown table, suitable for `ai-rulez
+// config reference`'s output or for checking into docs/configuration.md.
+func Reference() string {
+	var b strings.Builder
+	b.WriteString("# Configuration reference\n\n")
+	b.WriteString("| Field | Type | Default | Description |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, f := range Fields() {
+		def := f.Default
+		if def == "" {
+			def = "-"
+		}
+		b.WriteString("| `" + f.Path + "` | " + f.Type + " | " + def + " | " + f.Description + " |\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,18 @@
+package allconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Goldziher/ai-rulez/internal/config/allconfig"
+)
+
+func TestReference_ListsCoreFields(t *testing.T) {
+	t.Parallel()
+
+	doc := allconfig.Reference()
+	assert.Contains(t, doc, "metadata.name")
+	assert.Contains(t, doc, "rules[].priority")
+	assert.Contains(t, doc, "outputs[].selector")
+}
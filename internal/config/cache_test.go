@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestCacheConfig_MaxAgeDuration_Default(t *testing.T) {
+	t.Parallel()
+
+	var c *config.CacheConfig
+	d, err := c.MaxAgeDuration()
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultCacheMaxAge, d)
+
+	c = &config.CacheConfig{Enabled: true}
+	d, err = c.MaxAgeDuration()
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultCacheMaxAge, d)
+}
+
+func TestCacheConfig_MaxAgeDuration_Parsed(t *testing.T) {
+	t.Parallel()
+
+	c := &config.CacheConfig{Enabled: true, MaxAge: "2h"}
+	d, err := c.MaxAgeDuration()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, d)
+}
+
+func TestCacheConfig_MaxAgeDuration_Invalid(t *testing.T) {
+	t.Parallel()
+
+	c := &config.CacheConfig{Enabled: true, MaxAge: "not-a-duration"}
+	_, err := c.MaxAgeDuration()
+	assert.Error(t, err)
+}
+
+func TestCacheConfig_NamespaceEnabled(t *testing.T) {
+	t.Parallel()
+
+	var nilCfg *config.CacheConfig
+	assert.False(t, nilCfg.NamespaceEnabled("outputs"))
+
+	disabled := &config.CacheConfig{Enabled: false}
+	assert.False(t, disabled.NamespaceEnabled("outputs"))
+
+	allNamespaces := &config.CacheConfig{Enabled: true}
+	assert.True(t, allNamespaces.NamespaceEnabled("outputs"))
+	assert.True(t, allNamespaces.NamespaceEnabled("includes"))
+
+	scoped := &config.CacheConfig{Enabled: true, Namespaces: []string{"outputs"}}
+	assert.True(t, scoped.NamespaceEnabled("outputs"))
+	assert.False(t, scoped.NamespaceEnabled("includes"))
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// LoadConfigDir loads every *.yaml/*.yml/*.json fragment directly inside
+// dir, in lexicographic order, and merges them into a single Config,
+// mirroring the frostfs-node conf.d pattern. Dotfiles and common editor
+// artifacts (*.un~, *.swp, *~) are silently skipped. Each fragment is
+// loaded (and schema-validated) individually via LoadConfig so a per-file
+// error points at the right file, and the merged result is schema-validated
+// again afterwards.
+//
+// Merge semantics:
+//   - Metadata: last-wins (the last fragment that sets it takes effect).
+//   - Outputs: merged by File, later definitions overriding earlier ones;
+//     see MergeOutputs.
+//   - Rules and Sections: appended and de-duplicated by ID (falling back to
+//     Name/Title), same as MergeRules/MergeSections, except two fragments
+//     defining the same key with different content produce an error naming
+//     both files instead of silently letting the later one win.
+func LoadConfigDir(dir string) (*Config, error) {
+	names, err := confdFragmentNames(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("config directory %s contains no *.yaml/*.yml/*.json files", dir)
+	}
+
+	fragments := make([]configFragment, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, configFragment{path: path, cfg: cfg})
+	}
+
+	merged, err := mergeConfigFragments(fragments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateConfigWithSchema(merged); err != nil {
+		return nil, fmt.Errorf("merged configuration from %s failed schema validation: %w", dir, err)
+	}
+
+	return merged, nil
+}
+
+// confdFragmentNames returns the names of the config fragments directly
+// inside dir (not recursive), in lexicographic order.
+func confdFragmentNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan config directory %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isConfigDirFragment(entry.Name()) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// isConfigDirFragment reports whether name is a file LoadConfigDir should
+// load: a *.yaml/*.yml/*.json file that isn't a dotfile or a common editor
+// artifact (*.un~, *.swp, *~).
+func isConfigDirFragment(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") || strings.HasSuffix(name, ".swp") {
+		return false
+	}
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// configFragment pairs a loaded Config with the file it came from, so
+// mergeConfigFragments can name the right file in a conflict error.
+type configFragment struct {
+	path string
+	cfg  *Config
+}
+
+// loadConfigDirInto merges the fragments found in mainConfig.ConfigDir
+// (resolved relative to the directory containing mainFile) onto
+// mainConfig, per the merge semantics documented on LoadConfigDir.
+func loadConfigDirInto(mainConfig *Config, mainFile string) (*Config, error) {
+	dir := mainConfig.ConfigDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(mainFile), dir)
+	}
+
+	fragmentConfig, err := LoadConfigDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config_dir %s for %s: %w", mainConfig.ConfigDir, mainFile, err)
+	}
+
+	mainConfig.ConfigDir = ""
+	merged, err := mergeConfigFragments([]configFragment{
+		{path: mainFile, cfg: mainConfig},
+		{path: dir, cfg: fragmentConfig},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := *mainConfig
+	result.Metadata = merged.Metadata
+	result.Outputs = merged.Outputs
+	result.Rules = merged.Rules
+	result.Sections = merged.Sections
+	return &result, nil
+}
+
+// mergeConfigFragments merges fragments, in order, per the semantics
+// documented on LoadConfigDir.
+func mergeConfigFragments(fragments []configFragment) (*Config, error) {
+	merged := &Config{}
+
+	var ruleSrcs []ruleSource
+	var sectionSrcs []sectionSource
+	var outputSets [][]Output
+
+	for _, f := range fragments {
+		if f.cfg.Metadata != (Metadata{}) {
+			merged.Metadata = f.cfg.Metadata
+		}
+		outputSets = append(outputSets, f.cfg.Outputs)
+		for _, rule := range f.cfg.Rules {
+			ruleSrcs = append(ruleSrcs, ruleSource{path: f.path, rule: rule})
+		}
+		for _, section := range f.cfg.Sections {
+			sectionSrcs = append(sectionSrcs, sectionSource{path: f.path, section: section})
+		}
+	}
+
+	rules, err := mergeRulesStrict(ruleSrcs)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := mergeSectionsStrict(sectionSrcs)
+	if err != nil {
+		return nil, err
+	}
+
+	merged.Rules = rules
+	merged.Sections = sections
+	merged.Outputs = MergeOutputs(outputSets...)
+
+	return merged, nil
+}
+
+// ruleSource pairs a Rule with the file it was defined in, for
+// mergeRulesStrict's conflict error.
+type ruleSource struct {
+	path string
+	rule Rule
+}
+
+// mergeRulesStrict appends and de-duplicates rules by ID (falling back to
+// Name), like MergeRules, but returns an error instead of silently letting
+// the later definition win when two fragments disagree on a rule's content.
+func mergeRulesStrict(sources []ruleSource) ([]Rule, error) {
+	seen := make(map[string]ruleSource)
+	var order []string
+
+	for _, src := range sources {
+		key := src.rule.Name
+		if src.rule.ID != "" {
+			key = src.rule.ID
+		}
+
+		if existing, ok := seen[key]; ok {
+			if !reflect.DeepEqual(existing.rule, src.rule) {
+				return nil, fmt.Errorf("rule %q is defined differently in %s and %s",
+					key, filepath.Base(existing.path), filepath.Base(src.path))
+			}
+			continue
+		}
+
+		seen[key] = src
+		order = append(order, key)
+	}
+
+	result := make([]Rule, 0, len(order))
+	for _, key := range order {
+		result = append(result, seen[key].rule)
+	}
+	return result, nil
+}
+
+// sectionSource pairs a Section with the file it was defined in, for
+// mergeSectionsStrict's conflict error.
+type sectionSource struct {
+	path    string
+	section Section
+}
+
+// mergeSectionsStrict is mergeRulesStrict for Sections, keyed by ID
+// (falling back to Title).
+func mergeSectionsStrict(sources []sectionSource) ([]Section, error) {
+	seen := make(map[string]sectionSource)
+	var order []string
+
+	for _, src := range sources {
+		key := src.section.Title
+		if src.section.ID != "" {
+			key = src.section.ID
+		}
+
+		if existing, ok := seen[key]; ok {
+			if !reflect.DeepEqual(existing.section, src.section) {
+				return nil, fmt.Errorf("section %q is defined differently in %s and %s",
+					key, filepath.Base(existing.path), filepath.Base(src.path))
+			}
+			continue
+		}
+
+		seen[key] = src
+		order = append(order, key)
+	}
+
+	result := make([]Section, 0, len(order))
+	for _, key := range order {
+		result = append(result, seen[key].section)
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func writeConfDFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+}
+
+func TestLoadConfigDir_MergesFragmentsInOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfDFiles(t, dir, map[string]string{
+		"00-base.yaml": `metadata:
+  name: "base"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "style"
+    name: "Style"
+    content: "Use strict mode"`,
+		"10-overrides.yaml": `metadata:
+  name: "final"
+outputs:
+  - file: ".cursorrules"
+rules:
+  - id: "testing"
+    name: "Testing"
+    content: "Write tests"`,
+	})
+
+	cfg, err := config.LoadConfigDir(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "final", cfg.Metadata.Name)
+	assert.Len(t, cfg.Outputs, 2)
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, "Style", cfg.Rules[0].Name)
+	assert.Equal(t, "Testing", cfg.Rules[1].Name)
+}
+
+func TestLoadConfigDir_SkipsDotfilesAndEditorArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfDFiles(t, dir, map[string]string{
+		"00-base.yaml": `metadata:
+  name: "base"
+outputs:
+  - file: "CLAUDE.md"`,
+		".hidden.yaml":     `metadata:\n  name: "should not load"`,
+		"00-base.yaml.un~": `metadata:\n  name: "should not load"`,
+		"notes.swp":        `metadata:\n  name: "should not load"`,
+		"README.md":        "not a config file",
+	})
+
+	cfg, err := config.LoadConfigDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "base", cfg.Metadata.Name)
+}
+
+func TestLoadConfigDir_ConflictingRuleErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfDFiles(t, dir, map[string]string{
+		"00-base.yaml": `metadata:
+  name: "base"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "style"
+    name: "Style"
+    content: "Use strict mode"`,
+		"10-other.yaml": `metadata:
+  name: "base"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "style"
+    name: "Style"
+    content: "Use a different style entirely"`,
+	})
+
+	_, err := config.LoadConfigDir(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `rule "style" is defined differently`)
+}
+
+func TestLoadConfigDir_EmptyDirectoryErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := config.LoadConfigDir(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains no")
+}
+
+func TestLoadConfig_WithConfigDirKeyMergesFragments(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "conf.d"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ai_rulez.yaml"), []byte(`metadata:
+  name: "main"
+config_dir: conf.d
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "base"
+    name: "Base"
+    content: "Base content"`), 0o644))
+	writeConfDFiles(t, filepath.Join(dir, "conf.d"), map[string]string{
+		"00-extra.yaml": `metadata:
+  name: "main"
+outputs:
+  - file: ".cursorrules"
+rules:
+  - id: "extra"
+    name: "Extra"
+    content: "Extra content"`,
+	})
+
+	cfg, err := config.LoadConfig(filepath.Join(dir, "ai_rulez.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "main", cfg.Metadata.Name)
+	assert.Len(t, cfg.Outputs, 2)
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, "Base", cfg.Rules[0].Name)
+	assert.Equal(t, "Extra", cfg.Rules[1].Name)
+	assert.Empty(t, cfg.ConfigDir)
+}
+
+func TestLoadConfig_DispatchesDirectoryToLoadConfigDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfDFiles(t, dir, map[string]string{
+		"00-base.yaml": `metadata:
+  name: "base"
+outputs:
+  - file: "CLAUDE.md"`,
+	})
+
+	cfg, err := config.LoadConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "base", cfg.Metadata.Name)
+}
@@ -5,18 +5,160 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/config/format"
 )
 
 // Config represents the main configuration structure
 type Config struct {
-	Metadata  Metadata   `yaml:"metadata"`
-	Includes  []string   `yaml:"includes,omitempty"`
-	Outputs   []Output   `yaml:"outputs"`
-	Rules     []Rule     `yaml:"rules,omitempty"`
-	Sections  []Section  `yaml:"sections,omitempty"`
-	UserRulez *UserRulez `yaml:"user_rulez,omitempty"`
+	Metadata  Metadata       `yaml:"metadata"`
+	Includes  []IncludeEntry `yaml:"includes,omitempty"`
+	Outputs   []Output       `yaml:"outputs"`
+	Rules     []Rule         `yaml:"rules,omitempty"`
+	Sections  []Section      `yaml:"sections,omitempty"`
+	UserRulez *UserRulez     `yaml:"user_rulez,omitempty"`
+	// Profiles declares named overlays applied on top of this (already
+	// merged) config by LoadConfigWithProfile; see Profile for details.
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+	// Delims sets the project-wide default Go template action delimiters,
+	// e.g. ["<%", "%>"], inherited by any Output that doesn't set its own.
+	// Left empty, Go's default "{{"/"}}" delimiters are used.
+	Delims []string `yaml:"delims,omitempty"`
+	// Header sets the project-wide default header banner, inherited by any
+	// Output that doesn't set its own Header or HeaderFile. See Output.Header.
+	Header *HeaderValue `yaml:"header,omitempty"`
+	// HeaderFile sets the project-wide default header file, inherited the
+	// same way Header is. See Output.HeaderFile.
+	HeaderFile string `yaml:"header_file,omitempty"`
+	// ConfigDir names a conf.d-style directory (relative to this config
+	// file) whose *.yaml/*.yml/*.json fragments are loaded and merged on
+	// top of this config by LoadConfig. See LoadConfigDir for merge
+	// semantics.
+	ConfigDir string `yaml:"config_dir,omitempty"`
+	// Cache configures the on-disk filecache for resolved includes and
+	// rendered outputs (see internal/cache). Left nil, caching is disabled
+	// and every invocation re-reads includes and re-renders every output.
+	Cache *CacheConfig `yaml:"cache,omitempty"`
+}
+
+// CacheConfig configures the on-disk filecache internal/cache builds on
+// top of. Dir and MaxAge fall back to sensible defaults (see
+// cache.DefaultDir and DefaultCacheMaxAge) when left empty, so the common
+// case is just `cache: {enabled: true}`.
+type CacheConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Dir overrides the cache root, default $XDG_CACHE_HOME/ai-rulez (see
+	// cache.DefaultDir).
+	Dir string `yaml:"dir,omitempty"`
+	// MaxAge is a time.ParseDuration string (e.g. "24h") after which a
+	// cache entry is treated as stale and re-resolved/re-rendered. Default
+	// DefaultCacheMaxAge.
+	MaxAge string `yaml:"maxAge,omitempty"`
+	// Namespaces restricts caching to the named internal/cache namespaces
+	// (e.g. ["outputs"]); empty means every namespace is cached.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+}
+
+// DefaultCacheMaxAge is the TTL a CacheConfig with no MaxAge set falls back
+// to.
+const DefaultCacheMaxAge = 24 * time.Hour
+
+// MaxAgeDuration parses c.MaxAge, falling back to DefaultCacheMaxAge when
+// it's empty.
+func (c *CacheConfig) MaxAgeDuration() (time.Duration, error) {
+	if c == nil || c.MaxAge == "" {
+		return DefaultCacheMaxAge, nil
+	}
+	d, err := time.ParseDuration(c.MaxAge)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache maxAge %q: %w", c.MaxAge, err)
+	}
+	return d, nil
+}
+
+// NamespaceEnabled reports whether ns should be cached under c: true when
+// c is enabled and either Namespaces is empty (cache everything) or ns is
+// listed.
+func (c *CacheConfig) NamespaceEnabled(ns string) bool {
+	if c == nil || !c.Enabled {
+		return false
+	}
+	if len(c.Namespaces) == 0 {
+		return true
+	}
+	for _, n := range c.Namespaces {
+		if n == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// Profile is a named overlay resolved by LoadConfigWithProfile. Its Rules,
+// Sections and Outputs are merged on top of the base config (and, via
+// Extends, on top of any ancestor profile's overlay) using the same
+// ID/name-keyed merge semantics as MergeRules/MergeSections/MergeOutputs, so
+// a profile entry with a matching ID replaces the base entry rather than
+// duplicating it.
+type Profile struct {
+	// Extends names another profile in the same Profiles map whose overlay
+	// is applied first. A name that isn't itself a profile - including the
+	// conventional "base" - simply grounds the chain at the base config.
+	Extends  string    `yaml:"extends,omitempty"`
+	Rules    []Rule    `yaml:"rules,omitempty"`
+	Sections []Section `yaml:"sections,omitempty"`
+	Outputs  []Output  `yaml:"outputs,omitempty"`
+}
+
+// IncludeEntry is one entry of Includes. It unmarshals from either a plain
+// path string (the common case) or a mapping with an optional `when:`
+// selector gating whether the include is processed at all, e.g.:
+//
+//	includes:
+//	  - "shared/base.yaml"
+//	  - path: "shared/frontend.yaml"
+//	    when: "has(package.json)"
+//	  - path: "git::https://github.com/acme/standards//rules.yaml"
+//	    prefix: "acme-"
+type IncludeEntry struct {
+	Path string `yaml:"path"`
+	When string `yaml:"when,omitempty"`
+	// Prefix is prepended to the ID (or, lacking one, the Name/Title) of
+	// every Rule and Section this include contributes, before merging -
+	// a team pulling in a shared module this way can avoid its rule names
+	// colliding with the including project's own, the same way a
+	// Terraform module call-site namespaces the resources it creates.
+	Prefix string `yaml:"prefix,omitempty"`
+}
+
+// UnmarshalYAML accepts either a bare scalar path or a {path, when} mapping.
+func (e *IncludeEntry) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&e.Path)
+	}
+
+	type includeEntryAlias IncludeEntry
+	var alias includeEntryAlias
+	if err := value.Decode(&alias); err != nil {
+		return err
+	}
+	*e = IncludeEntry(alias)
+	return nil
+}
+
+// MarshalYAML renders an entry with neither When nor Prefix set as a bare
+// string, matching how most includes are authored, and falls back to the
+// full mapping otherwise.
+func (e IncludeEntry) MarshalYAML() (interface{}, error) {
+	if e.When == "" && e.Prefix == "" {
+		return e.Path, nil
+	}
+	type includeEntryAlias IncludeEntry
+	return includeEntryAlias(e), nil
 }
 
 // UserRulez contains user-specific rules and sections
@@ -36,14 +178,138 @@ type Metadata struct {
 type Output struct {
 	File     string `yaml:"file"`
 	Template string `yaml:"template,omitempty"`
+	// Engine selects the template engine used to render Template: "go" (default),
+	// "handlebars", or "mustache". Left empty, @file templates are detected by extension.
+	Engine string `yaml:"engine,omitempty"`
+	// Extends names a sibling output whose Template, Engine, Rules and Sections
+	// are inherited. The child's own fields, when set, take precedence.
+	Extends string `yaml:"extends,omitempty"`
+	// Rules, when non-empty, restricts this output to rules matching these
+	// IDs or names (inherited from Extends unless the child sets its own).
+	Rules []string `yaml:"rules,omitempty"`
+	// Sections, when non-empty, restricts this output to sections matching
+	// these IDs or titles (inherited from Extends unless the child sets its own).
+	Sections []string `yaml:"sections,omitempty"`
+	// Mode selects the write strategy used when this file already exists:
+	// OutputModeOverwrite (default), OutputModeSkip, OutputModeAppend, or
+	// OutputModeMerge. See those constants for details.
+	Mode string `yaml:"mode,omitempty"`
+	// Delims overrides the Go template action delimiters for this output,
+	// e.g. ["<%", "%>"], so generated content can use literal "{{ }}" for
+	// another templating system. Falls back to the top-level Config.Delims,
+	// then to Go's default "{{"/"}}" when both are empty.
+	Delims []string `yaml:"delims,omitempty"`
+	// ForEach, when set, expands this single Output into one rendered file
+	// per rule or section instead of one combined file. See ParseForEach for
+	// the expression grammar. File is rendered as a Go template per item,
+	// scoped via TemplateData.Item, so it can reference e.g. "{{.Item.Name}}".
+	ForEach string `yaml:"for_each,omitempty"`
+	// Header overrides the "generated file, do not edit" banner prepended to
+	// this output: `header: false` disables the banner entirely, and any
+	// other string replaces it, rendered as a Go template against the same
+	// TemplateData the output itself receives. Falls back to the top-level
+	// Config.Header, then to HeaderFile (here, then at the Config level),
+	// then to the built-in banner (see templates.GenerateHeader).
+	Header *HeaderValue `yaml:"header,omitempty"`
+	// HeaderFile names a file (relative to the config file) whose content
+	// replaces the default banner, rendered as a Go template the same way
+	// Header is. Ignored when Header is set. Useful for a standard
+	// copyright, license notice, or SPDX identifier shared across outputs.
+	HeaderFile string `yaml:"header_file,omitempty"`
+	// Selector narrows this output to rules and sections whose own Tags
+	// satisfy a selector.Compile expression, e.g. `security and not draft`.
+	// Unlike Rules/Sections (an allow-list by ID or name), Selector
+	// matches by tag, composing with Rules/Sections rather than replacing
+	// them: an item must pass both to be included.
+	Selector string `yaml:"selector,omitempty"`
+	// Compose renders this output as the concatenation of each
+	// ComposeFragment's own selector-filtered render, in order, instead of
+	// a single Template - e.g. a "security" fragment followed by a
+	// "frontend" fragment in one file. When set, Template is ignored.
+	Compose []ComposeFragment `yaml:"compose,omitempty"`
+}
+
+// ComposeFragment is one piece of an Output's Compose list: Selector
+// narrows the output's rules/sections down to this fragment's slice (on
+// top of Output.Selector and Output.Rules/Sections), rendered through its
+// own Template the same way Output.Template is.
+type ComposeFragment struct {
+	Selector string `yaml:"selector,omitempty"`
+	Template string `yaml:"template"`
+}
+
+// HeaderValue is the unmarshaled form of Output.Header or Config.Header.
+type HeaderValue struct {
+	// Disabled is true for `header: false`, suppressing the banner entirely.
+	Disabled bool
+	// Template is the header content for `header: "<template>"`, rendered as
+	// a Go template. Meaningless when Disabled is true.
+	Template string
+}
+
+// UnmarshalYAML accepts either a boolean (only `false` is meaningful; it
+// disables the header) or a string template.
+func (h *HeaderValue) UnmarshalYAML(value *yaml.Node) error {
+	var raw interface{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	switch v := raw.(type) {
+	case bool:
+		h.Disabled = !v
+	case string:
+		h.Template = v
+	default:
+		return fmt.Errorf("header must be a boolean or a string, got %T", raw)
+	}
+
+	return nil
 }
 
+// MarshalYAML renders a disabled header as `false` and a template override
+// as a bare string.
+func (h HeaderValue) MarshalYAML() (interface{}, error) {
+	if h.Disabled {
+		return false, nil
+	}
+	return h.Template, nil
+}
+
+// Output write-mode strategies for Output.Mode.
+const (
+	// OutputModeOverwrite replaces the file whenever rendered content
+	// differs from what's on disk. This is the default when Mode is empty.
+	OutputModeOverwrite = "overwrite"
+	// OutputModeSkip leaves an existing file alone once it has been created,
+	// useful for scaffolded READMEs and starter prompts users edit by hand.
+	OutputModeSkip = "skip"
+	// OutputModeAppend writes the rendered content below a stable marker,
+	// preserving any hand-written preface above it and replacing whatever
+	// followed the marker on a prior run.
+	OutputModeAppend = "append"
+	// OutputModeMerge preserves hand-written content outside a generated
+	// `# ai-rulez:begin id=...` / `# ai-rulez:end` block while replacing the
+	// block itself, so generated and hand-written content can share a file.
+	OutputModeMerge = "merge"
+)
+
 // Rule represents a single rule definition
 type Rule struct {
 	ID       string `yaml:"id,omitempty"`
 	Name     string `yaml:"name"`
 	Priority int    `yaml:"priority,omitempty"`
 	Content  string `yaml:"content"`
+	// When is a selector expression (see internal/selector) gating whether
+	// this rule is active for the current Context. Left empty, the rule is
+	// always active.
+	When string `yaml:"when,omitempty"`
+	// Disabled, when set by a profile overlay, drops this rule from the
+	// final config instead of overriding its content. See LoadConfigWithProfile.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// Tags classifies this rule for filtering, e.g. an Output's
+	// `for_each: rules where tag == "security"`. See ParseForEach.
+	Tags []string `yaml:"tags,omitempty"`
 }
 
 // Section represents an informative text section
@@ -52,59 +318,58 @@ type Section struct {
 	Title    string `yaml:"title"`
 	Priority int    `yaml:"priority,omitempty"`
 	Content  string `yaml:"content"`
+	// When is a selector expression (see internal/selector) gating whether
+	// this section is active for the current Context. Left empty, the
+	// section is always active.
+	When string `yaml:"when,omitempty"`
+	// Tags classifies this section for filtering, e.g. an Output's
+	// `for_each: sections where tag == "security"`. See ParseForEach.
+	Tags []string `yaml:"tags,omitempty"`
 }
 
-// LoadConfig loads configuration from a YAML file
+// LoadConfig loads configuration from a YAML file. If filename names a
+// directory, it is loaded as a conf.d-style config directory (see
+// LoadConfigDir) instead of a single file.
 func LoadConfig(filename string) (*Config, error) {
+	if info, err := os.Stat(filename); err == nil && info.IsDir() {
+		return LoadConfigDir(filename)
+	}
+
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %s: %w", filename, err)
 	}
 
+	data, err = normalizeToYAML(filename, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+
 	// Validate against schema first
-	if err := ValidateWithSchema(data); err != nil {
+	if err := ValidateWithSchema(data, filename); err != nil {
 		return nil, fmt.Errorf("schema validation failed for %s: %w", filename, err)
 	}
 
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
-	}
-
-	// Set default priority for rules
-	for i := range config.Rules {
-		if config.Rules[i].Priority == 0 {
-			config.Rules[i].Priority = 1
-		}
+		return nil, wrapYAMLError(filename, data, err)
 	}
 
-	// Set default priority for sections
-	for i := range config.Sections {
-		if config.Sections[i].Priority == 0 {
-			config.Sections[i].Priority = 1
-		}
-	}
+	ApplyDefaults(&config)
 
-	// Set default priority for user_rulez
-	if config.UserRulez != nil {
-		for i := range config.UserRulez.Rules {
-			if config.UserRulez.Rules[i].Priority == 0 {
-				config.UserRulez.Rules[i].Priority = 1
-			}
-		}
-		for i := range config.UserRulez.Sections {
-			if config.UserRulez.Sections[i].Priority == 0 {
-				config.UserRulez.Sections[i].Priority = 1
-			}
-		}
+	if config.ConfigDir != "" {
+		return loadConfigDirInto(&config, filename)
 	}
 
 	return &config, nil
 }
 
-// SaveConfig saves configuration to a YAML file
+// SaveConfig saves configuration to filename, in whichever format its
+// extension selects (YAML, JSON, TOML, or .env; see the format package and
+// marshalConfig). Plain YAML - including an unrecognized or missing
+// extension - is the default.
 func SaveConfig(config *Config, filename string) error {
-	data, err := yaml.Marshal(config)
+	data, err := marshalConfig(config, filename)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -121,6 +386,61 @@ func SaveConfig(config *Config, filename string) error {
 	return nil
 }
 
+// normalizeToYAML converts data into YAML bytes according to filename's
+// extension, so the rest of LoadConfig - schema validation, struct
+// population - only ever has to handle YAML. A plain .yaml/.yml file (or
+// an unrecognized/missing extension, preserving prior behavior) passes
+// through unchanged; JSON, TOML, and .env files (see the format package)
+// are decoded to a generic map[string]any and re-marshaled as YAML.
+func normalizeToYAML(filename string, data []byte) ([]byte, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" || ext == ".yaml" || ext == ".yml" {
+		return data, nil
+	}
+
+	fmtImpl, ok := format.ForExtension(ext)
+	if !ok {
+		return data, nil
+	}
+
+	decoded, err := fmtImpl.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(decoded)
+}
+
+// marshalConfig renders config for SaveConfig, in whichever format
+// filename's extension selects. A plain .yaml/.yml file (or an
+// unrecognized/missing extension) marshals directly; other formats
+// round-trip through the same YAML -> map[string]any representation
+// normalizeToYAML builds on load, so the encoded keys match the config's
+// yaml tags (e.g. "config_dir", not Go's "ConfigDir").
+func marshalConfig(config *Config, filename string) ([]byte, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" || ext == ".yaml" || ext == ".yml" {
+		return yaml.Marshal(config)
+	}
+
+	fmtImpl, ok := format.ForExtension(ext)
+	if !ok {
+		return yaml.Marshal(config)
+	}
+
+	yamlData, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic map[string]any
+	if err := yaml.Unmarshal(yamlData, &generic); err != nil {
+		return nil, err
+	}
+
+	return fmtImpl.Encode(generic)
+}
+
 // Validate checks the configuration for common errors
 func (c *Config) Validate() error {
 	if c.Metadata.Name == "" {
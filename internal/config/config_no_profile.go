@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/modules"
 )
 
 // LoadConfigWithoutProfiles loads configuration from a YAML file without profile merging
@@ -18,7 +20,7 @@ func LoadConfigWithoutProfiles(filename string) (*Config, error) {
 	}
 
 	// Validate against schema first
-	if err := ValidateWithSchema(data); err != nil {
+	if err := ValidateWithSchema(data, filename); err != nil {
 		return nil, fmt.Errorf("schema validation failed for %s: %w", filename, err)
 	}
 
@@ -27,19 +29,7 @@ func LoadConfigWithoutProfiles(filename string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
 	}
 
-	// Set default priority for rules
-	for i := range config.Rules {
-		if config.Rules[i].Priority == 0 {
-			config.Rules[i].Priority = 1
-		}
-	}
-
-	// Set default priority for sections
-	for i := range config.Sections {
-		if config.Sections[i].Priority == 0 {
-			config.Sections[i].Priority = 1
-		}
-	}
+	ApplyDefaults(&config)
 
 	// Do NOT merge profiles - this is for testing
 	return &config, nil
@@ -48,6 +38,14 @@ func LoadConfigWithoutProfiles(filename string) (*Config, error) {
 // LoadConfigWithIncludesWithoutProfiles loads a configuration file and resolves all includes without profile merging
 // This is used for testing to maintain backward compatibility
 func LoadConfigWithIncludesWithoutProfiles(filename string) (*Config, error) {
+	return LoadConfigWithIncludesWithoutProfilesAndRefresh(filename, false)
+}
+
+// LoadConfigWithIncludesWithoutProfilesAndRefresh is LoadConfigWithIncludesWithoutProfiles,
+// additionally accepting a refresh flag (the --refresh-includes CLI flag): when true, a
+// remote include whose fetched content no longer matches its pinned lockfile hash is
+// accepted and re-pinned instead of rejected.
+func LoadConfigWithIncludesWithoutProfilesAndRefresh(filename string, refresh bool) (*Config, error) {
 	absPath, err := filepath.Abs(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absolute path for %s: %w", filename, err)
@@ -56,6 +54,7 @@ func LoadConfigWithIncludesWithoutProfiles(filename string) (*Config, error) {
 	loader := &configLoaderNoProfile{
 		visited: make(map[string]bool),
 		baseDir: filepath.Dir(absPath),
+		refresh: refresh,
 	}
 
 	config, err := loader.loadConfig(absPath)
@@ -75,13 +74,83 @@ func LoadConfigWithIncludesWithoutProfiles(filename string) (*Config, error) {
 		}
 	}
 
+	if err := ApplyEnvOverrides(config, "", os.Getenv); err != nil {
+		return nil, err
+	}
+
+	if err := ResolveOutputExtends(config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Resolve(os.Getenv); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
 // configLoaderNoProfile handles recursive include resolution with cycle detection without profiles
 type configLoaderNoProfile struct {
-	visited map[string]bool
-	baseDir string
+	visited  map[string]bool
+	baseDir  string
+	refresh  bool              // when true, a changed remote include is accepted and re-pinned
+	resolver *modules.Resolver // lazily created; resolves git::/git+/http(s):// includes
+	lockfile *modules.Lockfile // lazily loaded from <baseDir>/ai-rulez.lock
+}
+
+// remoteResolver returns the loader's module resolver, creating it (and
+// loading its lockfile) on first use. Unlike the profile-aware loader,
+// which caches fetched includes under the shared per-user cache, this
+// loader caches them project-locally under <baseDir>/.ai-rulez/cache so a
+// repo's test fixtures are self-contained and reproducible across machines.
+func (l *configLoaderNoProfile) remoteResolver() (*modules.Resolver, *modules.Lockfile, error) {
+	if l.resolver == nil {
+		l.resolver = &modules.Resolver{CacheDir: filepath.Join(l.baseDir, ".ai-rulez", "cache")}
+	}
+	if l.lockfile == nil {
+		lockfile, err := modules.LoadLockfile(filepath.Join(l.baseDir, modules.LockfileName))
+		if err != nil {
+			return nil, nil, err
+		}
+		l.lockfile = lockfile
+	}
+	return l.resolver, l.lockfile, nil
+}
+
+// resolveRemoteInclude fetches a git::/git+/http(s):// include (caching it
+// under .ai-rulez/cache) and returns the local path to its content. The
+// cache key - and so the local path the cycle detector sees - is a
+// deterministic function of the source's canonical raw URL, so a glob or
+// alias that resolves back to an already-visited remote source still trips
+// the existing circular-include detector.
+func (l *configLoaderNoProfile) resolveRemoteInclude(rawSource string) (string, error) {
+	src, err := modules.ParseSource(rawSource)
+	if err != nil {
+		return "", err
+	}
+
+	resolver, lockfile, err := l.remoteResolver()
+	if err != nil {
+		return "", err
+	}
+
+	localPath, hash, err := resolver.Resolve(src)
+	if err != nil {
+		return "", err
+	}
+
+	if err := lockfile.Verify(src, localPath); err != nil {
+		if !l.refresh {
+			return "", fmt.Errorf("%w (rerun with --refresh-includes to accept the new content)", err)
+		}
+	}
+	lockfile.Pin(src, src.Ref, hash)
+
+	if err := modules.SaveLockfile(lockfile, filepath.Join(l.baseDir, modules.LockfileName)); err != nil {
+		return "", err
+	}
+
+	return localPath, nil
 }
 
 // loadConfig loads a config file and resolves includes recursively without profile merging
@@ -124,22 +193,40 @@ func (l *configLoaderNoProfile) resolveIncludes(config *Config, baseDir string)
 	allRules = append(allRules, config.Rules...)
 	allSections = append(allSections, config.Sections...)
 
-	// Process each include
-	for _, includePath := range config.Includes {
-		resolvedPath := l.resolvePath(includePath, baseDir)
-
-		if _, err := os.Stat(resolvedPath); os.IsNotExist(err) {
-			return fmt.Errorf("include file not found: %s (resolved to %s)", includePath, resolvedPath)
+	// Process each include; an entry may be a remote git::/git+/http(s)://
+	// source, a literal file, a directory (every *.yaml/*.yml beneath it),
+	// or a doublestar glob, resolved in deterministic sorted order so merge
+	// order is stable across runs.
+	for _, include := range config.Includes {
+		var resolvedPaths []string
+		if modules.IsRemoteSource(include.Path) {
+			resolvedPath, err := l.resolveRemoteInclude(include.Path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve include %s: %w", include.Path, err)
+			}
+			resolvedPaths = []string{resolvedPath}
+		} else {
+			var err error
+			resolvedPaths, err = expandIncludePath(include.Path, baseDir)
+			if err != nil {
+				return err
+			}
 		}
 
-		includedConfig, err := l.loadConfig(resolvedPath)
-		if err != nil {
-			return fmt.Errorf("failed to load include %s: %w", includePath, err)
-		}
+		for _, resolvedPath := range resolvedPaths {
+			includedConfig, err := l.loadConfig(resolvedPath)
+			if err != nil {
+				return fmt.Errorf("failed to load include %s: %w", include.Path, err)
+			}
 
-		// Merge rules and sections from included config
-		allRules = append(allRules, includedConfig.Rules...)
-		allSections = append(allSections, includedConfig.Sections...)
+			if include.Prefix != "" {
+				prefixRulesAndSections(includedConfig, include.Prefix)
+			}
+
+			// Merge rules and sections from included config
+			allRules = append(allRules, includedConfig.Rules...)
+			allSections = append(allSections, includedConfig.Sections...)
+		}
 	}
 
 	// Update config with merged rules and sections, clear includes
@@ -147,31 +234,11 @@ func (l *configLoaderNoProfile) resolveIncludes(config *Config, baseDir string)
 	config.Sections = MergeSections(allSections)
 	config.Includes = nil
 
-	// Ensure all rules have priority (default to 1)
-	for i := range config.Rules {
-		if config.Rules[i].Priority == 0 {
-			config.Rules[i].Priority = 1
-		}
-	}
-
-	// Ensure all sections have priority (default to 1)
-	for i := range config.Sections {
-		if config.Sections[i].Priority == 0 {
-			config.Sections[i].Priority = 1
-		}
-	}
+	ApplyDefaults(config)
 
 	return nil
 }
 
-// resolvePath resolves relative paths against the base directory
-func (*configLoaderNoProfile) resolvePath(includePath, baseDir string) string {
-	if filepath.IsAbs(includePath) {
-		return includePath
-	}
-	return filepath.Join(baseDir, includePath)
-}
-
 // loadLocalOverrides loads local override rules from .local.yaml file (no-profile version)
 func (l *configLoaderNoProfile) loadLocalOverrides(config *Config, filename string) error {
 	// Load the local config file
@@ -180,7 +247,11 @@ func (l *configLoaderNoProfile) loadLocalOverrides(config *Config, filename stri
 		return fmt.Errorf("failed to load local config: %w", err)
 	}
 
-	// Merge rules and sections using ID-based merging
+	// Deep-merge metadata: non-empty local fields overwrite main fields
+	mergeMetadata(&config.Metadata, localConfig.Metadata)
+
+	// Merge outputs by file key, rules by ID/name, sections by ID/title
+	config.Outputs = MergeOutputs(config.Outputs, localConfig.Outputs)
 	config.Rules = MergeRules(config.Rules, localConfig.Rules)
 	config.Sections = MergeSections(config.Sections, localConfig.Sections)
 
@@ -103,6 +103,36 @@ outputs:
 	}
 }
 
+func TestLoadConfig_MalformedYAMLReportsSourcePosition(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+
+	// Malformed YAML - a mapping key appearing where a sequence item was
+	// expected - caught by the very first yaml.Unmarshal in ValidateWithSchema,
+	// before the JSON schema or the Config struct ever see the data.
+	err := os.WriteFile(configFile, []byte(`metadata:
+  name: "Test"
+outputs:
+  - file: "output.md"
+rules:
+  - name: "Rule"
+  content: "Content"
+`), 0o644)
+	require.NoError(t, err)
+
+	_, err = config.LoadConfig(configFile)
+	require.Error(t, err)
+
+	var cerr *config.ConfigError
+	require.ErrorAs(t, err, &cerr)
+	assert.Equal(t, configFile, cerr.File)
+	assert.Greater(t, cerr.Line, 0)
+	assert.Contains(t, cerr.Snippet, "^")
+	assert.Contains(t, err.Error(), cerr.Message)
+}
+
 func TestSaveConfig(t *testing.T) {
 	t.Parallel()
 
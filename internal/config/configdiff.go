@@ -0,0 +1,107 @@
+package config
+
+import "sort"
+
+// ConfigDiff summarizes how one Config differs from another, by rule/
+// section name and output file, for anything that wants to know what
+// changed rather than just that something did - a Watcher subscriber, an
+// editor plugin, or another MCP session reacting to an out-of-band edit.
+type ConfigDiff struct {
+	RulesAdded    []string `json:"rules_added,omitempty"`
+	RulesRemoved  []string `json:"rules_removed,omitempty"`
+	RulesModified []string `json:"rules_modified,omitempty"`
+
+	SectionsAdded    []string `json:"sections_added,omitempty"`
+	SectionsRemoved  []string `json:"sections_removed,omitempty"`
+	SectionsModified []string `json:"sections_modified,omitempty"`
+
+	OutputsAdded    []string `json:"outputs_added,omitempty"`
+	OutputsRemoved  []string `json:"outputs_removed,omitempty"`
+	OutputsModified []string `json:"outputs_modified,omitempty"`
+}
+
+// IsEmpty reports whether the diff found no changes at all.
+func (d ConfigDiff) IsEmpty() bool {
+	return len(d.RulesAdded) == 0 && len(d.RulesRemoved) == 0 && len(d.RulesModified) == 0 &&
+		len(d.SectionsAdded) == 0 && len(d.SectionsRemoved) == 0 && len(d.SectionsModified) == 0 &&
+		len(d.OutputsAdded) == 0 && len(d.OutputsRemoved) == 0 && len(d.OutputsModified) == 0
+}
+
+// DiffConfigs compares prev against next, reporting rules and sections by
+// name/title and outputs by file that were added, removed, or have
+// changed content. prev may be nil, in which case everything in next
+// counts as added.
+func DiffConfigs(prev, next *Config) ConfigDiff {
+	var prevRules []Rule
+	var prevSections []Section
+	var prevOutputs []Output
+	if prev != nil {
+		prevRules, prevSections, prevOutputs = prev.Rules, prev.Sections, prev.Outputs
+	}
+
+	var diff ConfigDiff
+	diff.RulesAdded, diff.RulesRemoved, diff.RulesModified = diffNamed(
+		ruleKeys(prevRules), ruleKeys(next.Rules),
+	)
+	diff.SectionsAdded, diff.SectionsRemoved, diff.SectionsModified = diffNamed(
+		sectionKeys(prevSections), sectionKeys(next.Sections),
+	)
+	diff.OutputsAdded, diff.OutputsRemoved, diff.OutputsModified = diffNamed(
+		outputKeys(prevOutputs), outputKeys(next.Outputs),
+	)
+	return diff
+}
+
+// namedContent maps a rule/section/output's name/title/file to a string
+// that changes whenever anything a reader would care about does, so
+// diffNamed can tell "modified" apart from "untouched" with one
+// comparison instead of a field-by-field check per type.
+type namedContent map[string]string
+
+func ruleKeys(rules []Rule) namedContent {
+	m := make(namedContent, len(rules))
+	for _, rule := range rules {
+		m[rule.Name] = rule.Content
+	}
+	return m
+}
+
+func sectionKeys(sections []Section) namedContent {
+	m := make(namedContent, len(sections))
+	for _, section := range sections {
+		m[section.Title] = section.Content
+	}
+	return m
+}
+
+func outputKeys(outputs []Output) namedContent {
+	m := make(namedContent, len(outputs))
+	for _, output := range outputs {
+		m[output.File] = output.Template
+	}
+	return m
+}
+
+// diffNamed compares prev against next by key, returning sorted,
+// deterministic added/removed/modified slices.
+func diffNamed(prev, next namedContent) (added, removed, modified []string) {
+	for key, content := range next {
+		prevContent, ok := prev[key]
+		if !ok {
+			added = append(added, key)
+			continue
+		}
+		if prevContent != content {
+			modified = append(modified, key)
+		}
+	}
+	for key := range prev {
+		if _, ok := next[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(modified)
+	return added, removed, modified
+}
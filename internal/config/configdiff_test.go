@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestDiffConfigsAddedRemovedModified(t *testing.T) {
+	t.Parallel()
+
+	prev := &config.Config{
+		Rules:   []config.Rule{{Name: "r1", Content: "old"}, {Name: "r2", Content: "keep"}},
+		Outputs: []config.Output{{File: "CLAUDE.md", Template: "old"}},
+	}
+	next := &config.Config{
+		Rules:   []config.Rule{{Name: "r1", Content: "new"}, {Name: "r3", Content: "added"}},
+		Outputs: []config.Output{{File: "CLAUDE.md", Template: "old"}},
+	}
+
+	diff := config.DiffConfigs(prev, next)
+	assert.Equal(t, []string{"r3"}, diff.RulesAdded)
+	assert.Equal(t, []string{"r2"}, diff.RulesRemoved)
+	assert.Equal(t, []string{"r1"}, diff.RulesModified)
+	assert.Empty(t, diff.OutputsAdded)
+	assert.Empty(t, diff.OutputsRemoved)
+	assert.Empty(t, diff.OutputsModified)
+	assert.False(t, diff.IsEmpty())
+}
+
+func TestDiffConfigsNilPrevIsAllAdded(t *testing.T) {
+	t.Parallel()
+
+	next := &config.Config{Rules: []config.Rule{{Name: "r1", Content: "c1"}}}
+	diff := config.DiffConfigs(nil, next)
+	assert.Equal(t, []string{"r1"}, diff.RulesAdded)
+}
+
+func TestDiffConfigsNoChangesIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Rules: []config.Rule{{Name: "r1", Content: "c1"}}}
+	diff := config.DiffConfigs(cfg, cfg)
+	assert.True(t, diff.IsEmpty())
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultEnvPrefix is the env-var prefix ApplyEnvOverrides uses when the
+// caller passes an empty prefix.
+const DefaultEnvPrefix = "AI_RULEZ_"
+
+// ApplyEnvOverrides overlays environment-variable values onto cfg, following
+// viper's SCREAMING_SNAKE_CASE, double-underscore-nested convention. It is
+// the last overlay in the load path - it runs after includes, MergeRules,
+// and any .local.yaml overrides, and before Validate() - so an env var
+// always wins. getenv is injected rather than calling os.Getenv directly so
+// tests can supply a fake lookup without touching the process environment.
+//
+// Supported keys (prefix defaults to DefaultEnvPrefix):
+//
+//	{PREFIX}METADATA__NAME
+//	{PREFIX}METADATA__VERSION
+//	{PREFIX}METADATA__DESCRIPTION
+//	{PREFIX}RULES__<ID>__NAME
+//	{PREFIX}RULES__<ID>__CONTENT
+//	{PREFIX}RULES__<ID>__PRIORITY       (integer)
+//	{PREFIX}RULES__<ID>__WHEN
+//	{PREFIX}OUTPUTS__<index>__RULES     (comma-separated list)
+//	{PREFIX}OUTPUTS__<index>__SECTIONS  (comma-separated list)
+//	{PREFIX}OUTPUTS__<index>__TEMPLATE
+//	{PREFIX}OUTPUTS__<index>__ENGINE
+//	{PREFIX}OUTPUTS__<index>__MODE      (e.g. "skip" to disable an output)
+//
+// <ID> is the rule's ID, uppercased; rules without an ID can't be targeted.
+// The same keys, without the prefix, are accepted by the generate/validate
+// --set flag (see applySetOverrides in main.go) so CI can override either
+// via the environment or ad hoc on the command line.
+func ApplyEnvOverrides(cfg *Config, prefix string, getenv func(string) string) error {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	if v := getenv(prefix + "METADATA__NAME"); v != "" {
+		cfg.Metadata.Name = v
+	}
+	if v := getenv(prefix + "METADATA__VERSION"); v != "" {
+		cfg.Metadata.Version = v
+	}
+	if v := getenv(prefix + "METADATA__DESCRIPTION"); v != "" {
+		cfg.Metadata.Description = v
+	}
+
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if rule.ID == "" {
+			continue
+		}
+		key := prefix + "RULES__" + strings.ToUpper(rule.ID) + "__"
+
+		if v := getenv(key + "NAME"); v != "" {
+			rule.Name = v
+		}
+		if v := getenv(key + "CONTENT"); v != "" {
+			rule.Content = v
+		}
+		if v := getenv(key + "WHEN"); v != "" {
+			rule.When = v
+		}
+		if v := getenv(key + "PRIORITY"); v != "" {
+			priority, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid integer for %sPRIORITY: %w", key, err)
+			}
+			rule.Priority = priority
+		}
+	}
+
+	for i := range cfg.Outputs {
+		output := &cfg.Outputs[i]
+		key := fmt.Sprintf("%sOUTPUTS__%d__", prefix, i)
+
+		if v := getenv(key + "RULES"); v != "" {
+			output.Rules = splitEnvList(v)
+		}
+		if v := getenv(key + "SECTIONS"); v != "" {
+			output.Sections = splitEnvList(v)
+		}
+		if v := getenv(key + "TEMPLATE"); v != "" {
+			output.Template = v
+		}
+		if v := getenv(key + "ENGINE"); v != "" {
+			output.Engine = v
+		}
+		if v := getenv(key + "MODE"); v != "" {
+			output.Mode = v
+		}
+	}
+
+	return nil
+}
+
+// splitEnvList splits a comma-separated env value into a trimmed,
+// empty-entry-free list, e.g. "a, b ,,c" -> ["a", "b", "c"].
+func splitEnvList(v string) []string {
+	parts := strings.Split(v, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Metadata: config.Metadata{Name: "main", Version: "1.0.0"},
+			Outputs: []config.Output{
+				{File: "CLAUDE.md"},
+			},
+			Rules: []config.Rule{
+				{ID: "rule1", Name: "Rule 1", Content: "original content", Priority: 1},
+				{Name: "no id, can't be targeted"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		check  func(t *testing.T, cfg *config.Config)
+		errMsg string
+	}{
+		{
+			name: "overrides metadata fields",
+			env: map[string]string{
+				"AI_RULEZ_METADATA__VERSION":     "2.0.0",
+				"AI_RULEZ_METADATA__DESCRIPTION": "overridden description",
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "main", cfg.Metadata.Name)
+				assert.Equal(t, "2.0.0", cfg.Metadata.Version)
+				assert.Equal(t, "overridden description", cfg.Metadata.Description)
+			},
+		},
+		{
+			name: "overrides rule content and priority by id",
+			env: map[string]string{
+				"AI_RULEZ_RULES__RULE1__CONTENT":  "ENV: overridden content",
+				"AI_RULEZ_RULES__RULE1__PRIORITY": "9",
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "ENV: overridden content", cfg.Rules[0].Content)
+				assert.Equal(t, 9, cfg.Rules[0].Priority)
+			},
+		},
+		{
+			name: "rule without an id is left untouched",
+			env: map[string]string{
+				"AI_RULEZ_RULES____NAME": "shouldn't apply",
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "no id, can't be targeted", cfg.Rules[1].Name)
+			},
+		},
+		{
+			name: "overrides output rules and sections as comma-separated lists",
+			env: map[string]string{
+				"AI_RULEZ_OUTPUTS__0__RULES":    "rule1, rule2 ,,rule3",
+				"AI_RULEZ_OUTPUTS__0__SECTIONS": "sec1,sec2",
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, []string{"rule1", "rule2", "rule3"}, cfg.Outputs[0].Rules)
+				assert.Equal(t, []string{"sec1", "sec2"}, cfg.Outputs[0].Sections)
+			},
+		},
+		{
+			name: "overrides output template, engine and mode",
+			env: map[string]string{
+				"AI_RULEZ_OUTPUTS__0__TEMPLATE": "@templates/ci.tmpl",
+				"AI_RULEZ_OUTPUTS__0__ENGINE":   "handlebars",
+				"AI_RULEZ_OUTPUTS__0__MODE":     "skip",
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "@templates/ci.tmpl", cfg.Outputs[0].Template)
+				assert.Equal(t, "handlebars", cfg.Outputs[0].Engine)
+				assert.Equal(t, "skip", cfg.Outputs[0].Mode)
+			},
+		},
+		{
+			name: "non-integer priority is an error",
+			env: map[string]string{
+				"AI_RULEZ_RULES__RULE1__PRIORITY": "not-a-number",
+			},
+			errMsg: "invalid integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newConfig()
+			getenv := func(key string) string { return tt.env[key] }
+
+			err := config.ApplyEnvOverrides(cfg, "", getenv)
+
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestApplyEnvOverridesCustomPrefix(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Metadata: config.Metadata{Name: "main"}}
+	getenv := func(key string) string {
+		if key == "MYAPP_METADATA__NAME" {
+			return "renamed"
+		}
+		return ""
+	}
+
+	require.NoError(t, config.ApplyEnvOverrides(cfg, "MYAPP_", getenv))
+	assert.Equal(t, "renamed", cfg.Metadata.Name)
+}
@@ -0,0 +1,98 @@
+package config
+
+import "fmt"
+
+// ResolveOutputExtends resolves each output's `extends:` field against its
+// sibling outputs, inheriting Template, Engine, Rules, and Sections from the
+// named parent wherever the child leaves those fields unset. Resolution runs
+// after includes (and .local.yaml overrides) have been merged, so a chain
+// may span outputs pulled in from any of those sources. Extends is cleared
+// once resolved, mirroring how Includes is cleared after merging.
+func ResolveOutputExtends(cfg *Config) error {
+	byFile := make(map[string]*Output, len(cfg.Outputs))
+	for i := range cfg.Outputs {
+		byFile[cfg.Outputs[i].File] = &cfg.Outputs[i]
+	}
+
+	resolved := make(map[string]bool, len(cfg.Outputs))
+	for i := range cfg.Outputs {
+		if err := resolveOutputExtends(cfg.Outputs[i].File, byFile, resolved, nil); err != nil {
+			return err
+		}
+	}
+
+	// Fall back to the project-wide default for any output that still has
+	// no delimiters of its own (or inherited via extends).
+	for i := range cfg.Outputs {
+		if len(cfg.Outputs[i].Delims) == 0 {
+			cfg.Outputs[i].Delims = cfg.Delims
+		}
+	}
+
+	return nil
+}
+
+// resolveOutputExtends walks the extends chain for the output named file,
+// resolving parents depth-first and detecting cycles via the in-progress path.
+func resolveOutputExtends(file string, byFile map[string]*Output, resolved map[string]bool, path []string) error {
+	if resolved[file] {
+		return nil
+	}
+
+	for _, seen := range path {
+		if seen == file {
+			return fmt.Errorf("circular output extends detected: %s", appendChain(path, file))
+		}
+	}
+
+	output, exists := byFile[file]
+	if !exists {
+		return fmt.Errorf("output %q not found", file)
+	}
+
+	if output.Extends == "" {
+		resolved[file] = true
+		return nil
+	}
+
+	parent, exists := byFile[output.Extends]
+	if !exists {
+		return fmt.Errorf("output %q extends unknown output %q", file, output.Extends)
+	}
+
+	if err := resolveOutputExtends(parent.File, byFile, resolved, append(path, file)); err != nil {
+		return err
+	}
+
+	if output.Template == "" {
+		output.Template = parent.Template
+	}
+	if output.Engine == "" {
+		output.Engine = parent.Engine
+	}
+	if len(output.Rules) == 0 {
+		output.Rules = parent.Rules
+	}
+	if len(output.Sections) == 0 {
+		output.Sections = parent.Sections
+	}
+	if len(output.Delims) == 0 {
+		output.Delims = parent.Delims
+	}
+
+	output.Extends = ""
+	resolved[file] = true
+	return nil
+}
+
+// appendChain renders a cycle's path for error messages, e.g. "a -> b -> a".
+func appendChain(path []string, closing string) string {
+	chain := ""
+	for _, p := range append(path, closing) {
+		if chain != "" {
+			chain += " -> "
+		}
+		chain += p
+	}
+	return chain
+}
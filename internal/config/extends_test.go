@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestResolveOutputExtends(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		wantErr string
+		check   func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name: "child inherits template and filters from parent",
+			cfg: &config.Config{
+				Outputs: []config.Output{
+					{File: "CLAUDE.md", Template: "@templates/base.md", Rules: []string{"core"}},
+					{File: "CLAUDE.review.md", Extends: "CLAUDE.md", Rules: []string{"review"}},
+					{File: "CLAUDE.min.md", Extends: "CLAUDE.md", Template: "@templates/min.md"},
+				},
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				review := cfg.Outputs[1]
+				assert.Equal(t, "@templates/base.md", review.Template)
+				assert.Equal(t, []string{"review"}, review.Rules)
+				assert.Empty(t, review.Extends)
+
+				min := cfg.Outputs[2]
+				assert.Equal(t, "@templates/min.md", min.Template)
+				assert.Equal(t, []string{"core"}, min.Rules)
+			},
+		},
+		{
+			name: "circular extends is rejected",
+			cfg: &config.Config{
+				Outputs: []config.Output{
+					{File: "a.md", Extends: "b.md"},
+					{File: "b.md", Extends: "a.md"},
+				},
+			},
+			wantErr: "circular output extends",
+		},
+		{
+			name: "extends unknown output is rejected",
+			cfg: &config.Config{
+				Outputs: []config.Output{
+					{File: "a.md", Extends: "missing.md"},
+				},
+			},
+			wantErr: "extends unknown output",
+		},
+		{
+			name: "child inherits parent delims, grandchild keeps its own",
+			cfg: &config.Config{
+				Outputs: []config.Output{
+					{File: "base.md", Delims: []string{"<%", "%>"}},
+					{File: "child.md", Extends: "base.md"},
+					{File: "grandchild.md", Extends: "child.md", Delims: []string{"[[", "]]"}},
+				},
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, []string{"<%", "%>"}, cfg.Outputs[1].Delims)
+				assert.Equal(t, []string{"[[", "]]"}, cfg.Outputs[2].Delims)
+			},
+		},
+		{
+			name: "output with no delims falls back to the top-level default",
+			cfg: &config.Config{
+				Delims: []string{"<%", "%>"},
+				Outputs: []config.Output{
+					{File: "a.md"},
+					{File: "b.md", Delims: []string{"[[", "]]"}},
+				},
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, []string{"<%", "%>"}, cfg.Outputs[0].Delims)
+				assert.Equal(t, []string{"[[", "]]"}, cfg.Outputs[1].Delims)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := config.ResolveOutputExtends(tt.cfg)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, tt.cfg)
+		})
+	}
+}
@@ -11,14 +11,6 @@ import (
 // and traversing up to the root. Returns the path to the first config file found.
 // Supports: ai-rulez.yaml, .ai-rulez.yaml, ai_rulez.yaml, .ai_rulez.yaml (and .yml variants)
 func FindConfigFile(startDir string) (string, error) {
-	// Config file names to search for (in priority order)
-	configNames := []string{
-		".ai-rulez.yaml", ".ai-rulez.yml",
-		"ai-rulez.yaml", "ai-rulez.yml",
-		".ai_rulez.yaml", ".ai_rulez.yml",
-		"ai_rulez.yaml", "ai_rulez.yml",
-	}
-
 	// Start from the given directory
 	dir, err := filepath.Abs(startDir)
 	if err != nil {
@@ -32,7 +24,7 @@ func FindConfigFile(startDir string) (string, error) {
 		visited[dir] = true
 
 		// Check for each config file name
-		for _, name := range configNames {
+		for _, name := range configFileSearchOrder {
 			configPath := filepath.Join(dir, name)
 			if _, err := os.Stat(configPath); err == nil {
 				return configPath, nil
@@ -51,43 +43,43 @@ func FindConfigFile(startDir string) (string, error) {
 	return "", errors.New("no configuration file found. Create an 'ai-rulez.yaml', '.ai-rulez.yaml', 'ai_rulez.yaml', or '.ai_rulez.yaml' file in your project")
 }
 
+// configFileSearchOrder is the priority order FindConfigFile and
+// FindConfigFileFS search config file names in. YAML comes first so an
+// all-format project still resolves to its YAML file by default; see the
+// format package for the JSON/TOML/.env decoders these extensions select.
+var configFileSearchOrder = []string{
+	".ai-rulez.yaml", ".ai-rulez.yml",
+	"ai-rulez.yaml", "ai-rulez.yml",
+	".ai_rulez.yaml", ".ai_rulez.yml",
+	"ai_rulez.yaml", "ai_rulez.yml",
+	".ai-rulez.json", "ai-rulez.json",
+	".ai_rulez.json", "ai_rulez.json",
+	".ai-rulez.toml", "ai-rulez.toml",
+	".ai_rulez.toml", "ai_rulez.toml",
+	".ai-rulez.env", "ai-rulez.env",
+	".ai_rulez.env", "ai_rulez.env",
+}
+
+// configFileNames are the config file names FindAllConfigFiles and
+// FindAllConfigFilesWithExcludes look for.
+// Supports: ai-rulez/ai_rulez, each as .yaml/.yml/.json/.toml/.env, with or
+// without a leading dot.
+var configFileNames = map[string]bool{
+	".ai-rulez.yaml": true, ".ai-rulez.yml": true,
+	"ai-rulez.yaml": true, "ai-rulez.yml": true,
+	".ai_rulez.yaml": true, ".ai_rulez.yml": true,
+	"ai_rulez.yaml": true, "ai_rulez.yml": true,
+	".ai-rulez.json": true, "ai-rulez.json": true,
+	".ai_rulez.json": true, "ai_rulez.json": true,
+	".ai-rulez.toml": true, "ai-rulez.toml": true,
+	".ai_rulez.toml": true, "ai_rulez.toml": true,
+	".ai-rulez.env": true, "ai-rulez.env": true,
+	".ai_rulez.env": true, "ai_rulez.env": true,
+}
+
 // FindAllConfigFiles recursively finds all config files
 // starting from the given directory.
 // Supports: ai-rulez.yaml, .ai-rulez.yaml, ai_rulez.yaml, .ai_rulez.yaml (and .yml variants)
 func FindAllConfigFiles(rootDir string) ([]string, error) {
-	var configs []string
-	configNames := map[string]bool{
-		".ai-rulez.yaml": true, ".ai-rulez.yml": true,
-		"ai-rulez.yaml": true, "ai-rulez.yml": true,
-		".ai_rulez.yaml": true, ".ai_rulez.yml": true,
-		"ai_rulez.yaml": true, "ai_rulez.yml": true,
-	}
-
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip hidden directories (except .ai-rulez.yaml itself)
-		if info.IsDir() && filepath.Base(path) != "." && filepath.Base(path)[0] == '.' {
-			return filepath.SkipDir
-		}
-
-		// Check if this is a config file
-		if !info.IsDir() && configNames[filepath.Base(path)] {
-			configs = append(configs, path)
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to walk directory tree: %w", err)
-	}
-
-	if len(configs) == 0 {
-		return nil, fmt.Errorf("no configuration files found in %s", rootDir)
-	}
-
-	return configs, nil
+	return FindAllConfigFilesWithExcludes(rootDir, nil)
 }
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the gitignore-syntax file FindAllConfigFilesWithExcludes
+// reads exclude patterns from, in addition to any passed explicitly.
+const IgnoreFileName = ".ai-rulezignore"
+
+// excludePattern is a single compiled gitignore-style exclude rule. This
+// mirrors internal/gitignore's PatternSet, kept as its own small copy here
+// rather than imported: internal/gitignore already imports internal/config,
+// so importing it back would create a cycle.
+type excludePattern struct {
+	negate   bool
+	dirOnly  bool
+	anchored bool
+	re       *regexp.Regexp
+}
+
+// excludeMatcher is a compiled, ordered set of excludePatterns. Later
+// patterns take precedence, and a negated pattern (`!pattern`) re-includes
+// a path excluded by an earlier rule, following git's own precedence rules.
+type excludeMatcher struct {
+	patterns []excludePattern
+}
+
+// compileExcludePatterns compiles raw gitignore-style lines into an
+// excludeMatcher.
+func compileExcludePatterns(lines []string) (*excludeMatcher, error) {
+	m := &excludeMatcher{patterns: make([]excludePattern, 0, len(lines))}
+	for _, line := range lines {
+		p, err := compileExcludePattern(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", line, err)
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m, nil
+}
+
+// compileExcludePattern parses a single gitignore-style line into an excludePattern.
+func compileExcludePattern(raw string) (excludePattern, error) {
+	s := raw
+
+	negate := false
+	if strings.HasPrefix(s, "!") {
+		negate = true
+		s = s[1:]
+	}
+
+	dirOnly := strings.HasSuffix(s, "/")
+	if dirOnly {
+		s = strings.TrimSuffix(s, "/")
+	}
+
+	anchored := strings.HasPrefix(s, "/")
+	s = strings.TrimPrefix(s, "/")
+	// A pattern containing a non-trailing slash is anchored to the root
+	// rather than matching at any depth.
+	anchored = anchored || strings.Contains(s, "/")
+
+	re, err := globToRegexp(s)
+	if err != nil {
+		return excludePattern{}, err
+	}
+
+	return excludePattern{negate: negate, dirOnly: dirOnly, anchored: anchored, re: re}, nil
+}
+
+// match reports whether relPath (slash-separated, relative to the walk
+// root) is excluded, applying negation precedence: the last matching
+// pattern wins.
+func (m *excludeMatcher) match(relPath string) bool {
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "/")
+
+	excluded := false
+	for _, p := range m.patterns {
+		if p.appliesTo(relPath) {
+			excluded = !p.negate
+		}
+	}
+	return excluded
+}
+
+// matchDir reports whether relPath (a directory) should be pruned entirely,
+// by probing whether a file directly beneath it would be excluded - this
+// lets directory-shaped patterns like "**/node_modules/**" skip the whole
+// subtree instead of being checked file by file.
+func (m *excludeMatcher) matchDir(relPath string) bool {
+	if m.match(relPath) {
+		return true
+	}
+	return m.match(relPath + "/.ai-rulez-probe")
+}
+
+func (p excludePattern) appliesTo(path string) bool {
+	if p.anchored {
+		return p.re.MatchString(path)
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if p.re.MatchString(segment) {
+			return true
+		}
+	}
+	return p.re.MatchString(path)
+}
+
+// readIgnoreFileLines reads all non-empty, non-comment lines from path. A
+// missing file yields a nil, non-error slice.
+func readIgnoreFileLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// loadIgnoreFileLines collects IgnoreFileName's lines from rootDir and every
+// ancestor directory up to the filesystem root, so a monorepo's top-level
+// .ai-rulezignore applies even when FindAllConfigFilesWithExcludes is run
+// from a subdirectory. Returned root-to-leaf, so a leaf directory's entries
+// are checked last (and so take precedence on conflicting negation).
+func loadIgnoreFileLines(rootDir string) ([]string, error) {
+	dir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	visited := make(map[string]bool)
+	for !visited[dir] {
+		visited[dir] = true
+		dirs = append(dirs, dir)
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	var lines []string
+	for i := len(dirs) - 1; i >= 0; i-- {
+		fileLines, err := readIgnoreFileLines(filepath.Join(dirs[i], IgnoreFileName))
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fileLines...)
+	}
+
+	return lines, nil
+}
+
+// newExcludeMatcher compiles excludes (explicit glob patterns, e.g.
+// "**/node_modules/**") together with any patterns discovered by
+// loadIgnoreFileLines.
+func newExcludeMatcher(rootDir string, excludes []string) (*excludeMatcher, error) {
+	ignoreFileLines, err := loadIgnoreFileLines(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	return compileExcludePatterns(append(ignoreFileLines, excludes...))
+}
+
+// FindAllConfigFilesWithExcludes is FindAllConfigFiles, additionally pruning
+// any path matching excludes (gitignore-style globs, e.g.
+// "**/node_modules/**", "**/vendor/**") or a .ai-rulezignore file found in
+// rootDir or any ancestor directory. Monorepos commonly vendor third-party
+// trees that ship their own ai-rulez.yaml; without this, those are picked up
+// as if they belonged to the project.
+func FindAllConfigFilesWithExcludes(rootDir string, excludes []string) ([]string, error) {
+	matcher, err := newExcludeMatcher(rootDir, excludes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile excludes: %w", err)
+	}
+
+	var configs []string
+	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, relErr := filepath.Rel(rootDir, path)
+		if relErr != nil {
+			return relErr
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if info.IsDir() {
+			if filepath.Base(path) != "." && filepath.Base(path)[0] == '.' {
+				return filepath.SkipDir
+			}
+			if relPath != "." && matcher.matchDir(relPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if configFileNames[filepath.Base(path)] && !matcher.match(relPath) {
+			configs = append(configs, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory tree: %w", err)
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no configuration files found in %s", rootDir)
+	}
+
+	return configs, nil
+}
@@ -240,3 +240,88 @@ func TestFindAllConfigFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestFindAllConfigFilesWithExcludes(t *testing.T) {
+	t.Parallel()
+
+	writeConfigs := func(t *testing.T, root string, paths ...string) {
+		t.Helper()
+		for _, p := range paths {
+			full := filepath.Join(root, p)
+			require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+			require.NoError(t, os.WriteFile(full, []byte("test"), 0644))
+		}
+	}
+
+	t.Run("explicit exclude prunes matching subtree", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		writeConfigs(t, tmpDir,
+			"ai-rulez.yaml",
+			"vendor/some-dep/ai-rulez.yaml",
+			"project/ai-rulez.yaml",
+		)
+
+		configs, err := config.FindAllConfigFilesWithExcludes(tmpDir, []string{"**/vendor/**"})
+		require.NoError(t, err)
+		assert.Len(t, configs, 2)
+		for _, c := range configs {
+			assert.NotContains(t, c, "vendor")
+		}
+	})
+
+	t.Run("no excludes behaves like FindAllConfigFiles", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		writeConfigs(t, tmpDir, "ai-rulez.yaml", "project/ai-rulez.yaml")
+
+		configs, err := config.FindAllConfigFilesWithExcludes(tmpDir, nil)
+		require.NoError(t, err)
+		assert.Len(t, configs, 2)
+	})
+
+	t.Run("ai-rulezignore file in root is honored", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		writeConfigs(t, tmpDir,
+			"ai-rulez.yaml",
+			"node_modules/some-dep/ai-rulez.yaml",
+		)
+		require.NoError(t, os.WriteFile(
+			filepath.Join(tmpDir, config.IgnoreFileName), []byte("node_modules/\n"), 0644))
+
+		configs, err := config.FindAllConfigFilesWithExcludes(tmpDir, nil)
+		require.NoError(t, err)
+		assert.Len(t, configs, 1)
+	})
+
+	t.Run("ai-rulezignore in an ancestor directory applies to subdirectory searches", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		writeConfigs(t, tmpDir,
+			"sub/ai-rulez.yaml",
+			"sub/testdata/ai-rulez.yaml",
+		)
+		require.NoError(t, os.WriteFile(
+			filepath.Join(tmpDir, config.IgnoreFileName), []byte("**/testdata/**\n"), 0644))
+
+		configs, err := config.FindAllConfigFilesWithExcludes(filepath.Join(tmpDir, "sub"), nil)
+		require.NoError(t, err)
+		assert.Len(t, configs, 1)
+	})
+
+	t.Run("excluding everything reports the same not-found error", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		writeConfigs(t, tmpDir, "ai-rulez.yaml")
+
+		_, err := config.FindAllConfigFilesWithExcludes(tmpDir, []string{"**/*.yaml"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no configuration files found")
+	})
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ForEach kinds for ForEachSpec.Kind.
+const (
+	ForEachRules    = "rules"
+	ForEachSections = "sections"
+)
+
+// ForEachSpec is the parsed form of an Output.ForEach expression.
+type ForEachSpec struct {
+	// Kind is ForEachRules or ForEachSections.
+	Kind string
+	// Tag, when non-empty, restricts iteration to items whose Tags include
+	// this value.
+	Tag string
+}
+
+// ParseForEach parses an Output.ForEach expression of the form:
+//
+//	rules
+//	sections
+//	rules where tag == "security"
+//	sections where tag == "security"
+//
+// The quotes around the tag value are required and may use either " or '.
+func ParseForEach(expr string) (*ForEachSpec, error) {
+	expr = strings.TrimSpace(expr)
+
+	kind, rest, hasWhere := strings.Cut(expr, " where ")
+	kind = strings.TrimSpace(kind)
+
+	if kind != ForEachRules && kind != ForEachSections {
+		return nil, fmt.Errorf("for_each: unknown kind %q, expected %q or %q", kind, ForEachRules, ForEachSections)
+	}
+
+	spec := &ForEachSpec{Kind: kind}
+	if !hasWhere {
+		return spec, nil
+	}
+
+	tag, err := parseForEachTagCondition(rest)
+	if err != nil {
+		return nil, fmt.Errorf("for_each: %w", err)
+	}
+	spec.Tag = tag
+
+	return spec, nil
+}
+
+// parseForEachTagCondition parses the `tag == "value"` clause following a
+// `where` keyword, returning the unquoted value.
+func parseForEachTagCondition(cond string) (string, error) {
+	cond = strings.TrimSpace(cond)
+
+	field, value, ok := strings.Cut(cond, "==")
+	if !ok || strings.TrimSpace(field) != "tag" {
+		return "", fmt.Errorf("invalid where clause %q, expected `tag == \"value\"`", cond)
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return "", fmt.Errorf("invalid tag value %q, expected a quoted string", value)
+	}
+
+	quote := value[0]
+	if (quote != '"' && quote != '\'') || value[len(value)-1] != quote {
+		return "", fmt.Errorf("invalid tag value %q, expected a quoted string", value)
+	}
+
+	return value[1 : len(value)-1], nil
+}
+
+// hasTag reports whether tags contains tag.
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterRulesByTag returns the rules whose Tags include tag, or all rules when tag is empty.
+func FilterRulesByTag(rules []Rule, tag string) []Rule {
+	if tag == "" {
+		return rules
+	}
+
+	var filtered []Rule
+	for _, rule := range rules {
+		if hasTag(rule.Tags, tag) {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered
+}
+
+// FilterSectionsByTag returns the sections whose Tags include tag, or all sections when tag is empty.
+func FilterSectionsByTag(sections []Section, tag string) []Section {
+	if tag == "" {
+		return sections
+	}
+
+	var filtered []Section
+	for _, section := range sections {
+		if hasTag(section.Tags, tag) {
+			filtered = append(filtered, section)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,105 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestParseForEach(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    *config.ForEachSpec
+		wantErr string
+	}{
+		{
+			name: "rules",
+			expr: "rules",
+			want: &config.ForEachSpec{Kind: config.ForEachRules},
+		},
+		{
+			name: "sections",
+			expr: "sections",
+			want: &config.ForEachSpec{Kind: config.ForEachSections},
+		},
+		{
+			name: "rules filtered by tag, double quotes",
+			expr: `rules where tag == "security"`,
+			want: &config.ForEachSpec{Kind: config.ForEachRules, Tag: "security"},
+		},
+		{
+			name: "sections filtered by tag, single quotes",
+			expr: `sections where tag == 'security'`,
+			want: &config.ForEachSpec{Kind: config.ForEachSections, Tag: "security"},
+		},
+		{
+			name: "surrounding whitespace is trimmed",
+			expr: `  rules where tag == "security"  `,
+			want: &config.ForEachSpec{Kind: config.ForEachRules, Tag: "security"},
+		},
+		{
+			name:    "unknown kind",
+			expr:    "widgets",
+			wantErr: `unknown kind "widgets"`,
+		},
+		{
+			name:    "malformed where clause",
+			expr:    "rules where tag = security",
+			wantErr: "invalid where clause",
+		},
+		{
+			name:    "unquoted tag value",
+			expr:    "rules where tag == security",
+			wantErr: "expected a quoted string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := config.ParseForEach(tt.expr)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilterRulesByTag(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Name: "Auth Rule", Tags: []string{"security"}},
+		{Name: "Style Rule", Tags: []string{"style"}},
+	}
+
+	assert.Equal(t, rules, config.FilterRulesByTag(rules, ""))
+	assert.Equal(t, []config.Rule{rules[0]}, config.FilterRulesByTag(rules, "security"))
+	assert.Empty(t, config.FilterRulesByTag(rules, "missing"))
+}
+
+func TestFilterSectionsByTag(t *testing.T) {
+	t.Parallel()
+
+	sections := []config.Section{
+		{Title: "Security", Tags: []string{"security"}},
+		{Title: "Style", Tags: []string{"style"}},
+	}
+
+	assert.Equal(t, sections, config.FilterSectionsByTag(sections, ""))
+	assert.Equal(t, []config.Section{sections[0]}, config.FilterSectionsByTag(sections, "security"))
+	assert.Empty(t, config.FilterSectionsByTag(sections, "missing"))
+}
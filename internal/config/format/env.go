@@ -0,0 +1,158 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envFormat decodes a flat `.env`-style KEY=VALUE file into a generic
+// config map, using the same "__"-nested-key convention as
+// config.ApplyEnvOverrides: a double underscore descends into a nested
+// object, and a segment that's entirely digits is an array index, e.g.:
+//
+//	METADATA__NAME=My Project
+//	RULES__0__NAME=Rule 1
+//	RULES__0__CONTENT=Some content
+//	RULES__1__NAME=Rule 2
+//	RULES__1__CONTENT=More content
+//
+// decodes to {"metadata": {"name": "My Project"}, "rules": [{"name": "Rule 1", "content": "Some content"}, ...]}.
+// Blank lines and lines starting with "#" are ignored; values may be
+// wrapped in matching single or double quotes.
+type envFormat struct{}
+
+func (envFormat) Decode(data []byte) (map[string]any, error) {
+	root := map[string]any{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid .env line (expected KEY=VALUE): %q", line)
+		}
+
+		segments := strings.Split(strings.ToLower(strings.TrimSpace(key)), "__")
+		setNestedValue(root, segments, unquoteEnvValue(strings.TrimSpace(value)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return arrayifyChildren(root), nil
+}
+
+func (envFormat) Encode(v map[string]any) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeEnvKeys(&b, "", v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// setNestedValue assigns value at the path described by segments into
+// root, creating intermediate map[string]any levels as needed. Numeric
+// segments (e.g. "0") are kept as map keys for now; arrayifyChildren
+// converts any all-numeric-keyed map into a slice afterwards, once every
+// line has been applied.
+func setNestedValue(root map[string]any, segments []string, value string) {
+	node := root
+	for _, seg := range segments[:len(segments)-1] {
+		next, ok := node[seg].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			node[seg] = next
+		}
+		node = next
+	}
+	node[segments[len(segments)-1]] = value
+}
+
+// arrayifyChildren walks m, replacing any child map[string]any whose keys
+// are all non-negative integers with a []any ordered by index.
+func arrayifyChildren(m map[string]any) map[string]any {
+	for k, v := range m {
+		if child, ok := v.(map[string]any); ok {
+			m[k] = arrayify(child)
+		}
+	}
+	return m
+}
+
+// arrayify converts m into a []any (ordered by key, parsed as an integer)
+// if every key is a valid non-negative integer, recursing into map-valued
+// entries either way.
+func arrayify(m map[string]any) any {
+	indices := make([]int, 0, len(m))
+	for k := range m {
+		idx, err := strconv.Atoi(k)
+		if err != nil || idx < 0 {
+			return arrayifyChildren(m)
+		}
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+
+	arr := make([]any, len(indices))
+	for i, idx := range indices {
+		v := m[strconv.Itoa(idx)]
+		if child, ok := v.(map[string]any); ok {
+			v = arrayify(child)
+		}
+		arr[i] = v
+	}
+	return arr
+}
+
+// writeEnvKeys walks v, writing one UPPER__NESTED__KEY=value line per leaf.
+func writeEnvKeys(b *bytes.Buffer, prefix string, v any) error {
+	switch x := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := writeEnvKeys(b, joinEnvKey(prefix, k), x[k]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range x {
+			if err := writeEnvKeys(b, joinEnvKey(prefix, strconv.Itoa(i)), item); err != nil {
+				return err
+			}
+		}
+	default:
+		fmt.Fprintf(b, "%s=%v\n", strings.ToUpper(prefix), x)
+	}
+	return nil
+}
+
+func joinEnvKey(prefix, segment string) string {
+	if prefix == "" {
+		return segment
+	}
+	return prefix + "__" + segment
+}
+
+// unquoteEnvValue strips a single matching pair of surrounding quotes, as a
+// shell or dotenv loader would.
+func unquoteEnvValue(v string) string {
+	if len(v) >= 2 {
+		first, last := v[0], v[len(v)-1]
+		if (first == '"' && last == '"') || (first == '\'' && last == '\'') {
+			return v[1 : len(v)-1]
+		}
+	}
+	return v
+}
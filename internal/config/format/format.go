@@ -0,0 +1,47 @@
+// Package format decodes and encodes config files in whichever on-disk
+// format they're written in (YAML, JSON, TOML, or a flat .env), normalizing
+// all of them to the same JSON-compatible map[string]any representation the
+// config package already uses for schema validation (see
+// config.ConvertYAMLToJSON).
+package format
+
+import "path/filepath"
+
+// Decoder parses raw config bytes into a generic, JSON-compatible
+// map[string]any representation.
+type Decoder interface {
+	Decode(data []byte) (map[string]any, error)
+}
+
+// Encoder renders a generic map[string]any back into raw config bytes.
+type Encoder interface {
+	Encode(v map[string]any) ([]byte, error)
+}
+
+// Format pairs a Decoder and Encoder for one file format.
+type Format interface {
+	Decoder
+	Encoder
+}
+
+// registry maps a file extension (as returned by filepath.Ext, e.g. ".yaml")
+// to the Format that handles it.
+var registry = map[string]Format{
+	".yaml": yamlFormat{},
+	".yml":  yamlFormat{},
+	".json": jsonFormat{},
+	".toml": tomlFormat{},
+	".env":  envFormat{},
+}
+
+// ForExtension returns the Format registered for ext (as returned by
+// filepath.Ext, e.g. ".yaml"), and false if ext isn't recognized.
+func ForExtension(ext string) (Format, bool) {
+	f, ok := registry[ext]
+	return f, ok
+}
+
+// ForFilename is ForExtension(filepath.Ext(filename)).
+func ForFilename(filename string) (Format, bool) {
+	return ForExtension(filepath.Ext(filename))
+}
@@ -0,0 +1,128 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config/format"
+)
+
+func TestForExtension_UnknownReturnsFalse(t *testing.T) {
+	t.Parallel()
+
+	_, ok := format.ForExtension(".ini")
+	assert.False(t, ok)
+}
+
+func TestYAMLFormat_RoundTrips(t *testing.T) {
+	t.Parallel()
+
+	f, ok := format.ForExtension(".yaml")
+	require.True(t, ok)
+
+	decoded, err := f.Decode([]byte("metadata:\n  name: Test\noutputs:\n  - file: CLAUDE.md\n"))
+	require.NoError(t, err)
+
+	metadata, ok := decoded["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Test", metadata["name"])
+
+	encoded, err := f.Encode(decoded)
+	require.NoError(t, err)
+
+	roundTripped, err := f.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, decoded, roundTripped)
+}
+
+func TestJSONFormat_Decode(t *testing.T) {
+	t.Parallel()
+
+	f, ok := format.ForFilename("ai_rulez.json")
+	require.True(t, ok)
+
+	decoded, err := f.Decode([]byte(`{"metadata": {"name": "Test"}, "outputs": [{"file": "CLAUDE.md"}]}`))
+	require.NoError(t, err)
+
+	metadata, ok := decoded["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Test", metadata["name"])
+}
+
+func TestTOMLFormat_Decode(t *testing.T) {
+	t.Parallel()
+
+	f, ok := format.ForFilename("ai_rulez.toml")
+	require.True(t, ok)
+
+	decoded, err := f.Decode([]byte(`
+[metadata]
+name = "Test"
+
+[[rules]]
+name = "Rule 1"
+content = "content"
+`))
+	require.NoError(t, err)
+
+	metadata, ok := decoded["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Test", metadata["name"])
+	assert.Len(t, decoded["rules"], 1)
+}
+
+func TestEnvFormat_DecodeBuildsNestedRulesArray(t *testing.T) {
+	t.Parallel()
+
+	f, ok := format.ForFilename("ai_rulez.env")
+	require.True(t, ok)
+
+	decoded, err := f.Decode([]byte(`
+# a comment
+METADATA__NAME="My Project"
+RULES__0__NAME=Rule 1
+RULES__0__CONTENT=Some content
+RULES__1__NAME=Rule 2
+RULES__1__CONTENT=More content
+`))
+	require.NoError(t, err)
+
+	metadata, ok := decoded["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "My Project", metadata["name"])
+
+	rules, ok := decoded["rules"].([]any)
+	require.True(t, ok)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "Rule 1", rules[0].(map[string]any)["name"])
+	assert.Equal(t, "Rule 2", rules[1].(map[string]any)["name"])
+}
+
+func TestEnvFormat_DecodeRejectsMalformedLine(t *testing.T) {
+	t.Parallel()
+
+	f, _ := format.ForExtension(".env")
+	_, err := f.Decode([]byte("not a key value line"))
+	assert.Error(t, err)
+}
+
+func TestEnvFormat_EncodeDecodeRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	f, _ := format.ForExtension(".env")
+	original := map[string]any{
+		"metadata": map[string]any{"name": "My Project"},
+		"rules": []any{
+			map[string]any{"name": "Rule 1", "content": "content"},
+		},
+	}
+
+	encoded, err := f.Encode(original)
+	require.NoError(t, err)
+
+	decoded, err := f.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
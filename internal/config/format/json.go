@@ -0,0 +1,21 @@
+package format
+
+import "encoding/json"
+
+// jsonFormat decodes and encodes plain JSON.
+type jsonFormat struct{}
+
+func (jsonFormat) Decode(data []byte) (map[string]any, error) {
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = map[string]any{}
+	}
+	return v, nil
+}
+
+func (jsonFormat) Encode(v map[string]any) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
@@ -0,0 +1,29 @@
+package format
+
+import (
+	"bytes"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlFormat decodes and encodes TOML.
+type tomlFormat struct{}
+
+func (tomlFormat) Decode(data []byte) (map[string]any, error) {
+	var v map[string]any
+	if err := toml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = map[string]any{}
+	}
+	return v, nil
+}
+
+func (tomlFormat) Encode(v map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,22 @@
+package format
+
+import "gopkg.in/yaml.v3"
+
+// yamlFormat decodes and encodes plain YAML, the config package's native
+// format.
+type yamlFormat struct{}
+
+func (yamlFormat) Decode(data []byte) (map[string]any, error) {
+	var v map[string]any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = map[string]any{}
+	}
+	return v, nil
+}
+
+func (yamlFormat) Encode(v map[string]any) ([]byte, error) {
+	return yaml.Marshal(v)
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/fsutil"
+	"github.com/Goldziher/ai-rulez/internal/modules"
+)
+
+// LoadConfigFS is LoadConfig, reading through fsys instead of the real disk.
+// It parses filename as YAML only: JSON, TOML and .env files are not
+// converted, a directory is not loaded as a conf.d directory, and a
+// config_dir key is not applied.
+func LoadConfigFS(fsys fsutil.Filesystem, filename string) (*Config, error) {
+	data, err := fsys.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", filename, err)
+	}
+
+	if err := ValidateWithSchema(data, filename); err != nil {
+		return nil, fmt.Errorf("schema validation failed for %s: %w", filename, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+
+	ApplyDefaults(&config)
+
+	return &config, nil
+}
+
+// ApplyDefaults fills in every field a freshly-decoded Config leaves unset
+// with its documented default - currently just Priority (rules, sections,
+// and user_rulez's own rules/sections all default to 1) - in the single
+// pass every loader (LoadConfig, LoadConfigFS, and their *WithoutProfiles/
+// *WithIncludes variants) delegates to instead of re-implementing its own
+// copy of the same loop. This is the one place a future default (e.g. a
+// default Engine) should be added.
+func ApplyDefaults(config *Config) {
+	for i := range config.Rules {
+		if config.Rules[i].Priority == 0 {
+			config.Rules[i].Priority = 1
+		}
+	}
+	for i := range config.Sections {
+		if config.Sections[i].Priority == 0 {
+			config.Sections[i].Priority = 1
+		}
+	}
+	if config.UserRulez != nil {
+		for i := range config.UserRulez.Rules {
+			if config.UserRulez.Rules[i].Priority == 0 {
+				config.UserRulez.Rules[i].Priority = 1
+			}
+		}
+		for i := range config.UserRulez.Sections {
+			if config.UserRulez.Sections[i].Priority == 0 {
+				config.UserRulez.Sections[i].Priority = 1
+			}
+		}
+	}
+}
+
+// SaveConfigFS is SaveConfig, writing through fsys instead of the real disk.
+func SaveConfigFS(fsys fsutil.Filesystem, config *Config, filename string) error {
+	data, err := marshalConfig(config, filename)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	dir := filepath.Dir(filename)
+	if err := fsys.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	if err := fsys.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+// LoadConfigWithIncludesFS is LoadConfigWithIncludes, reading through fsys
+// instead of the real disk. Only literal-path local includes are supported:
+// glob patterns, directory includes, and remote (git::/http(s)://) sources
+// return an error instead of being silently skipped, and a bare include
+// name is read as a plain path relative to the including file (not resolved
+// against a project Layout). A .local.yaml overlay is not loaded. This covers
+// the common case this exists for: an in-memory dry run of a root config
+// plus its flat list of local includes, with no disk writes.
+func LoadConfigWithIncludesFS(fsys fsutil.Filesystem, filename string) (*Config, error) {
+	baseDir := filepath.Dir(filename)
+
+	config, err := loadConfigFS(fsys, filename, baseDir, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ResolveOutputExtends(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func loadConfigFS(fsys fsutil.Filesystem, filename, baseDir string, visited map[string]bool) (*Config, error) {
+	if visited[filename] {
+		return nil, fmt.Errorf("circular include detected: %s", filename)
+	}
+	visited[filename] = true
+	defer delete(visited, filename)
+
+	config, err := LoadConfigFS(fsys, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config.Includes) == 0 {
+		return config, nil
+	}
+
+	var allRules []Rule
+	var allSections []Section
+	allRules = append(allRules, config.Rules...)
+	allSections = append(allSections, config.Sections...)
+
+	for _, include := range config.Includes {
+		if isGlobPattern(include.Path) || modules.IsRemoteSource(include.Path) {
+			return nil, fmt.Errorf("include %s: glob, directory, and remote includes are not supported via LoadConfigWithIncludesFS", include.Path)
+		}
+
+		includePath := include.Path
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(baseDir, includePath)
+		}
+
+		if info, err := fsys.Stat(includePath); err == nil && info.IsDir() {
+			return nil, fmt.Errorf("include %s: directory includes are not supported via LoadConfigWithIncludesFS", include.Path)
+		}
+
+		includedConfig, err := loadConfigFS(fsys, includePath, filepath.Dir(includePath), visited)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load include %s: %w", include.Path, err)
+		}
+
+		allRules = append(allRules, includedConfig.Rules...)
+		allSections = append(allSections, includedConfig.Sections...)
+	}
+
+	config.Rules = MergeRules(allRules)
+	config.Sections = MergeSections(allSections)
+	config.Includes = nil
+
+	ApplyDefaults(config)
+
+	return config, nil
+}
+
+// FindConfigFileFS is FindConfigFile, reading through fsys instead of the
+// real disk.
+func FindConfigFileFS(fsys fsutil.Filesystem, startDir string) (string, error) {
+	dir := filepath.Clean(startDir)
+
+	visited := make(map[string]bool)
+	for !visited[dir] {
+		visited[dir] = true
+
+		for _, name := range configFileSearchOrder {
+			configPath := filepath.Join(dir, name)
+			if _, err := fsys.Stat(configPath); err == nil {
+				return configPath, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", errors.New("no configuration file found. Create an 'ai-rulez.yaml', '.ai-rulez.yaml', 'ai_rulez.yaml', or '.ai_rulez.yaml' file in your project")
+}
+
+// FindAllConfigFilesWithExcludesFS is FindAllConfigFilesWithExcludes,
+// walking through fsys instead of the real disk.
+func FindAllConfigFilesWithExcludesFS(fsys fsutil.Filesystem, rootDir string, excludes []string) ([]string, error) {
+	ignoreFileLines, err := loadIgnoreFileLinesFS(fsys, rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile excludes: %w", err)
+	}
+	matcher, err := compileExcludePatterns(append(ignoreFileLines, excludes...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile excludes: %w", err)
+	}
+
+	var configs []string
+	err = fsys.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, relErr := filepath.Rel(rootDir, path)
+		if relErr != nil {
+			return relErr
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if info.IsDir() {
+			if filepath.Base(path) != "." && filepath.Base(path)[0] == '.' {
+				return filepath.SkipDir
+			}
+			if relPath != "." && matcher.matchDir(relPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if configFileNames[filepath.Base(path)] && !matcher.match(relPath) {
+			configs = append(configs, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory tree: %w", err)
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no configuration files found in %s", rootDir)
+	}
+
+	return configs, nil
+}
+
+// loadIgnoreFileLinesFS is loadIgnoreFileLines, reading through fsys instead
+// of the real disk. Unlike the disk version, it does not walk upward past
+// rootDir: an in-memory filesystem has no meaningful "ancestor directory".
+func loadIgnoreFileLinesFS(fsys fsutil.Filesystem, rootDir string) ([]string, error) {
+	data, err := fsys.ReadFile(filepath.Join(rootDir, IgnoreFileName))
+	if err != nil {
+		return nil, nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
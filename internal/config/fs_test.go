@@ -0,0 +1,147 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/fsutil"
+)
+
+func TestLoadConfigFS(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("ai-rulez.yaml", []byte(`
+metadata:
+  name: Mem Project
+outputs:
+  - file: CLAUDE.md
+rules:
+  - name: Rule 1
+    content: Content 1
+`), 0o644))
+
+	cfg, err := config.LoadConfigFS(mem, "ai-rulez.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "Mem Project", cfg.Metadata.Name)
+	assert.Equal(t, 1, cfg.Rules[0].Priority)
+}
+
+func TestSaveConfigFS(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "Content 1", Priority: 1}},
+	}
+
+	require.NoError(t, config.SaveConfigFS(mem, cfg, "out/ai-rulez.yaml"))
+
+	loaded, err := config.LoadConfigFS(mem, "out/ai-rulez.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "Mem Project", loaded.Metadata.Name)
+}
+
+func TestLoadConfigWithIncludesFS(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("ai-rulez.yaml", []byte(`
+metadata:
+  name: Mem Project
+outputs:
+  - file: CLAUDE.md
+rules:
+  - name: Root Rule
+    content: Root content
+includes:
+  - shared.yaml
+`), 0o644))
+	require.NoError(t, mem.WriteFile("shared.yaml", []byte(`
+rules:
+  - name: Shared Rule
+    content: Shared content
+`), 0o644))
+
+	cfg, err := config.LoadConfigWithIncludesFS(mem, "ai-rulez.yaml")
+	require.NoError(t, err)
+	assert.Len(t, cfg.Rules, 2)
+	assert.Empty(t, cfg.Includes)
+}
+
+func TestLoadConfigWithIncludesFS_RejectsGlobIncludes(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("ai-rulez.yaml", []byte(`
+metadata:
+  name: Mem Project
+outputs:
+  - file: CLAUDE.md
+includes:
+  - "rules/*.yaml"
+`), 0o644))
+
+	_, err := config.LoadConfigWithIncludesFS(mem, "ai-rulez.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported via LoadConfigWithIncludesFS")
+}
+
+func TestLoadConfigWithIncludesFS_RejectsRemoteIncludes(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("ai-rulez.yaml", []byte(`
+metadata:
+  name: Mem Project
+outputs:
+  - file: CLAUDE.md
+includes:
+  - "git::https://github.com/example/rules.git"
+`), 0o644))
+
+	_, err := config.LoadConfigWithIncludesFS(mem, "ai-rulez.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported via LoadConfigWithIncludesFS")
+}
+
+func TestFindConfigFileFS(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("project/ai-rulez.yaml", []byte("test"), 0o644))
+
+	path, err := config.FindConfigFileFS(mem, "project")
+	require.NoError(t, err)
+	assert.Equal(t, "project/ai-rulez.yaml", path)
+}
+
+func TestFindConfigFileFS_NotFound(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	_, err := config.FindConfigFileFS(mem, ".")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no configuration file found")
+}
+
+func TestFindAllConfigFilesWithExcludesFS(t *testing.T) {
+	t.Parallel()
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("ai-rulez.yaml", []byte("test"), 0o644))
+	require.NoError(t, mem.WriteFile("vendor/some-dep/ai-rulez.yaml", []byte("test"), 0o644))
+	require.NoError(t, mem.WriteFile("project/ai-rulez.yaml", []byte("test"), 0o644))
+
+	configs, err := config.FindAllConfigFilesWithExcludesFS(mem, ".", []string{"**/vendor/**"})
+	require.NoError(t, err)
+	assert.Len(t, configs, 2)
+	for _, c := range configs {
+		assert.NotContains(t, c, "vendor")
+	}
+}
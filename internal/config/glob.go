@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// isGlobPattern reports whether includePath contains glob metacharacters
+// ("*", "?", "[") that expandIncludePath should expand, as opposed to a
+// literal file or directory path.
+func isGlobPattern(includePath string) bool {
+	return strings.ContainsAny(includePath, "*?[")
+}
+
+// expandIncludePath resolves one includes: entry to the concrete, sorted
+// list of config files it refers to:
+//
+//   - a literal file path resolves to itself
+//   - a directory resolves to every *.yaml/*.yml file beneath it, recursively
+//   - a doublestar glob (e.g. "rules.d/**/*.yaml") resolves to every file
+//     under baseDir it matches
+//
+// Matches are sorted for deterministic merge order. A literal, non-glob
+// entry that resolves to nothing - including a directory holding no
+// *.yaml/*.yml files - is an error; a glob that legitimately matches
+// nothing is a soft no-op (an empty, nil-error slice).
+func expandIncludePath(includePath, baseDir string) ([]string, error) {
+	resolved := includePath
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(baseDir, resolved)
+	}
+
+	if isGlobPattern(includePath) {
+		matches, err := globYAMLFiles(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", includePath, err)
+		}
+		sort.Strings(matches)
+		return matches, nil
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("include file not found: %s (resolved to %s)", includePath, resolved)
+	}
+
+	if !info.IsDir() {
+		return []string{resolved}, nil
+	}
+
+	matches, err := collectYAMLFiles(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan include directory %s: %w", includePath, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("include directory %s contains no *.yaml/*.yml files", includePath)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// collectYAMLFiles recursively finds every *.yaml/*.yml file beneath dir.
+func collectYAMLFiles(dir string) ([]string, error) {
+	var matches []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// globYAMLFiles expands a doublestar-style glob pattern ("**" matches any
+// number of path segments) against the filesystem, starting the walk at the
+// longest non-glob directory prefix of pattern.
+func globYAMLFiles(pattern string) ([]string, error) {
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	err = filepath.WalkDir(globRoot(pattern), func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if re.MatchString(filepath.ToSlash(path)) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// globRoot returns the longest directory prefix of pattern containing no
+// glob metacharacters, the starting point for globYAMLFiles' filesystem walk.
+func globRoot(pattern string) string {
+	slashed := filepath.ToSlash(pattern)
+	var prefix []string
+	for _, part := range strings.Split(slashed, "/") {
+		if strings.ContainsAny(part, "*?[") {
+			break
+		}
+		prefix = append(prefix, part)
+	}
+
+	root := strings.Join(prefix, "/")
+	if root == "" {
+		root = "/"
+	}
+	return filepath.FromSlash(root)
+}
+
+// globToRegexp compiles a doublestar-style glob into a regexp matching full
+// slash-separated paths: "**/" matches zero or more whole path segments,
+// "*" matches within a single segment, and "?" matches a single character.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = filepath.ToSlash(pattern)
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '*':
+			switch {
+			case strings.HasPrefix(pattern[i:], "**/"):
+				b.WriteString("(?:.*/)?")
+				i += 3
+			case strings.HasPrefix(pattern[i:], "**"):
+				b.WriteString(".*")
+				i += 2
+			default:
+				b.WriteString("[^/]*")
+				i++
+			}
+		case '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			i++
+		}
+	}
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestOutput_HeaderUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		yaml string
+		want *config.HeaderValue
+	}{
+		{
+			name: "unset",
+			yaml: `file: CLAUDE.md`,
+			want: nil,
+		},
+		{
+			name: "false disables the header",
+			yaml: `
+file: CLAUDE.md
+header: false`,
+			want: &config.HeaderValue{Disabled: true},
+		},
+		{
+			name: "string overrides the header",
+			yaml: `
+file: CLAUDE.md
+header: "// Copyright Acme Corp"`,
+			want: &config.HeaderValue{Template: "// Copyright Acme Corp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output config.Output
+			require.NoError(t, yaml.Unmarshal([]byte(tt.yaml), &output))
+			assert.Equal(t, tt.want, output.Header)
+		})
+	}
+}
+
+func TestOutput_HeaderUnmarshal_InvalidType(t *testing.T) {
+	t.Parallel()
+
+	var output config.Output
+	err := yaml.Unmarshal([]byte(`
+file: CLAUDE.md
+header: [1, 2, 3]`), &output)
+	assert.Error(t, err)
+}
+
+func TestHeaderValue_MarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	disabled, err := yaml.Marshal(config.HeaderValue{Disabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "false\n", string(disabled))
+
+	custom, err := yaml.Marshal(config.HeaderValue{Template: "// License"})
+	require.NoError(t, err)
+	assert.Equal(t, "// License\n", string(custom))
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// envRefPattern matches `${env:VAR}` and `${env:VAR1,VAR2,VAR3|default}`
+// placeholders inside Rule.Content, Section.Content, Output.File, and
+// Output.Template. This is a distinct, inline syntax from the
+// `{PREFIX}RULES__<ID>__...` whole-field overrides ApplyEnvOverrides
+// applies - it lets a single field reference an env var by name wherever
+// it appears, rather than replacing the field wholesale.
+var envRefPattern = regexp.MustCompile(`\$\{env:([^}]*)\}`)
+
+// UnresolvedEnvError reports a `${env:...}` reference that named no
+// environment variable with a non-empty value and carried no `|default`
+// fallback.
+type UnresolvedEnvError struct {
+	Container string   // e.g. `rule "Rule 1"`, `section "Intro"`, `output "CLAUDE.md"`
+	Names     []string // the comma-separated names tried, left to right
+}
+
+func (e *UnresolvedEnvError) Error() string {
+	return fmt.Sprintf("%s: unresolved ${env:%s} and no default given", e.Container, strings.Join(e.Names, ","))
+}
+
+// Resolve expands `${env:VAR}` and `${env:VAR1,VAR2,VAR3|default}`
+// references inside Rule.Content, Section.Content, Output.File, and
+// Output.Template, in place. Names are tried left to right; the first one
+// getenv returns a non-empty value for wins, mirroring viper's BindEnv
+// multi-name fallback. A reference with no matching name and no literal
+// `|default` fails with *UnresolvedEnvError naming the containing rule,
+// section, or output. getenv is injected rather than calling os.Getenv
+// directly so tests can supply a fake lookup without touching the process
+// environment.
+//
+// Interpolation only ever targets string fields (content, file, template),
+// so typed fields like Rule.Priority are untouched and the JSON schema's
+// existing `type` constraints for those fields need no change.
+func (c *Config) Resolve(getenv func(string) string) error {
+	for i := range c.Rules {
+		label := c.Rules[i].ID
+		if label == "" {
+			label = c.Rules[i].Name
+		}
+		resolved, err := resolveEnvRefs(fmt.Sprintf("rule %q", label), c.Rules[i].Content, getenv)
+		if err != nil {
+			return err
+		}
+		c.Rules[i].Content = resolved
+	}
+
+	for i := range c.Sections {
+		label := c.Sections[i].ID
+		if label == "" {
+			label = c.Sections[i].Title
+		}
+		resolved, err := resolveEnvRefs(fmt.Sprintf("section %q", label), c.Sections[i].Content, getenv)
+		if err != nil {
+			return err
+		}
+		c.Sections[i].Content = resolved
+	}
+
+	for i := range c.Outputs {
+		container := fmt.Sprintf("output %q", c.Outputs[i].File)
+
+		resolvedFile, err := resolveEnvRefs(container, c.Outputs[i].File, getenv)
+		if err != nil {
+			return err
+		}
+
+		resolvedTemplate, err := resolveEnvRefs(container, c.Outputs[i].Template, getenv)
+		if err != nil {
+			return err
+		}
+
+		c.Outputs[i].File = resolvedFile
+		c.Outputs[i].Template = resolvedTemplate
+	}
+
+	return nil
+}
+
+// resolveEnvRefs replaces every `${env:...}` placeholder in s, returning the
+// first unresolved reference's error (naming container) if any placeholder
+// has no matching env var and no default.
+func resolveEnvRefs(container, s string, getenv func(string) string) (string, error) {
+	var firstErr error
+
+	result := envRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+
+		spec := envRefPattern.FindStringSubmatch(match)[1]
+		names, def, hasDefault := splitEnvRefSpec(spec)
+
+		for _, name := range names {
+			if v := getenv(name); v != "" {
+				return v
+			}
+		}
+
+		if hasDefault {
+			return def
+		}
+
+		firstErr = &UnresolvedEnvError{Container: container, Names: names}
+		return match
+	})
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
+
+// splitEnvRefSpec splits the inside of a `${env:...}` placeholder into its
+// comma-separated variable names and an optional `|default` literal, e.g.
+// "HOST,FALLBACK_HOST|localhost" -> (["HOST", "FALLBACK_HOST"], "localhost", true).
+func splitEnvRefSpec(spec string) (names []string, def string, hasDefault bool) {
+	namesPart := spec
+	if idx := strings.Index(spec, "|"); idx >= 0 {
+		namesPart, def = spec[:idx], spec[idx+1:]
+		hasDefault = true
+	}
+
+	for _, name := range strings.Split(namesPart, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, def, hasDefault
+}
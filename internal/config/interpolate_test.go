@@ -0,0 +1,102 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestConfig_Resolve(t *testing.T) {
+	t.Parallel()
+
+	fakeEnv := map[string]string{
+		"PROJECT_NAME": "Widgets",
+		"MODEL_ID":     "claude",
+	}
+	getenv := func(k string) string { return fakeEnv[k] }
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		wantErr string
+		check   func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name: "expands a single var in rule content",
+			cfg: &config.Config{
+				Rules: []config.Rule{{Name: "Intro", Content: "Use ${env:MODEL_ID} for this project."}},
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "Use claude for this project.", cfg.Rules[0].Content)
+			},
+		},
+		{
+			name: "first non-empty name wins across a fallback list",
+			cfg: &config.Config{
+				Sections: []config.Section{{Title: "Header", Content: "${env:MISSING_HOST,PROJECT_NAME}"}},
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "Widgets", cfg.Sections[0].Content)
+			},
+		},
+		{
+			name: "literal default applies when no name resolves",
+			cfg: &config.Config{
+				Outputs: []config.Output{{File: "${env:MISSING|CLAUDE.md}"}},
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "CLAUDE.md", cfg.Outputs[0].File)
+			},
+		},
+		{
+			name: "expands output template alongside file",
+			cfg: &config.Config{
+				Outputs: []config.Output{{File: "out.md", Template: "@templates/${env:MODEL_ID}.md"}},
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "@templates/claude.md", cfg.Outputs[0].Template)
+			},
+		},
+		{
+			name: "unresolved rule reference names the rule",
+			cfg: &config.Config{
+				Rules: []config.Rule{{ID: "intro", Content: "${env:MISSING_HOST}"}},
+			},
+			wantErr: `rule "intro": unresolved ${env:MISSING_HOST} and no default given`,
+		},
+		{
+			name: "unresolved section reference names the section",
+			cfg: &config.Config{
+				Sections: []config.Section{{Title: "Header", Content: "${env:MISSING_HOST}"}},
+			},
+			wantErr: `section "Header": unresolved ${env:MISSING_HOST} and no default given`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cfg.Resolve(getenv)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+
+				var unresolved *config.UnresolvedEnvError
+				assert.True(t, errors.As(err, &unresolved))
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, tt.cfg)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Layout is the standard ai-rulez project directory layout: the project
+// root, its config dir, includes dir, and cache dir. configLoader consults
+// it only to resolve bare include names against IncludesDir; other include
+// paths are still resolved against the including file's directory. It lets
+// CI and other tooling tell ai-rulez where things live via env vars instead
+// of relying on the current working directory.
+type Layout struct {
+	Root        string
+	ConfigDir   string
+	IncludesDir string
+	CacheDir    string
+}
+
+// Environment variables overriding individual Layout fields.
+const (
+	EnvRoot        = "AI_RULEZ_ROOT"
+	EnvConfigDir   = "AI_RULEZ_CONFIG_DIR"
+	EnvIncludesDir = "AI_RULEZ_INCLUDE_DIR"
+	EnvCacheDir    = "AI_RULEZ_CACHE_DIR"
+)
+
+// rootMarkers are, in priority order, the files/directories DetectLayout
+// looks for while walking upward to find the project root.
+var rootMarkers = []string{"ai_rules.yaml", ".airules.yaml", ".git", "go.mod"}
+
+// DetectLayout resolves a Layout for the project containing startDir. The
+// root is found by walking upward from startDir for one of rootMarkers,
+// falling back to startDir itself if none is found. Each field can be
+// overridden independently via EnvRoot, EnvConfigDir, EnvIncludesDir, and
+// EnvCacheDir; relative overrides are resolved against the root.
+func DetectLayout(startDir string) (*Layout, error) {
+	root := os.Getenv(EnvRoot)
+	if root == "" {
+		var err error
+		root, err = findProjectRoot(startDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project root: %w", err)
+	}
+
+	configDir := resolveLayoutDir(EnvConfigDir, root, defaultConfigDir(root))
+	includesDir := resolveLayoutDir(EnvIncludesDir, root, filepath.Join(configDir, "includes"))
+	cacheDir := resolveLayoutDir(EnvCacheDir, root, filepath.Join(configDir, "cache"))
+
+	return &Layout{Root: root, ConfigDir: configDir, IncludesDir: includesDir, CacheDir: cacheDir}, nil
+}
+
+// resolveLayoutDir returns the value of env, resolved against root if
+// relative, or fall if env is unset.
+func resolveLayoutDir(env, root, fall string) string {
+	dir := os.Getenv(env)
+	if dir == "" {
+		return fall
+	}
+	if !filepath.IsAbs(dir) {
+		return filepath.Join(root, dir)
+	}
+	return dir
+}
+
+// defaultConfigDir returns root/.ai_rules if it already exists, else
+// root/ai_rules.
+func defaultConfigDir(root string) string {
+	if _, err := os.Stat(filepath.Join(root, ".ai_rules")); err == nil {
+		return filepath.Join(root, ".ai_rules")
+	}
+	return filepath.Join(root, "ai_rules")
+}
+
+// findProjectRoot walks upward from startDir looking for one of rootMarkers,
+// falling back to startDir itself if none is found by the time it reaches
+// the filesystem root.
+func findProjectRoot(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	start := dir
+
+	visited := make(map[string]bool)
+	for !visited[dir] {
+		visited[dir] = true
+		for _, marker := range rootMarkers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return start, nil
+}
+
+// Scaffold creates the layout's config, includes, and cache directories.
+func (l *Layout) Scaffold() error {
+	for _, dir := range []string{l.ConfigDir, l.IncludesDir, l.CacheDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// isBareIncludeName reports whether includePath names an include by bare
+// identifier rather than by relative or absolute path, e.g. "go-style"
+// rather than "./go-style.yaml" or "includes/go-style.yaml". Bare names
+// resolve against the Layout's includes dir.
+func isBareIncludeName(includePath string) bool {
+	return !strings.ContainsAny(includePath, `/\`) && filepath.Ext(includePath) == ""
+}
@@ -0,0 +1,134 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestDetectLayoutFindsRootByMarker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		marker string
+	}{
+		{name: "ai_rules.yaml", marker: "ai_rules.yaml"},
+		{name: ".airules.yaml", marker: ".airules.yaml"},
+		{name: ".git", marker: ".git"},
+		{name: "go.mod", marker: "go.mod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			if tt.marker == ".git" {
+				require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+			} else {
+				require.NoError(t, os.WriteFile(filepath.Join(root, tt.marker), []byte(""), 0o644))
+			}
+
+			nested := filepath.Join(root, "src", "nested")
+			require.NoError(t, os.MkdirAll(nested, 0o755))
+
+			layout, err := config.DetectLayout(nested)
+			require.NoError(t, err)
+			assert.Equal(t, root, layout.Root)
+		})
+	}
+}
+
+func TestDetectLayoutFallsBackToStartDir(t *testing.T) {
+	dir := t.TempDir()
+
+	layout, err := config.DetectLayout(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, layout.Root)
+}
+
+func TestDetectLayoutDefaultConfigDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	layout, err := config.DetectLayout(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "ai_rules"), layout.ConfigDir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".ai_rules"), 0o755))
+	layout, err = config.DetectLayout(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".ai_rules"), layout.ConfigDir)
+}
+
+func TestDetectLayoutEnvOverrides(t *testing.T) {
+	root := t.TempDir()
+	otherRoot := t.TempDir()
+
+	t.Setenv(config.EnvRoot, otherRoot)
+	t.Setenv(config.EnvConfigDir, "custom-config")
+	t.Setenv(config.EnvIncludesDir, "custom-config/shared")
+	t.Setenv(config.EnvCacheDir, "/var/tmp/ai-rulez-cache")
+
+	layout, err := config.DetectLayout(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, otherRoot, layout.Root)
+	assert.Equal(t, filepath.Join(otherRoot, "custom-config"), layout.ConfigDir)
+	assert.Equal(t, filepath.Join(otherRoot, "custom-config", "shared"), layout.IncludesDir)
+	assert.Equal(t, "/var/tmp/ai-rulez-cache", layout.CacheDir)
+}
+
+func TestLayoutScaffold(t *testing.T) {
+	root := t.TempDir()
+
+	layout, err := config.DetectLayout(root)
+	require.NoError(t, err)
+
+	require.NoError(t, layout.Scaffold())
+
+	for _, dir := range []string{layout.ConfigDir, layout.IncludesDir, layout.CacheDir} {
+		info, err := os.Stat(dir)
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+	}
+}
+
+func TestLoadConfigWithIncludesResolvesBareIncludeName(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+
+	layout, err := config.DetectLayout(root)
+	require.NoError(t, err)
+	require.NoError(t, layout.Scaffold())
+
+	t.Setenv(config.EnvIncludesDir, layout.IncludesDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(layout.IncludesDir, "go-style.yaml"), []byte(`metadata:
+  name: "go-style"
+outputs:
+  - file: "go.md"
+rules:
+  - name: "Go rule"
+    content: "go content"`), 0o644))
+
+	mainFile := filepath.Join(root, "ai_rulez.yaml")
+	require.NoError(t, os.WriteFile(mainFile, []byte(`metadata:
+  name: "main"
+includes:
+  - go-style
+outputs:
+  - file: "CLAUDE.md"`), 0o644))
+
+	cfg, err := config.LoadConfigWithIncludes(mainFile)
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "Go rule", cfg.Rules[0].Name)
+}
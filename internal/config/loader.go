@@ -7,18 +7,41 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/Goldziher/ai-rulez/internal/modules"
+	"github.com/Goldziher/ai-rulez/internal/selector"
 )
 
 // LoadConfigWithIncludes loads a configuration file and resolves all includes.
+// Remote includes whose fetched content no longer matches a pinned lockfile
+// hash cause an error; use LoadConfigWithIncludesAndUpdate to accept new content.
 func LoadConfigWithIncludes(filename string) (*Config, error) {
+	return LoadConfigWithIncludesAndUpdate(filename, false)
+}
+
+// LoadConfigWithIncludesAndUpdate loads a configuration file and resolves all
+// includes, same as LoadConfigWithIncludes. When allowUpdate is true, a
+// remote include whose content no longer matches its pinned lockfile hash is
+// accepted and re-pinned instead of rejected, mirroring a `--update` CLI flag.
+func LoadConfigWithIncludesAndUpdate(filename string, allowUpdate bool) (*Config, error) {
+	return LoadConfigWithContext(filename, allowUpdate, nil)
+}
+
+// LoadConfigWithContext loads a configuration file and resolves all includes,
+// same as LoadConfigWithIncludesAndUpdate, but also evaluates every Rule,
+// Section, and Include `when:` selector against ctx, dropping entries that
+// don't match. A nil ctx matches only entries with an empty `when:`.
+func LoadConfigWithContext(filename string, allowUpdate bool, ctx *selector.Context) (*Config, error) {
 	absPath, err := filepath.Abs(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absolute path for %s: %w", filename, err)
 	}
 
 	loader := &configLoader{
-		visited: make(map[string]bool),
-		baseDir: filepath.Dir(absPath),
+		visited:     make(map[string]bool),
+		baseDir:     filepath.Dir(absPath),
+		allowUpdate: allowUpdate,
+		ctx:         ctx,
 	}
 
 	config, err := loader.loadConfig(absPath)
@@ -38,13 +61,112 @@ func LoadConfigWithIncludes(filename string) (*Config, error) {
 		}
 	}
 
+	if err := ApplyEnvOverrides(config, "", os.Getenv); err != nil {
+		return nil, err
+	}
+
+	if err := ResolveOutputExtends(config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Resolve(os.Getenv); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
 // configLoader handles recursive include resolution with cycle detection.
 type configLoader struct {
-	visited map[string]bool
-	baseDir string
+	visited     map[string]bool
+	baseDir     string
+	resolver    *modules.Resolver // lazily created; resolves git::/https:// includes
+	lockfile    *modules.Lockfile // lazily loaded from <baseDir>/ai-rulez.lock
+	allowUpdate bool              // when true, a changed remote include is accepted and re-pinned
+	ctx         *selector.Context // when:selector evaluation context; nil matches only when:-less entries
+	explain     *MergeExplanation // non-nil to collect .local.yaml patch field provenance
+	layout      *Layout           // lazily detected; resolves bare include names against IncludesDir
+}
+
+// detectLayout returns the configLoader's Layout, detecting it from baseDir
+// on first use.
+func (l *configLoader) detectLayout() (*Layout, error) {
+	if l.layout == nil {
+		layout, err := DetectLayout(l.baseDir)
+		if err != nil {
+			return nil, err
+		}
+		l.layout = layout
+	}
+	return l.layout, nil
+}
+
+// MergeExplanation records, for `validate --explain-merge`, which
+// .local.yaml patch file contributed each touched field of each final rule
+// or section.
+type MergeExplanation struct {
+	Rules    []FieldSource
+	Sections []FieldSource
+}
+
+// LoadConfigWithExplain loads a configuration file and resolves all
+// includes and its .local.yaml overrides, additionally returning a
+// MergeExplanation of which .local.yaml patch file contributed each
+// touched rule/section field. Unlike LoadConfigWithIncludes, it does not
+// apply environment overrides or ${env:...} interpolation.
+func LoadConfigWithExplain(filename string) (*Config, *MergeExplanation, error) {
+	absPath, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get absolute path for %s: %w", filename, err)
+	}
+
+	explain := &MergeExplanation{}
+	loader := &configLoader{
+		visited: make(map[string]bool),
+		baseDir: filepath.Dir(absPath),
+		explain: explain,
+	}
+
+	config, err := loader.loadConfig(absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baseDir := filepath.Dir(absPath)
+	configBaseName := strings.TrimSuffix(filepath.Base(absPath), filepath.Ext(absPath))
+	localConfigPath := filepath.Join(baseDir, configBaseName+".local.yaml")
+	if _, err := os.Stat(localConfigPath); err == nil {
+		if err := loader.loadLocalOverrides(config, localConfigPath); err != nil {
+			return nil, nil, fmt.Errorf("failed to load %s: %w", filepath.Base(localConfigPath), err)
+		}
+	}
+
+	if err := ResolveOutputExtends(config); err != nil {
+		return nil, nil, err
+	}
+
+	return config, explain, nil
+}
+
+// remoteResolver returns the configLoader's module resolver, creating it
+// (and loading its lockfile) on first use so configs with no remote
+// includes never touch the module cache.
+func (l *configLoader) remoteResolver() (*modules.Resolver, *modules.Lockfile, error) {
+	if l.resolver == nil {
+		resolver, err := modules.NewResolver()
+		if err != nil {
+			return nil, nil, err
+		}
+		l.resolver = resolver
+	}
+	if l.lockfile == nil {
+		lockfile, err := modules.LoadLockfile(filepath.Join(l.baseDir, modules.LockfileName))
+		if err != nil {
+			return nil, nil, err
+		}
+		l.lockfile = lockfile
+	}
+	return l.resolver, l.lockfile, nil
 }
 
 // loadConfig loads a config file and resolves includes recursively.
@@ -67,6 +189,10 @@ func (l *configLoader) loadConfig(filename string) (*Config, error) {
 		return nil, fmt.Errorf("failed to load config %s: %w", absPath, err)
 	}
 
+	if err := filterByContext(config, l.ctx); err != nil {
+		return nil, fmt.Errorf("failed to evaluate when selectors in %s: %w", absPath, err)
+	}
+
 	// Resolve includes
 	if err := l.resolveIncludes(config, filepath.Dir(absPath)); err != nil {
 		return nil, fmt.Errorf("failed to resolve includes in %s: %w", absPath, err)
@@ -75,6 +201,44 @@ func (l *configLoader) loadConfig(filename string) (*Config, error) {
 	return config, nil
 }
 
+// filterByContext drops Rules and Sections whose `when:` selector doesn't
+// match ctx, in place. Entries with an empty `when:` are always kept.
+func filterByContext(config *Config, ctx *selector.Context) error {
+	keptRules := config.Rules[:0]
+	for _, rule := range config.Rules {
+		matches, err := selector.Evaluate(rule.When, ctx)
+		if err != nil {
+			label := rule.ID
+			if label == "" {
+				label = rule.Name
+			}
+			return fmt.Errorf("rule %q: %w", label, err)
+		}
+		if matches {
+			keptRules = append(keptRules, rule)
+		}
+	}
+	config.Rules = keptRules
+
+	keptSections := config.Sections[:0]
+	for _, section := range config.Sections {
+		matches, err := selector.Evaluate(section.When, ctx)
+		if err != nil {
+			label := section.ID
+			if label == "" {
+				label = section.Title
+			}
+			return fmt.Errorf("section %q: %w", label, err)
+		}
+		if matches {
+			keptSections = append(keptSections, section)
+		}
+	}
+	config.Sections = keptSections
+
+	return nil
+}
+
 // resolveIncludes processes all include paths and merges rules and sections.
 func (l *configLoader) resolveIncludes(config *Config, baseDir string) error {
 	if len(config.Includes) == 0 {
@@ -88,16 +252,31 @@ func (l *configLoader) resolveIncludes(config *Config, baseDir string) error {
 	allSections = append(allSections, config.Sections...)
 
 	// Process each include
-	for _, includePath := range config.Includes {
-		resolvedPath := l.resolvePath(includePath, baseDir)
+	for _, include := range config.Includes {
+		matches, err := selector.Evaluate(include.When, l.ctx)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", include.Path, err)
+		}
+		if !matches {
+			continue
+		}
+
+		resolvedPath, err := l.resolveInclude(include.Path, baseDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve include %s: %w", include.Path, err)
+		}
 
 		if _, err := os.Stat(resolvedPath); os.IsNotExist(err) {
-			return fmt.Errorf("include file not found: %s (resolved to %s)", includePath, resolvedPath)
+			return fmt.Errorf("include file not found: %s (resolved to %s)", include.Path, resolvedPath)
 		}
 
 		includedConfig, err := l.loadConfig(resolvedPath)
 		if err != nil {
-			return fmt.Errorf("failed to load include %s: %w", includePath, err)
+			return fmt.Errorf("failed to load include %s: %w", include.Path, err)
+		}
+
+		if include.Prefix != "" {
+			prefixRulesAndSections(includedConfig, include.Prefix)
 		}
 
 		// Merge rules and sections from included config
@@ -110,29 +289,81 @@ func (l *configLoader) resolveIncludes(config *Config, baseDir string) error {
 	config.Sections = MergeSections(allSections)
 	config.Includes = nil
 
-	// Ensure all rules have priority (default to 1)
-	for i := range config.Rules {
-		if config.Rules[i].Priority == 0 {
-			config.Rules[i].Priority = 1
+	ApplyDefaults(config)
+
+	return nil
+}
+
+// resolvePath resolves relative paths against the base directory. A bare
+// include name with no directory or extension, e.g. "go-style", resolves
+// against the project Layout's includes dir instead, so includes: [go-style]
+// finds <includes-dir>/go-style.yaml regardless of which file included it.
+func (l *configLoader) resolvePath(includePath, baseDir string) string {
+	if filepath.IsAbs(includePath) {
+		return includePath
+	}
+	if isBareIncludeName(includePath) {
+		if layout, err := l.detectLayout(); err == nil {
+			return filepath.Join(layout.IncludesDir, includePath+".yaml")
 		}
 	}
+	return filepath.Join(baseDir, includePath)
+}
+
+// resolveInclude resolves an include entry to a local file path, fetching
+// and caching it first if it's a remote (git::/http(s)://) source.
+func (l *configLoader) resolveInclude(includePath, baseDir string) (string, error) {
+	if !modules.IsRemoteSource(includePath) {
+		return l.resolvePath(includePath, baseDir), nil
+	}
 
-	// Ensure all sections have priority (default to 1)
-	for i := range config.Sections {
-		if config.Sections[i].Priority == 0 {
-			config.Sections[i].Priority = 1
+	src, err := modules.ParseSource(includePath)
+	if err != nil {
+		return "", err
+	}
+
+	resolver, lockfile, err := l.remoteResolver()
+	if err != nil {
+		return "", err
+	}
+
+	localPath, hash, err := resolver.Resolve(src)
+	if err != nil {
+		return "", err
+	}
+
+	if err := lockfile.Verify(src, localPath); err != nil {
+		if !l.allowUpdate {
+			return "", fmt.Errorf("%w (rerun with --update to accept the new content)", err)
 		}
 	}
+	lockfile.Pin(src, src.Ref, hash)
 
-	return nil
+	if err := modules.SaveLockfile(lockfile, filepath.Join(l.baseDir, modules.LockfileName)); err != nil {
+		return "", err
+	}
+
+	return localPath, nil
 }
 
-// resolvePath resolves relative paths against the base directory.
-func (*configLoader) resolvePath(includePath, baseDir string) string {
-	if filepath.IsAbs(includePath) {
-		return includePath
+// prefixRulesAndSections prepends prefix to the ID of every Rule and
+// Section in config (or, lacking an ID, the Name/Title), in place - see
+// IncludeEntry.Prefix.
+func prefixRulesAndSections(config *Config, prefix string) {
+	for i, rule := range config.Rules {
+		if rule.ID != "" {
+			config.Rules[i].ID = prefix + rule.ID
+		} else {
+			config.Rules[i].Name = prefix + rule.Name
+		}
+	}
+	for i, section := range config.Sections {
+		if section.ID != "" {
+			config.Sections[i].ID = prefix + section.ID
+		} else {
+			config.Sections[i].Title = prefix + section.Title
+		}
 	}
-	return filepath.Join(baseDir, includePath)
 }
 
 // MergeRules combines multiple rule slices, with later rules taking precedence.
@@ -198,23 +429,34 @@ func MergeSections(sectionSets ...[]Section) []Section {
 }
 
 // ValidateIncludes checks that all include paths are valid and accessible.
+// Remote (git::/http(s)://) includes are only checked for well-formedness;
+// fetching and content validation happens lazily during resolveIncludes.
 func ValidateIncludes(config *Config, baseDir string) error {
-	for _, includePath := range config.Includes {
-		var resolvedPath string
-		if filepath.IsAbs(includePath) {
-			resolvedPath = includePath
-		} else {
-			resolvedPath = filepath.Join(baseDir, includePath)
+	for _, include := range config.Includes {
+		if _, err := selector.Evaluate(include.When, nil); err != nil {
+			return fmt.Errorf("invalid when selector for include %s: %w", include.Path, err)
 		}
 
-		// Check if file exists
-		if _, err := os.Stat(resolvedPath); os.IsNotExist(err) {
-			return fmt.Errorf("include file not found: %s", includePath)
+		if modules.IsRemoteSource(include.Path) {
+			if _, err := modules.ParseSource(include.Path); err != nil {
+				return fmt.Errorf("invalid include source %s: %w", include.Path, err)
+			}
+			continue
+		}
+
+		// Expand literal paths, directories, and doublestar globs alike; a
+		// literal entry that matches nothing (including a directory with no
+		// yaml files) errors, a glob that matches nothing is a soft no-op.
+		resolvedPaths, err := expandIncludePath(include.Path, baseDir)
+		if err != nil {
+			return err
 		}
 
-		// Check if it's a valid YAML file by attempting to parse
-		if _, err := LoadConfig(resolvedPath); err != nil {
-			return fmt.Errorf("invalid include file %s: %w", includePath, err)
+		// Check that each resolved file is a valid YAML file
+		for _, resolvedPath := range resolvedPaths {
+			if _, err := LoadConfig(resolvedPath); err != nil {
+				return fmt.Errorf("invalid include file %s: %w", include.Path, err)
+			}
 		}
 	}
 
@@ -236,27 +478,78 @@ func ValidateOutputs(outputs []Output) error {
 	return nil
 }
 
-// loadLocalOverrides loads local override rules from .local.yaml file
+// loadLocalOverrides applies a .local.yaml patch file on top of config.
+// Metadata, outputs, and user_rulez merge/replace as before; rules and
+// sections are patched per-field (see RulePatch) instead of being replaced
+// wholesale. When l.explain is set, it records which field of which
+// rule/section the patch file touched.
 func (l *configLoader) loadLocalOverrides(config *Config, filename string) error {
-	// Load the local config file
-	localConfig, err := l.loadConfig(filename)
+	patch, err := loadLocalPatchFile(filename)
 	if err != nil {
-		return fmt.Errorf("failed to load local config: %w", err)
+		return err
 	}
 
-	// Merge rules and sections using ID-based merging
-	config.Rules = MergeRules(config.Rules, localConfig.Rules)
-	config.Sections = MergeSections(config.Sections, localConfig.Sections)
+	// Deep-merge metadata: non-empty local fields overwrite main fields
+	mergeMetadata(&config.Metadata, patch.Metadata)
+
+	// Merge outputs by file key
+	config.Outputs = MergeOutputs(config.Outputs, patch.Outputs)
+
+	source := patchSourceName(filename)
+	var ruleExplain, sectionExplain *[]FieldSource
+	if l.explain != nil {
+		ruleExplain = &l.explain.Rules
+		sectionExplain = &l.explain.Sections
+	}
+	config.Rules = PatchRules(config.Rules, source, patch.Rules, ruleExplain)
+	config.Sections = PatchSections(config.Sections, source, patch.Sections, sectionExplain)
 
-	// Also merge user_rulez if present in local config
-	if localConfig.UserRulez != nil {
+	// Also merge user_rulez if present in the patch file
+	if patch.UserRulez != nil {
 		if config.UserRulez == nil {
-			config.UserRulez = localConfig.UserRulez
+			config.UserRulez = patch.UserRulez
 		} else {
-			config.UserRulez.Rules = MergeRules(config.UserRulez.Rules, localConfig.UserRulez.Rules)
-			config.UserRulez.Sections = MergeSections(config.UserRulez.Sections, localConfig.UserRulez.Sections)
+			config.UserRulez.Rules = MergeRules(config.UserRulez.Rules, patch.UserRulez.Rules)
+			config.UserRulez.Sections = MergeSections(config.UserRulez.Sections, patch.UserRulez.Sections)
 		}
 	}
 
 	return nil
 }
+
+// mergeMetadata overlays non-empty fields from override onto base, in place.
+func mergeMetadata(base *Metadata, override Metadata) {
+	if override.Name != "" {
+		base.Name = override.Name
+	}
+	if override.Version != "" {
+		base.Version = override.Version
+	}
+	if override.Description != "" {
+		base.Description = override.Description
+	}
+}
+
+// MergeOutputs combines multiple output slices, keyed by File, with later
+// outputs taking precedence. This lets a `.local.yaml` overlay replace an
+// existing output's template or append a developer-local output entirely.
+func MergeOutputs(outputSets ...[]Output) []Output {
+	outputMap := make(map[string]Output)
+	var order []string
+
+	for _, outputs := range outputSets {
+		for _, output := range outputs {
+			if _, exists := outputMap[output.File]; !exists {
+				order = append(order, output.File)
+			}
+			outputMap[output.File] = output
+		}
+	}
+
+	result := make([]Output, 0, len(order))
+	for _, file := range order {
+		result = append(result, outputMap[file])
+	}
+
+	return result
+}
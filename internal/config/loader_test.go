@@ -1,6 +1,8 @@
 package config_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -9,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/selector"
 )
 
 func TestLoadConfigWithIncludes(t *testing.T) {
@@ -149,6 +152,167 @@ includes:
 			wantErr: true,
 			check:   nil,
 		},
+		{
+			name: "directory include picks up every yaml file sorted",
+			files: map[string]string{
+				"main.yaml": `metadata:
+  name: "main"
+includes:
+  - "rules.d"
+outputs:
+  - file: "CLAUDE.md"`,
+				"rules.d/b.yaml": `metadata:
+  name: "b"
+outputs:
+  - file: "b.md"
+rules:
+  - name: "b rule"
+    content: "b content"`,
+				"rules.d/a.yml": `metadata:
+  name: "a"
+outputs:
+  - file: "a.md"
+rules:
+  - name: "a rule"
+    content: "a content"`,
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Len(t, cfg.Rules, 2)
+				assert.Equal(t, "a rule", cfg.Rules[0].Name)
+				assert.Equal(t, "b rule", cfg.Rules[1].Name)
+			},
+		},
+		{
+			name: "doublestar glob matches nested includes",
+			files: map[string]string{
+				"main.yaml": `metadata:
+  name: "main"
+includes:
+  - "rules.d/**/*.yaml"
+outputs:
+  - file: "CLAUDE.md"`,
+				"rules.d/go/style.yaml": `metadata:
+  name: "go style"
+outputs:
+  - file: "go.md"
+rules:
+  - name: "go style rule"
+    content: "gofmt everything"`,
+				"rules.d/nested/deep/style.yaml": `metadata:
+  name: "deep style"
+outputs:
+  - file: "deep.md"
+rules:
+  - name: "deep style rule"
+    content: "deep content"`,
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Len(t, cfg.Rules, 2)
+				assert.Equal(t, "go style rule", cfg.Rules[0].Name)
+				assert.Equal(t, "deep style rule", cfg.Rules[1].Name)
+			},
+		},
+		{
+			name: "glob matching zero files is a soft no-op",
+			files: map[string]string{
+				"main.yaml": `metadata:
+  name: "main"
+includes:
+  - "rules.d/**/*.yaml"
+outputs:
+  - file: "CLAUDE.md"`,
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Empty(t, cfg.Rules)
+			},
+		},
+		{
+			name: "literal non-glob entry matching zero files is an error",
+			files: map[string]string{
+				"main.yaml": `metadata:
+  name: "main"
+includes:
+  - "missing-dir"
+outputs:
+  - file: "CLAUDE.md"`,
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "literal directory with no yaml files is an error",
+			files: map[string]string{
+				"main.yaml": `metadata:
+  name: "main"
+includes:
+  - "empty.d"
+outputs:
+  - file: "CLAUDE.md"`,
+				"empty.d/.gitkeep": "",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "prefixed include namespaces merged rule and section names",
+			files: map[string]string{
+				"main.yaml": `metadata:
+  name: "main"
+includes:
+  - path: "shared.yaml"
+    prefix: "acme-"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - name: "main rule"
+    content: "main content"`,
+				"shared.yaml": `metadata:
+  name: "shared"
+outputs:
+  - file: "output.md"
+rules:
+  - id: "style"
+    name: "shared rule"
+    content: "shared content"
+sections:
+  - title: "Shared Section"
+    content: "shared section content"`,
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Len(t, cfg.Rules, 2)
+				assert.Equal(t, "main rule", cfg.Rules[0].Name)
+				assert.Equal(t, "acme-style", cfg.Rules[1].ID)
+				require.Len(t, cfg.Sections, 1)
+				assert.Equal(t, "acme-Shared Section", cfg.Sections[0].Title)
+			},
+		},
+		{
+			name: "circular include detector trips through a glob",
+			files: map[string]string{
+				"main.yaml": `metadata:
+  name: "main"
+includes:
+  - "rules.d/*.yaml"
+outputs:
+  - file: "CLAUDE.md"`,
+				"rules.d/circular.yaml": `metadata:
+  name: "circular"
+outputs:
+  - file: "output.md"
+includes:
+  - "../main.yaml"`,
+			},
+			wantErr: true,
+			check:   nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -159,6 +323,7 @@ includes:
 			tmpDir := t.TempDir()
 			for filename, content := range tt.files {
 				filePath := filepath.Join(tmpDir, filename)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
 				err := os.WriteFile(filePath, []byte(content), 0o644)
 				require.NoError(t, err)
 			}
@@ -249,7 +414,7 @@ func TestValidateIncludes(t *testing.T) {
 		{
 			name: "valid includes",
 			config: &config.Config{
-				Includes: []string{"valid.yaml"},
+				Includes: []config.IncludeEntry{{Path: "valid.yaml"}},
 			},
 			files: map[string]string{
 				"valid.yaml": `metadata:
@@ -262,7 +427,7 @@ outputs:
 		{
 			name: "missing include file",
 			config: &config.Config{
-				Includes: []string{"missing.yaml"},
+				Includes: []config.IncludeEntry{{Path: "missing.yaml"}},
 			},
 			files:   map[string]string{},
 			wantErr: true,
@@ -270,13 +435,41 @@ outputs:
 		{
 			name: "invalid YAML in include",
 			config: &config.Config{
-				Includes: []string{"invalid.yaml"},
+				Includes: []config.IncludeEntry{{Path: "invalid.yaml"}},
 			},
 			files: map[string]string{
 				"invalid.yaml": "invalid: yaml: [",
 			},
 			wantErr: true,
 		},
+		{
+			name: "well-formed remote include is accepted without fetching",
+			config: &config.Config{
+				Includes: []config.IncludeEntry{{Path: "git::https://github.com/org/repo//rules.yaml?ref=v1.0.0"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "glob matching files is accepted",
+			config: &config.Config{
+				Includes: []config.IncludeEntry{{Path: "rules.d/*.yaml"}},
+			},
+			files: map[string]string{
+				"rules.d/a.yaml": `metadata:
+  name: "a"
+outputs:
+  - file: "a.md"`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "glob matching nothing is accepted as a soft no-op",
+			config: &config.Config{
+				Includes: []config.IncludeEntry{{Path: "rules.d/*.yaml"}},
+			},
+			files:   map[string]string{},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -286,6 +479,7 @@ outputs:
 			tmpDir := t.TempDir()
 			for filename, content := range tt.files {
 				filePath := filepath.Join(tmpDir, filename)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
 				err := os.WriteFile(filePath, []byte(content), 0o644)
 				require.NoError(t, err)
 			}
@@ -400,7 +594,7 @@ func TestLoadConfigWithLocalFile(t *testing.T) {
 		check   func(t *testing.T, cfg *config.Config)
 	}{
 		{
-			name: "local file overrides rules by ID",
+			name: "local file overrides rules by ID, metadata and outputs by file key",
 			files: map[string]string{
 				"test.yaml": `metadata:
   name: "main"
@@ -423,10 +617,13 @@ rules:
 			wantErr: false,
 			check: func(t *testing.T, cfg *config.Config) {
 				t.Helper()
-				assert.Equal(t, "main", cfg.Metadata.Name)
+				assert.Equal(t, "local overrides", cfg.Metadata.Name)
 				assert.Len(t, cfg.Rules, 1)
 				assert.Equal(t, "Rule 1 Overridden", cfg.Rules[0].Name)
 				assert.Equal(t, "LOCAL: overridden content", cfg.Rules[0].Content)
+				assert.Len(t, cfg.Outputs, 2)
+				assert.Equal(t, "CLAUDE.md", cfg.Outputs[0].File)
+				assert.Equal(t, "local.md", cfg.Outputs[1].File)
 			},
 		},
 	}
@@ -438,6 +635,7 @@ rules:
 			tmpDir := t.TempDir()
 			for filename, content := range tt.files {
 				filePath := filepath.Join(tmpDir, filename)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
 				err := os.WriteFile(filePath, []byte(content), 0o644)
 				require.NoError(t, err)
 			}
@@ -455,3 +653,190 @@ rules:
 		})
 	}
 }
+
+func TestLoadConfigWithEnvOverrides(t *testing.T) {
+	// Not t.Parallel(): sets process-wide env vars via t.Setenv.
+
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"test.yaml": `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "rule1"
+    name: "Rule 1"
+    priority: 1
+    content: "original content"`,
+		"test.local.yaml": `rules:
+  - id: "rule1"
+    name: "Rule 1 Overridden"
+    content: "LOCAL: overridden content"`,
+	}
+	for filename, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0o644))
+	}
+
+	t.Setenv("AI_RULEZ_METADATA__VERSION", "2.0.0")
+	t.Setenv("AI_RULEZ_RULES__RULE1__CONTENT", "ENV: overridden content")
+	t.Setenv("AI_RULEZ_RULES__RULE1__PRIORITY", "5")
+
+	mainFile := filepath.Join(tmpDir, "test.yaml")
+	cfg, err := config.LoadConfigWithIncludesWithoutProfiles(mainFile)
+	require.NoError(t, err)
+
+	// Env wins over the .local.yaml file for fields it sets...
+	assert.Equal(t, "2.0.0", cfg.Metadata.Version)
+	assert.Equal(t, "ENV: overridden content", cfg.Rules[0].Content)
+	assert.Equal(t, 5, cfg.Rules[0].Priority)
+	// ...but fields untouched by env still reflect the .local.yaml override.
+	assert.Equal(t, "Rule 1 Overridden", cfg.Rules[0].Name)
+}
+
+func TestLoadConfigWithContext(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.yaml")
+	files := map[string]string{
+		"main.yaml": `metadata:
+  name: "main"
+includes:
+  - path: "go.yaml"
+    when: "has(go.mod)"
+  - path: "rust.yaml"
+    when: "has(Cargo.toml)"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - name: "always on"
+    content: "always"
+  - name: "ci only"
+    content: "ci"
+    when: "ci"`,
+		"go.yaml": `metadata:
+  name: "go"
+outputs:
+  - file: "go.md"
+rules:
+  - name: "go rule"
+    content: "go content"`,
+		"rust.yaml": `metadata:
+  name: "rust"
+outputs:
+  - file: "rust.md"
+rules:
+  - name: "rust rule"
+    content: "rust content"`,
+	}
+	for filename, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example\n"), 0o644))
+
+	ctx := selector.DetectContext(tmpDir, []string{"ci"})
+	cfg, err := config.LoadConfigWithContext(mainFile, false, ctx)
+	require.NoError(t, err)
+
+	var ruleNames []string
+	for _, rule := range cfg.Rules {
+		ruleNames = append(ruleNames, rule.Name)
+	}
+	assert.ElementsMatch(t, []string{"always on", "ci only", "go rule"}, ruleNames)
+}
+
+func TestLoadConfigWithIncludesWithoutProfilesRemoteHTTP(t *testing.T) {
+	t.Parallel()
+
+	const remoteYAML = `metadata:
+  name: "remote"
+outputs:
+  - file: "remote.md"
+rules:
+  - name: "remote rule"
+    content: "remote content"`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(remoteYAML))
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.yaml")
+	require.NoError(t, os.WriteFile(mainFile, []byte(`metadata:
+  name: "main"
+includes:
+  - "`+server.URL+`/rules.yaml"
+outputs:
+  - file: "CLAUDE.md"`), 0o644))
+
+	cfg, err := config.LoadConfigWithIncludesWithoutProfiles(mainFile)
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "remote rule", cfg.Rules[0].Name)
+
+	// Fetched content is cached locally and pinned in a lockfile next to the root config.
+	assert.FileExists(t, filepath.Join(tmpDir, "ai-rulez.lock"))
+	cacheEntries, err := os.ReadDir(filepath.Join(tmpDir, ".ai-rulez", "cache"))
+	require.NoError(t, err)
+	assert.Len(t, cacheEntries, 1)
+
+	// A second load is served from the cache and lockfile, not the server.
+	server.Close()
+	cfg2, err := config.LoadConfigWithIncludesWithoutProfiles(mainFile)
+	require.NoError(t, err)
+	require.Len(t, cfg2.Rules, 1)
+	assert.Equal(t, "remote rule", cfg2.Rules[0].Name)
+}
+
+func TestLoadConfigWithIncludesWithoutProfilesRemoteTamperDetection(t *testing.T) {
+	t.Parallel()
+
+	var serve func(w http.ResponseWriter, r *http.Request)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { serve(w, r) }))
+	defer server.Close()
+
+	serve = func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`metadata:
+  name: "remote"
+outputs:
+  - file: "remote.md"
+rules:
+  - name: "remote rule v1"
+    content: "v1"`))
+	}
+
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.yaml")
+	require.NoError(t, os.WriteFile(mainFile, []byte(`metadata:
+  name: "main"
+includes:
+  - "`+server.URL+`/rules.yaml"
+outputs:
+  - file: "CLAUDE.md"`), 0o644))
+
+	_, err := config.LoadConfigWithIncludesWithoutProfiles(mainFile)
+	require.NoError(t, err)
+
+	// Clear the local cache so the next load re-fetches, but the lockfile
+	// (pinned to the v1 content hash) is left in place.
+	require.NoError(t, os.RemoveAll(filepath.Join(tmpDir, ".ai-rulez", "cache")))
+
+	serve = func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`metadata:
+  name: "remote"
+outputs:
+  - file: "remote.md"
+rules:
+  - name: "remote rule v2"
+    content: "v2"`))
+	}
+
+	_, err = config.LoadConfigWithIncludesWithoutProfiles(mainFile)
+	assert.Error(t, err)
+
+	cfg, err := config.LoadConfigWithIncludesWithoutProfilesAndRefresh(mainFile, true)
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "remote rule v2", cfg.Rules[0].Name)
+}
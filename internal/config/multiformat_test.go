@@ -0,0 +1,99 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestLoadConfig_JSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ai_rulez.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"metadata": {"name": "JSON Project"},
+		"outputs": [{"file": "CLAUDE.md"}],
+		"rules": [{"name": "Rule 1", "content": "content"}]
+	}`), 0o644))
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "JSON Project", cfg.Metadata.Name)
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "Rule 1", cfg.Rules[0].Name)
+}
+
+func TestLoadConfig_TOML(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ai_rulez.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+[metadata]
+name = "TOML Project"
+
+[[outputs]]
+file = "CLAUDE.md"
+
+[[rules]]
+name = "Rule 1"
+content = "content"
+`), 0o644))
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "TOML Project", cfg.Metadata.Name)
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "Rule 1", cfg.Rules[0].Name)
+}
+
+func TestLoadConfig_Env(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ai_rulez.env")
+	require.NoError(t, os.WriteFile(path, []byte(`
+METADATA__NAME=Env Project
+OUTPUTS__0__FILE=CLAUDE.md
+RULES__0__NAME=Rule 1
+RULES__0__CONTENT=content
+`), 0o644))
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Env Project", cfg.Metadata.Name)
+	require.Len(t, cfg.Outputs, 1)
+	assert.Equal(t, "CLAUDE.md", cfg.Outputs[0].File)
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "Rule 1", cfg.Rules[0].Name)
+}
+
+func TestSaveConfig_PreservesOriginalFormat(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Round Trip"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Priority: 1, Content: "content"}},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ai_rulez.json")
+
+	require.NoError(t, config.SaveConfig(cfg, path))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"name": "Round Trip"`)
+
+	loaded, err := config.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Round Trip", loaded.Metadata.Name)
+	assert.Equal(t, cfg.Rules[0].Name, loaded.Rules[0].Name)
+}
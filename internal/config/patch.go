@@ -0,0 +1,340 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// localPatchFile is the shape of a .local.yaml override file. Metadata,
+// Outputs, and UserRulez merge/replace as before; Rules and Sections are
+// patches (see RulePatch) applied field-by-field rather than replaced
+// wholesale. It is parsed directly, without schema validation or its own
+// `includes:`/`when:` resolution, since a patch file is a leaf overlay
+// applied last, not a standalone config.
+type localPatchFile struct {
+	Metadata  Metadata       `yaml:"metadata,omitempty"`
+	Outputs   []Output       `yaml:"outputs,omitempty"`
+	Rules     []RulePatch    `yaml:"rules,omitempty"`
+	Sections  []SectionPatch `yaml:"sections,omitempty"`
+	UserRulez *UserRulez     `yaml:"user_rulez,omitempty"`
+}
+
+// loadLocalPatchFile reads and parses filename as a localPatchFile.
+func loadLocalPatchFile(filename string) (*localPatchFile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local override file %s: %w", filename, err)
+	}
+
+	var patch localPatchFile
+	if err := yaml.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("failed to parse local override file %s: %w", filename, err)
+	}
+	return &patch, nil
+}
+
+// patchSourceName is the short, stable name recorded into FieldSource
+// entries for a patch file, e.g. "ai_rulez.local.yaml".
+func patchSourceName(filename string) string {
+	return filepath.Base(filename)
+}
+
+// ContentPatch is a Rule/Section Content override in a .local.yaml patch
+// file. It unmarshals from a plain string (full replacement) or a mapping
+// with $append (appends to the base content), e.g.:
+//
+//	content: "full replacement text"
+//	content:
+//	  $append: "\n\nExtra paragraph appended to the base content."
+type ContentPatch struct {
+	Literal  string
+	Append   string
+	isAppend bool
+}
+
+// UnmarshalYAML accepts either a bare replacement string or a {$append: ...} mapping.
+func (c *ContentPatch) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&c.Literal)
+	}
+
+	var directive struct {
+		Append string `yaml:"$append"`
+	}
+	if err := value.Decode(&directive); err != nil {
+		return err
+	}
+	c.Append = directive.Append
+	c.isAppend = true
+	return nil
+}
+
+// Apply returns base with this patch applied.
+func (c *ContentPatch) Apply(base string) string {
+	if c == nil {
+		return base
+	}
+	if c.isAppend {
+		return base + c.Append
+	}
+	return c.Literal
+}
+
+// TagsPatch is a Rule/Section Tags override in a .local.yaml patch file. It
+// unmarshals from a plain list (full replacement) or a mapping with $add
+// and/or $remove (applied to the base tags), e.g.:
+//
+//	tags: [security, compliance]
+//	tags:
+//	  $add: [experimental]
+//	  $remove: [draft]
+type TagsPatch struct {
+	Literal []string
+	Add     []string
+	Remove  []string
+}
+
+// UnmarshalYAML accepts either a bare replacement list or a
+// {$add: [...], $remove: [...]} mapping.
+func (t *TagsPatch) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		return value.Decode(&t.Literal)
+	}
+
+	var directive struct {
+		Add    []string `yaml:"$add"`
+		Remove []string `yaml:"$remove"`
+	}
+	if err := value.Decode(&directive); err != nil {
+		return err
+	}
+	t.Add = directive.Add
+	t.Remove = directive.Remove
+	return nil
+}
+
+// Apply returns base with this patch applied. A patch is a $add/$remove
+// directive when it carries either; otherwise (including a patch built
+// directly in Go rather than unmarshaled) it is a literal replacement.
+func (t *TagsPatch) Apply(base []string) []string {
+	if t == nil {
+		return base
+	}
+	if len(t.Add) == 0 && len(t.Remove) == 0 {
+		return t.Literal
+	}
+
+	result := make([]string, 0, len(base)+len(t.Add))
+	result = append(result, base...)
+	for _, tag := range t.Add {
+		found := false
+		for _, existing := range result {
+			if existing == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, tag)
+		}
+	}
+	if len(t.Remove) == 0 {
+		return result
+	}
+
+	remove := make(map[string]bool, len(t.Remove))
+	for _, tag := range t.Remove {
+		remove[tag] = true
+	}
+	filtered := result[:0]
+	for _, tag := range result {
+		if !remove[tag] {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered
+}
+
+// RulePatch is one `rules:` entry in a .local.yaml patch file. Fields left
+// zero are not applied, so a patch only needs to name the fields it changes.
+// $patch defaults to "merge"; set it to "delete" to remove a rule by ID/name.
+type RulePatch struct {
+	ID       string        `yaml:"id,omitempty"`
+	Name     string        `yaml:"name,omitempty"`
+	Priority int           `yaml:"priority,omitempty"`
+	Content  *ContentPatch `yaml:"content,omitempty"`
+	Tags     *TagsPatch    `yaml:"tags,omitempty"`
+	When     string        `yaml:"when,omitempty"`
+	Patch    string        `yaml:"$patch,omitempty"`
+}
+
+func (p RulePatch) key() string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return p.Name
+}
+
+// SectionPatch is the Section equivalent of RulePatch.
+type SectionPatch struct {
+	ID       string        `yaml:"id,omitempty"`
+	Title    string        `yaml:"title,omitempty"`
+	Priority int           `yaml:"priority,omitempty"`
+	Content  *ContentPatch `yaml:"content,omitempty"`
+	Tags     *TagsPatch    `yaml:"tags,omitempty"`
+	When     string        `yaml:"when,omitempty"`
+	Patch    string        `yaml:"$patch,omitempty"`
+}
+
+func (p SectionPatch) key() string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return p.Title
+}
+
+// FieldSource records, for one rule or section (identified by Key, its
+// ID/name or ID/title), which file contributed the final value of each
+// field a patch touched. Used by `validate --explain-merge`.
+type FieldSource struct {
+	Key    string
+	Fields map[string]string // field name -> contributing file
+}
+
+// PatchRules applies patches on top of base, keyed by ID (falling back to
+// name), the same key convention MergeRules uses. source names the patch
+// file and is recorded into *explain for every field a patch sets; explain
+// may be nil to skip provenance tracking.
+func PatchRules(base []Rule, source string, patches []RulePatch, explain *[]FieldSource) []Rule {
+	ruleMap := make(map[string]Rule, len(base))
+	order := make([]string, 0, len(base))
+	for _, rule := range base {
+		key := rule.Name
+		if rule.ID != "" {
+			key = rule.ID
+		}
+		ruleMap[key] = rule
+		order = append(order, key)
+	}
+
+	for _, patch := range patches {
+		key := patch.key()
+		if patch.Patch == "delete" {
+			delete(ruleMap, key)
+			continue
+		}
+
+		rule, existed := ruleMap[key]
+		fields := make(map[string]string)
+
+		if patch.ID != "" {
+			rule.ID = patch.ID
+		}
+		if patch.Name != "" {
+			rule.Name = patch.Name
+			fields["name"] = source
+		}
+		if patch.Priority != 0 {
+			rule.Priority = patch.Priority
+			fields["priority"] = source
+		}
+		if patch.Content != nil {
+			rule.Content = patch.Content.Apply(rule.Content)
+			fields["content"] = source
+		}
+		if patch.Tags != nil {
+			rule.Tags = patch.Tags.Apply(rule.Tags)
+			fields["tags"] = source
+		}
+		if patch.When != "" {
+			rule.When = patch.When
+			fields["when"] = source
+		}
+
+		if !existed {
+			order = append(order, key)
+		}
+		ruleMap[key] = rule
+
+		if explain != nil && len(fields) > 0 {
+			*explain = append(*explain, FieldSource{Key: key, Fields: fields})
+		}
+	}
+
+	result := make([]Rule, 0, len(order))
+	for _, key := range order {
+		if rule, ok := ruleMap[key]; ok {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// PatchSections is the Section equivalent of PatchRules.
+func PatchSections(base []Section, source string, patches []SectionPatch, explain *[]FieldSource) []Section {
+	sectionMap := make(map[string]Section, len(base))
+	order := make([]string, 0, len(base))
+	for _, section := range base {
+		key := section.Title
+		if section.ID != "" {
+			key = section.ID
+		}
+		sectionMap[key] = section
+		order = append(order, key)
+	}
+
+	for _, patch := range patches {
+		key := patch.key()
+		if patch.Patch == "delete" {
+			delete(sectionMap, key)
+			continue
+		}
+
+		section, existed := sectionMap[key]
+		fields := make(map[string]string)
+
+		if patch.ID != "" {
+			section.ID = patch.ID
+		}
+		if patch.Title != "" {
+			section.Title = patch.Title
+			fields["title"] = source
+		}
+		if patch.Priority != 0 {
+			section.Priority = patch.Priority
+			fields["priority"] = source
+		}
+		if patch.Content != nil {
+			section.Content = patch.Content.Apply(section.Content)
+			fields["content"] = source
+		}
+		if patch.Tags != nil {
+			section.Tags = patch.Tags.Apply(section.Tags)
+			fields["tags"] = source
+		}
+		if patch.When != "" {
+			section.When = patch.When
+			fields["when"] = source
+		}
+
+		if !existed {
+			order = append(order, key)
+		}
+		sectionMap[key] = section
+
+		if explain != nil && len(fields) > 0 {
+			*explain = append(*explain, FieldSource{Key: key, Fields: fields})
+		}
+	}
+
+	result := make([]Section, 0, len(order))
+	for _, key := range order {
+		if section, ok := sectionMap[key]; ok {
+			result = append(result, section)
+		}
+	}
+	return result
+}
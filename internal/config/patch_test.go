@@ -0,0 +1,173 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestPatchRules(t *testing.T) {
+	t.Parallel()
+
+	base := []config.Rule{
+		{ID: "rule1", Name: "Rule 1", Content: "original content", Priority: 5},
+		{ID: "rule2", Name: "Rule 2", Content: "untouched"},
+	}
+
+	tests := []struct {
+		name     string
+		patches  []config.RulePatch
+		expected []config.Rule
+	}{
+		{
+			name: "merge patch overrides only named fields",
+			patches: []config.RulePatch{
+				{ID: "rule1", Name: "Rule 1 Renamed"},
+			},
+			expected: []config.Rule{
+				{ID: "rule1", Name: "Rule 1 Renamed", Content: "original content", Priority: 5},
+				{ID: "rule2", Name: "Rule 2", Content: "untouched"},
+			},
+		},
+		{
+			name: "content literal patch overrides content only",
+			patches: []config.RulePatch{
+				{ID: "rule1", Content: &config.ContentPatch{Literal: "original content"}},
+			},
+			expected: base,
+		},
+		{
+			name: "tags add/remove patch",
+			patches: []config.RulePatch{
+				{ID: "rule2", Tags: &config.TagsPatch{Add: []string{"security"}, Remove: []string{"draft"}}},
+			},
+			expected: []config.Rule{
+				{ID: "rule1", Name: "Rule 1", Content: "original content", Priority: 5},
+				{ID: "rule2", Name: "Rule 2", Content: "untouched", Tags: []string{"security"}},
+			},
+		},
+		{
+			name: "delete patch removes a rule",
+			patches: []config.RulePatch{
+				{ID: "rule2", Patch: "delete"},
+			},
+			expected: []config.Rule{
+				{ID: "rule1", Name: "Rule 1", Content: "original content", Priority: 5},
+			},
+		},
+		{
+			name: "patch with unknown ID adds a new rule",
+			patches: []config.RulePatch{
+				{ID: "rule3", Name: "Rule 3", Content: &config.ContentPatch{Literal: "new rule"}},
+			},
+			expected: []config.Rule{
+				{ID: "rule1", Name: "Rule 1", Content: "original content", Priority: 5},
+				{ID: "rule2", Name: "Rule 2", Content: "untouched"},
+				{ID: "rule3", Name: "Rule 3", Content: "new rule"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := config.PatchRules(base, "test.local.yaml", tt.patches, nil)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestPatchRulesExplain(t *testing.T) {
+	t.Parallel()
+
+	base := []config.Rule{{ID: "rule1", Name: "Rule 1", Content: "original"}}
+	var explain []config.FieldSource
+
+	config.PatchRules(base, "team.local.yaml", []config.RulePatch{
+		{ID: "rule1", Content: &config.ContentPatch{Literal: "new content"}},
+	}, &explain)
+
+	require.Len(t, explain, 1)
+	assert.Equal(t, "rule1", explain[0].Key)
+	assert.Equal(t, "team.local.yaml", explain[0].Fields["content"])
+}
+
+func TestContentPatchAppend(t *testing.T) {
+	t.Parallel()
+
+	var patch config.ContentPatch
+	require.NoError(t, yaml.Unmarshal([]byte(`$append: "\nmore"`), &patch))
+	assert.Equal(t, "base\nmore", patch.Apply("base"))
+}
+
+func TestContentPatchLiteral(t *testing.T) {
+	t.Parallel()
+
+	var patch config.ContentPatch
+	require.NoError(t, yaml.Unmarshal([]byte(`"replacement"`), &patch))
+	assert.Equal(t, "replacement", patch.Apply("base"))
+}
+
+func TestTagsPatchAddRemove(t *testing.T) {
+	t.Parallel()
+
+	var patch config.TagsPatch
+	require.NoError(t, yaml.Unmarshal([]byte(`$add: [security]
+$remove: [draft]`), &patch))
+	assert.Equal(t, []string{"compliance", "security"}, patch.Apply([]string{"draft", "compliance"}))
+}
+
+func TestTagsPatchAddIsIdempotent(t *testing.T) {
+	t.Parallel()
+
+	var patch config.TagsPatch
+	require.NoError(t, yaml.Unmarshal([]byte(`$add: [security]`), &patch))
+	assert.Equal(t, []string{"security"}, patch.Apply([]string{"security"}))
+}
+
+func TestTagsPatchLiteral(t *testing.T) {
+	t.Parallel()
+
+	var patch config.TagsPatch
+	require.NoError(t, yaml.Unmarshal([]byte(`[security, compliance]`), &patch))
+	assert.Equal(t, []string{"security", "compliance"}, patch.Apply([]string{"draft"}))
+}
+
+func TestLoadConfigWithExplain(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.yaml"), []byte(`metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "rule1"
+    name: "Rule 1"
+    content: "original content"
+    priority: 5
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.local.yaml"), []byte(`rules:
+  - id: "rule1"
+    content:
+      $append: "\n\nteam addendum"
+`), 0o644))
+
+	cfg, explain, err := config.LoadConfigWithExplain(filepath.Join(tmpDir, "test.yaml"))
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "original content\n\nteam addendum", cfg.Rules[0].Content)
+	assert.Equal(t, 5, cfg.Rules[0].Priority)
+
+	require.Len(t, explain.Rules, 1)
+	assert.Equal(t, "rule1", explain.Rules[0].Key)
+	assert.Equal(t, "test.local.yaml", explain.Rules[0].Fields["content"])
+}
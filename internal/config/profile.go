@@ -0,0 +1,89 @@
+package config
+
+import "fmt"
+
+// LoadConfigWithProfile loads and fully resolves filename (includes, local
+// overrides, env overlay - the same as LoadConfigWithIncludes), then applies
+// the named profile's overlay chain on top. An empty profileName returns the
+// base config unchanged. The chain is resolved by following each profile's
+// Extends from profileName up to its root, then applying overlays in
+// root-to-leaf order so the most specific profile wins; rules left with
+// Disabled: true after all overlays are applied are dropped.
+func LoadConfigWithProfile(filename, profileName string) (*Config, error) {
+	cfg, err := LoadConfigWithIncludes(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if profileName == "" {
+		return cfg, nil
+	}
+
+	chain, err := resolveProfileChain(cfg.Profiles, profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := cfg.Rules
+	sections := cfg.Sections
+	outputs := cfg.Outputs
+	for _, name := range chain {
+		overlay := cfg.Profiles[name]
+		rules = MergeRules(rules, overlay.Rules)
+		sections = MergeSections(sections, overlay.Sections)
+		if len(overlay.Outputs) > 0 {
+			outputs = MergeOutputs(outputs, overlay.Outputs)
+		}
+	}
+
+	cfg.Rules = dropDisabledRules(rules)
+	cfg.Sections = sections
+	cfg.Outputs = outputs
+	cfg.Profiles = nil
+
+	return cfg, nil
+}
+
+// resolveProfileChain walks name's Extends links up to its root ancestor and
+// returns the chain in root-to-leaf application order. An Extends value that
+// isn't itself a key in profiles (including the conventional "base") simply
+// ends the walk.
+func resolveProfileChain(profiles map[string]Profile, name string) ([]string, error) {
+	if _, ok := profiles[name]; !ok {
+		return nil, fmt.Errorf("profile %q is not defined", name)
+	}
+
+	var chain []string
+	visited := make(map[string]bool)
+	current := name
+	for current != "" {
+		if visited[current] {
+			return nil, fmt.Errorf("circular profile extends detected: %s", current)
+		}
+		visited[current] = true
+
+		profile, ok := profiles[current]
+		if !ok {
+			break
+		}
+		chain = append(chain, current)
+		current = profile.Extends
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+
+	return chain, nil
+}
+
+// dropDisabledRules returns rules with every Disabled entry removed.
+func dropDisabledRules(rules []Rule) []Rule {
+	result := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if !rule.Disabled {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
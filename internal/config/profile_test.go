@@ -0,0 +1,200 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestLoadConfigWithProfile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		config      string
+		profileName string
+		wantErr     bool
+		check       func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name: "empty profile name returns the base config",
+			config: `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "rule1"
+    name: "Rule 1"
+    content: "base content"
+profiles:
+  ci:
+    rules:
+      - id: "rule1"
+        name: "Rule 1 (ci)"
+        content: "ci content"`,
+			profileName: "",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "base content", cfg.Rules[0].Content)
+			},
+		},
+		{
+			name: "profile overrides only outputs",
+			config: `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "rule1"
+    name: "Rule 1"
+    content: "base content"
+profiles:
+  ci:
+    outputs:
+      - file: "ci.md"`,
+			profileName: "ci",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Len(t, cfg.Outputs, 2)
+				assert.Equal(t, "CLAUDE.md", cfg.Outputs[0].File)
+				assert.Equal(t, "ci.md", cfg.Outputs[1].File)
+				require.Len(t, cfg.Rules, 1)
+				assert.Equal(t, "base content", cfg.Rules[0].Content)
+			},
+		},
+		{
+			name: "profile disables a base rule",
+			config: `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "rule1"
+    name: "Rule 1"
+    content: "base content"
+  - id: "rule2"
+    name: "Rule 2"
+    content: "kept"
+profiles:
+  minimal:
+    rules:
+      - id: "rule1"
+        name: "Rule 1"
+        content: "base content"
+        disabled: true`,
+			profileName: "minimal",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Len(t, cfg.Rules, 1)
+				assert.Equal(t, "rule2", cfg.Rules[0].ID)
+			},
+		},
+		{
+			name: "profile chain three deep applies root to leaf",
+			config: `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "rule1"
+    name: "Rule 1"
+    content: "base content"
+profiles:
+  base:
+    rules:
+      - id: "rule1"
+        name: "Rule 1"
+        content: "base profile content"
+  ci:
+    extends: base
+    rules:
+      - id: "rule1"
+        name: "Rule 1"
+        content: "ci content"
+  local:
+    extends: ci
+    rules:
+      - id: "rule2"
+        name: "Rule 2"
+        content: "local-only rule"`,
+			profileName: "local",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				require.Len(t, cfg.Rules, 2)
+				assert.Equal(t, "ci content", cfg.Rules[0].Content)
+				assert.Equal(t, "local-only rule", cfg.Rules[1].Content)
+			},
+		},
+		{
+			name: "extends naming a literal, undeclared profile grounds the chain",
+			config: `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+rules:
+  - id: "rule1"
+    name: "Rule 1"
+    content: "base content"
+profiles:
+  ci:
+    extends: base
+    rules:
+      - id: "rule1"
+        name: "Rule 1"
+        content: "ci content"`,
+			profileName: "ci",
+			check: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+				assert.Equal(t, "ci content", cfg.Rules[0].Content)
+			},
+		},
+		{
+			name: "unknown profile name is an error",
+			config: `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"`,
+			profileName: "nonexistent",
+			wantErr:     true,
+		},
+		{
+			name: "circular extends is an error",
+			config: `metadata:
+  name: "main"
+outputs:
+  - file: "CLAUDE.md"
+profiles:
+  a:
+    extends: b
+  b:
+    extends: a`,
+			profileName: "a",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			mainFile := filepath.Join(tmpDir, "test.yaml")
+			require.NoError(t, os.WriteFile(mainFile, []byte(tt.config), 0o644))
+
+			cfg, err := config.LoadConfigWithProfile(mainFile, tt.profileName)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
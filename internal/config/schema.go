@@ -4,6 +4,8 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
@@ -14,12 +16,56 @@ import (
 //go:embed schema/ai-rules-v1.schema.json
 var schemaJSON string
 
-// ValidateWithSchema validates a configuration against the JSON Schema.
-func ValidateWithSchema(configData []byte) error {
+// ValidationError is one schema violation, carrying both the logical Path
+// into the config (e.g. "rules[3].priority") and, when File is known, the
+// exact source position of the offending value plus a snippet of it.
+type ValidationError struct {
+	Path    string
+	Message string
+	File    string
+	Line    int
+	Column  int
+	Snippet string
+}
+
+// Error renders as "file:line:col: path message" once a position is known,
+// falling back to "path: message" otherwise (e.g. from ValidateConfigWithSchema,
+// which validates an in-memory Config with no source file to point at), and
+// appends the offending source line with a "^" underline when a snippet was
+// captured.
+func (e ValidationError) Error() string {
+	if e.File == "" || e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	msg := fmt.Sprintf("%s:%d:%d: %s %s", e.File, e.Line, e.Column, e.Path, e.Message)
+	if e.Snippet == "" {
+		return msg
+	}
+	return msg + "\n" + e.Snippet
+}
+
+// ValidationErrors is a non-empty set of schema violations, returned by
+// ValidateWithSchema. It satisfies error so existing
+// `if err := ValidateWithSchema(...); err != nil` callers keep working
+// unchanged; callers wanting structured access can type-assert to it.
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = "- " + e.Error()
+	}
+	return fmt.Sprintf("configuration validation failed:\n%s", strings.Join(lines, "\n"))
+}
+
+// ValidateWithSchema validates a configuration against the JSON Schema,
+// translating any violations back into the source YAML's file:line:col via
+// a yaml.Node parse of configData.
+func ValidateWithSchema(configData []byte, filename string) error {
 	// Convert YAML to JSON for schema validation
 	var yamlData any
 	if err := yaml.Unmarshal(configData, &yamlData); err != nil {
-		return fmt.Errorf("failed to parse YAML: %w", err)
+		return wrapYAMLError(filename, configData, err)
 	}
 
 	jsonData, err := json.Marshal(ConvertYAMLToJSON(yamlData))
@@ -37,16 +83,107 @@ func ValidateWithSchema(configData []byte) error {
 		return fmt.Errorf("schema validation error: %w", err)
 	}
 
-	if !result.Valid() {
-		var errors []string
-		for _, desc := range result.Errors() {
-			errors = append(errors, fmt.Sprintf("- %s", desc))
+	if result.Valid() {
+		return nil
+	}
+
+	var root yaml.Node
+	positions := positionIndex{}
+	if err := yaml.Unmarshal(configData, &root); err == nil {
+		indexPositions(&root, "", positions)
+	}
+
+	errs := make(ValidationErrors, 0, len(result.Errors()))
+	for _, desc := range result.Errors() {
+		path := normalizeFieldPath(desc.Field())
+		verr := ValidationError{
+			Path:    displayPath(path),
+			Message: desc.Description(),
+			File:    filename,
+		}
+		if pos, ok := positions[path]; ok {
+			verr.Line, verr.Column = pos.line, pos.column
+			verr.Snippet = snippetAt(string(configData), pos.line, pos.column)
+		}
+		errs = append(errs, verr)
+	}
+
+	return errs
+}
+
+// yamlPos is the source position of one node indexed by positionIndex.
+type yamlPos struct {
+	line   int
+	column int
+}
+
+// positionIndex maps a dotted field path matching gojsonschema's Field()
+// convention (e.g. "rules.0.priority") to the source position of that value.
+type positionIndex map[string]yamlPos
+
+// indexPositions walks node, recording the position of every value reachable
+// under path (the path accumulated so far, in gojsonschema's dotted/indexed
+// convention) into idx.
+func indexPositions(node *yaml.Node, path string, idx positionIndex) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			indexPositions(node.Content[0], path, idx)
+		}
+	case yaml.MappingNode:
+		idx[path] = yamlPos{node.Line, node.Column}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinFieldPath(path, key.Value)
+			idx[childPath] = yamlPos{value.Line, value.Column}
+			indexPositions(value, childPath, idx)
+		}
+	case yaml.SequenceNode:
+		idx[path] = yamlPos{node.Line, node.Column}
+		for i, item := range node.Content {
+			childPath := joinFieldPath(path, strconv.Itoa(i))
+			idx[childPath] = yamlPos{item.Line, item.Column}
+			indexPositions(item, childPath, idx)
 		}
-		return fmt.Errorf("configuration validation failed:\n%s",
-			stringSliceToString(errors, "\n"))
+	default:
+		idx[path] = yamlPos{node.Line, node.Column}
+	}
+}
+
+func joinFieldPath(path, segment string) string {
+	if path == "" {
+		return segment
+	}
+	return path + "." + segment
+}
+
+// normalizeFieldPath strips gojsonschema's "(root)" field marker so the
+// result matches the dotted paths recorded by indexPositions.
+func normalizeFieldPath(field string) string {
+	field = strings.TrimPrefix(field, "(root)")
+	return strings.TrimPrefix(field, ".")
+}
+
+// displayPath renders a dotted field path (e.g. "rules.0.priority") in
+// bracketed-index notation matching idiomatic Go field access, e.g.
+// "rules[0].priority", for display in ValidationError.
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
 	}
 
-	return nil
+	var b strings.Builder
+	for _, segment := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(segment); err == nil {
+			b.WriteString("[" + segment + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
 }
 
 // ConvertYAMLToJSON converts YAML data to JSON-compatible format.
@@ -67,18 +204,6 @@ func ConvertYAMLToJSON(i any) any {
 	return i
 }
 
-// stringSliceToString joins a slice of strings.
-func stringSliceToString(slice []string, sep string) string {
-	result := ""
-	for i, s := range slice {
-		if i > 0 {
-			result += sep
-		}
-		result += s
-	}
-	return result
-}
-
 // ValidateConfigWithSchema validates a Config struct against the schema.
 func ValidateConfigWithSchema(cfg *Config) error {
 	// Marshal config to YAML first
@@ -87,5 +212,5 @@ func ValidateConfigWithSchema(cfg *Config) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	return ValidateWithSchema(yamlData)
+	return ValidateWithSchema(yamlData, "")
 }
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -107,7 +108,7 @@ rules:
     content: "Content"
 `,
 			wantErr: true,
-			errMsg:  "rules.0.priority",
+			errMsg:  "rules[0].priority",
 		},
 		{
 			name: "invalid_priority_zero",
@@ -122,7 +123,7 @@ rules:
     content: "Content"
 `,
 			wantErr: true,
-			errMsg:  "rules.0.priority",
+			errMsg:  "rules[0].priority",
 		},
 		{
 			name: "invalid_priority_negative",
@@ -137,7 +138,7 @@ rules:
     content: "Content"
 `,
 			wantErr: true,
-			errMsg:  "rules.0.priority",
+			errMsg:  "rules[0].priority",
 		},
 		{
 			name: "missing_rule_name",
@@ -150,7 +151,7 @@ rules:
   - content: "Content"
 `,
 			wantErr: true,
-			errMsg:  "rules.0: name is required",
+			errMsg:  "rules[0]: name is required",
 		},
 		{
 			name: "missing_rule_content",
@@ -163,7 +164,7 @@ rules:
   - name: "Rule"
 `,
 			wantErr: true,
-			errMsg:  "rules.0: content is required",
+			errMsg:  "rules[0]: content is required",
 		},
 		{
 			name: "invalid_version_format",
@@ -198,7 +199,7 @@ outputs:
     template: "123-invalid"
 `,
 			wantErr: true,
-			errMsg:  "outputs.0.template",
+			errMsg:  "outputs[0].template",
 		},
 		{
 			name: "additional_properties",
@@ -239,7 +240,7 @@ sections:
   - content: "Some content"
 `,
 			wantErr: true,
-			errMsg:  "sections.0: title is required",
+			errMsg:  "sections[0]: title is required",
 		},
 		{
 			name: "section_missing_content",
@@ -252,7 +253,7 @@ sections:
   - title: "Introduction"
 `,
 			wantErr: true,
-			errMsg:  "sections.0: content is required",
+			errMsg:  "sections[0]: content is required",
 		},
 		{
 			name: "section_invalid_priority",
@@ -267,13 +268,13 @@ sections:
     content: "Welcome"
 `,
 			wantErr: true,
-			errMsg:  "sections.0.priority",
+			errMsg:  "sections[0].priority",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := config.ValidateWithSchema([]byte(tt.yaml))
+			err := config.ValidateWithSchema([]byte(tt.yaml), "")
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -287,6 +288,51 @@ sections:
 	}
 }
 
+func TestValidateWithSchema_ReportsSourcePosition(t *testing.T) {
+	yamlData := []byte(`metadata:
+  name: "Test"
+outputs:
+  - file: "output.md"
+rules:
+  - name: "Rule"
+    priority: -5
+    content: "Content"
+`)
+
+	err := config.ValidateWithSchema(yamlData, "ai_rulez.yaml")
+	require.Error(t, err)
+
+	var verrs config.ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	require.Len(t, verrs, 1)
+
+	verr := verrs[0]
+	assert.Equal(t, "rules[0].priority", verr.Path)
+	assert.Equal(t, "ai_rulez.yaml", verr.File)
+	assert.Equal(t, 7, verr.Line)
+	assert.Equal(t, 15, verr.Column)
+	assert.True(t, strings.HasPrefix(verr.Error(), "ai_rulez.yaml:7:15: rules[0].priority "+verr.Message), verr.Error())
+	assert.Contains(t, verr.Snippet, "priority: -5")
+	assert.Contains(t, verr.Snippet, "^")
+}
+
+func TestValidateConfigWithSchema_NoFileLeavesPositionUnset(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs:  []config.Output{{File: "output.md"}},
+		Rules:    []config.Rule{{Name: "Rule", Priority: -5, Content: "Content"}},
+	}
+
+	err := config.ValidateConfigWithSchema(cfg)
+	require.Error(t, err)
+
+	var verrs config.ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "", verrs[0].File)
+	assert.Equal(t, "rules[0].priority: "+verrs[0].Message, verrs[0].Error())
+}
+
 func TestConvertYAMLToJSON(t *testing.T) {
 	input := map[any]any{
 		"string": "value",
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ConfigError wraps a YAML decode failure - malformed syntax or a type
+// mismatch caught by yaml.Unmarshal itself, as opposed to ValidationError's
+// schema violations - with the source line yaml.v3 embeds in its error text
+// (e.g. "yaml: line 5: did not find expected key"), plus a snippet of the
+// offending line. It's ConfigError's counterpart for YAML decode errors,
+// alongside ValidationError for schema violations and
+// generator.TemplateError for template failures.
+type ConfigError struct {
+	File    string
+	Line    int
+	Message string
+	Snippet string
+	Err     error
+}
+
+// yamlLinePattern extracts the 1-indexed line number yaml.v3 embeds in its
+// error text (e.g. "yaml: line 5: did not find expected key", or the first
+// line of a multi-error "yaml: unmarshal errors:\n  line 3: ...").
+var yamlLinePattern = regexp.MustCompile(`line (\d+):\s*(.*)`)
+
+// wrapYAMLError wraps err (from yaml.Unmarshal(data, ...) against filename)
+// as a ConfigError if err's message carries a yaml.v3 "line N:" position,
+// attaching a snippet of data's offending line. err is returned unchanged
+// if no position can be found, so callers can use wrapYAMLError
+// unconditionally.
+func wrapYAMLError(filename string, data []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	m := yamlLinePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	return &ConfigError{
+		File:    filename,
+		Line:    line,
+		Message: strings.TrimSpace(m[2]),
+		Snippet: snippetAt(string(data), line, 0),
+		Err:     err,
+	}
+}
+
+// Error renders as "file:line: message", followed by the offending source
+// line and a "^" underline when a snippet was captured - the same
+// file:line[:col] convention ValidationError uses for schema violations.
+func (e *ConfigError) Error() string {
+	msg := fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	if e.Snippet == "" {
+		return msg
+	}
+	return msg + "\n" + e.Snippet
+}
+
+// Unwrap exposes the underlying yaml.Unmarshal error to errors.Is/As.
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// snippetAt returns line (1-indexed) of src together with a "^" underline,
+// or "" if line falls outside src - the short context Hugo's
+// herrors.FileError shows under a config or template error. column (also
+// 1-indexed) positions the underline directly when known (e.g. from a YAML
+// node's position); 0 falls back to underlining the line's first non-blank
+// character.
+func snippetAt(src string, line, column int) string {
+	lines := strings.Split(src, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	text := lines[line-1]
+	indent := column - 1
+	if column <= 0 {
+		indent = len(text) - len(strings.TrimLeft(text, " \t"))
+	}
+	if indent < 0 {
+		indent = 0
+	}
+	return fmt.Sprintf("    %s\n    %s^", text, strings.Repeat(" ", indent))
+}
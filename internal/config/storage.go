@@ -0,0 +1,196 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Revision identifies the on-disk state of a config file at the moment it
+// was loaded: the sha256 of its raw bytes, hex-encoded. Save compares the
+// current on-disk revision against the one a caller loaded, so two
+// concurrent load-mutate-save cycles (e.g. two MCP clients, or an MCP
+// client and the CLI, mutating the same config) can detect they raced
+// instead of one silently overwriting the other.
+type Revision string
+
+// ErrConflict is returned by Storage.Save when path's on-disk revision no
+// longer matches expectedRevision - the file changed since the caller's
+// Load. Callers (an MCP handler, in particular) should surface this as a
+// "conflict" tool error so the caller can re-fetch and retry rather than
+// clobbering someone else's write.
+var ErrConflict = errors.New("config file has changed since it was loaded")
+
+// StorageEvent notifies a Storage.Watch subscriber that path changed on
+// disk. Unlike Watcher, it makes no attempt to reload or validate the new
+// content - callers that want a reloaded, schema-validated Config should
+// use Watcher instead.
+type StorageEvent struct {
+	Path string
+}
+
+// Storage abstracts where and how a Config is persisted, so embedders can
+// swap the default file-based implementation for something else (an
+// in-memory Storage for tests, a git-backed one) without touching the MCP
+// handlers or CLI commands built against it.
+type Storage interface {
+	// Load reads and parses the Config at path, returning its revision
+	// alongside it so a later Save can detect a conflicting write.
+	Load(path string) (*Config, Revision, error)
+
+	// Save writes cfg to path. If expectedRevision is non-empty and
+	// doesn't match path's current on-disk revision, Save returns
+	// ErrConflict without writing anything.
+	Save(path string, cfg *Config, expectedRevision Revision) error
+
+	// Watch notifies on path's changes. The returned channel is closed
+	// once the underlying file watcher can no longer be used.
+	Watch(path string) <-chan StorageEvent
+}
+
+// FileStorage is the default Storage: a plain file on the local
+// filesystem, written atomically (temp file + rename) with the parent
+// directory fsynced so the rename itself is durable.
+type FileStorage struct{}
+
+// NewFileStorage returns the default file-based Storage.
+func NewFileStorage() FileStorage {
+	return FileStorage{}
+}
+
+// Load reads and parses the Config at path via LoadConfig, returning the
+// sha256 revision of its raw on-disk bytes.
+func (FileStorage) Load(path string) (*Config, Revision, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rev, err := fileRevision(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return cfg, rev, nil
+}
+
+// Save writes cfg to path via a temp file in the same directory followed
+// by an atomic rename, fsyncing the parent directory so the rename
+// survives a crash. If expectedRevision is non-empty, Save first checks it
+// against path's current on-disk revision and returns ErrConflict without
+// writing if they differ (path doesn't exist yet is treated as matching
+// an empty expectedRevision).
+func (FileStorage) Save(path string, cfg *Config, expectedRevision Revision) error {
+	if expectedRevision != "" {
+		current, err := fileRevision(path)
+		if err != nil {
+			return err
+		}
+		if current != expectedRevision {
+			return fmt.Errorf("%w: %s", ErrConflict, path)
+		}
+	}
+
+	data, err := marshalConfig(cfg, path)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".ai-rulez-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file %s: %w", tmpPath, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temp file %s: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file %s: %w", tmpPath, err)
+	}
+	if err := os.Chmod(tmpPath, 0o644); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", tmpPath, path, err)
+	}
+
+	if dirFile, err := os.Open(dir); err == nil {
+		_ = dirFile.Sync()
+		dirFile.Close()
+	}
+
+	return nil
+}
+
+// Watch starts an fsnotify watch on path, sending a StorageEvent each time
+// it's written, created, removed, or renamed. The channel is closed when
+// the underlying watcher's event stream ends.
+func (FileStorage) Watch(path string) <-chan StorageEvent {
+	events := make(chan StorageEvent)
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		close(events)
+		return events
+	}
+	if err := fsWatcher.Add(path); err != nil {
+		fsWatcher.Close()
+		close(events)
+		return events
+	}
+
+	go func() {
+		defer fsWatcher.Close()
+		defer close(events)
+		for {
+			select {
+			case event, ok := <-fsWatcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+					continue
+				}
+				events <- StorageEvent{Path: path}
+			case _, ok := <-fsWatcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
+
+// fileRevision returns the sha256 revision of path's current on-disk
+// bytes, or "" if path doesn't exist yet.
+func fileRevision(path string) (Revision, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return Revision(hex.EncodeToString(sum[:])), nil
+}
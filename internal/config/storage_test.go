@@ -0,0 +1,94 @@
+package config_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func writeTestConfig(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, config.SaveConfig(&config.Config{
+		Rules: []config.Rule{{Name: "r1", Content: "c1", Priority: 1}},
+	}, path))
+}
+
+func TestFileStorageLoadSaveRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeTestConfig(t, path)
+
+	storage := config.NewFileStorage()
+	cfg, rev, err := storage.Load(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, rev)
+	require.Len(t, cfg.Rules, 1)
+
+	cfg.Rules = append(cfg.Rules, config.Rule{Name: "r2", Content: "c2", Priority: 1})
+	require.NoError(t, storage.Save(path, cfg, rev))
+
+	reloaded, newRev, err := storage.Load(path)
+	require.NoError(t, err)
+	assert.Len(t, reloaded.Rules, 2)
+	assert.NotEqual(t, rev, newRev)
+}
+
+func TestFileStorageSaveConflict(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeTestConfig(t, path)
+
+	storage := config.NewFileStorage()
+	cfg, rev, err := storage.Load(path)
+	require.NoError(t, err)
+
+	// Someone else saves in between.
+	cfg.Rules[0].Content = "changed by someone else"
+	require.NoError(t, storage.Save(path, cfg, rev))
+
+	// The original caller's stale revision should now conflict.
+	cfg.Rules[0].Content = "my change"
+	err = storage.Save(path, cfg, rev)
+	assert.ErrorIs(t, err, config.ErrConflict)
+}
+
+func TestFileStorageSaveWithoutExpectedRevision(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	storage := config.NewFileStorage()
+
+	cfg := &config.Config{Rules: []config.Rule{{Name: "r1", Content: "c1", Priority: 1}}}
+	require.NoError(t, storage.Save(path, cfg, ""))
+
+	reloaded, _, err := storage.Load(path)
+	require.NoError(t, err)
+	assert.Len(t, reloaded.Rules, 1)
+}
+
+func TestFileStorageWatch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeTestConfig(t, path)
+
+	storage := config.NewFileStorage()
+	events := storage.Watch(path)
+
+	writeTestConfig(t, path)
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok)
+		assert.Equal(t, path, event.Path)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a storage event")
+	}
+}
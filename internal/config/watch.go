@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/modules"
+)
+
+// WatchedFiles returns configFile plus every local (non-remote) include
+// file and conf.d fragment it resolves to, recursively, for a file watcher
+// to monitor for `ai-rulez watch`. Remote (git::/http(s)://) includes are
+// skipped: a watcher cares about local edits, not the fetched module cache.
+// A bare include name resolved against a project Layout, or a glob/directory
+// include that legitimately matches nothing, is skipped rather than
+// erroring out of the whole watch, since any of those missing a file
+// shouldn't stop watching everything else. The returned list has no
+// duplicates.
+func WatchedFiles(configFile string) ([]string, error) {
+	absPath, err := filepath.Abs(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for %s: %w", configFile, err)
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	if err := collectWatchedFiles(absPath, seen, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func collectWatchedFiles(filename string, seen map[string]bool, files *[]string) error {
+	absPath, err := filepath.Abs(filename)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for %s: %w", filename, err)
+	}
+	if seen[absPath] {
+		return nil
+	}
+	seen[absPath] = true
+	*files = append(*files, absPath)
+
+	if info, err := os.Stat(absPath); err == nil && info.IsDir() {
+		return collectConfDFragments(absPath, seen, files)
+	}
+
+	configDir, err := peekConfigDir(absPath)
+	if err == nil && configDir != "" {
+		if !filepath.IsAbs(configDir) {
+			configDir = filepath.Join(filepath.Dir(absPath), configDir)
+		}
+		if err := collectConfDFragments(configDir, seen, files); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := LoadConfig(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config %s: %w", absPath, err)
+	}
+
+	baseDir := filepath.Dir(absPath)
+	for _, include := range cfg.Includes {
+		if modules.IsRemoteSource(include.Path) {
+			continue
+		}
+
+		resolvedPaths, err := expandIncludePath(include.Path, baseDir)
+		if err != nil {
+			// Bare include names (resolved against a Layout) aren't
+			// understood by expandIncludePath; skip rather than fail the
+			// whole watch over one include this helper can't resolve.
+			continue
+		}
+
+		for _, resolved := range resolvedPaths {
+			if err := collectWatchedFiles(resolved, seen, files); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectConfDFragments adds every conf.d fragment LoadConfigDir would load
+// from dir to files, so editing a fragment in place (without touching the
+// main config file) still triggers a watch regeneration.
+func collectConfDFragments(dir string, seen map[string]bool, files *[]string) error {
+	names, err := confdFragmentNames(dir)
+	if err != nil {
+		// A config_dir that doesn't exist yet (or isn't readable) shouldn't
+		// stop watching everything else; LoadConfig surfaces the real
+		// error once the main config is actually reloaded.
+		return nil
+	}
+
+	for _, name := range names {
+		absPath, err := filepath.Abs(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path for %s: %w", name, err)
+		}
+		if seen[absPath] {
+			continue
+		}
+		seen[absPath] = true
+		*files = append(*files, absPath)
+	}
+
+	return nil
+}
+
+// peekConfigDir reads filename's top-level `config_dir:` value (if any)
+// without fully loading or merging it, so WatchedFiles can add its
+// fragments before LoadConfig clears the field during the merge.
+func peekConfigDir(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+
+	data, err = normalizeToYAML(filename, data)
+	if err != nil {
+		return "", err
+	}
+
+	var peek struct {
+		ConfigDir string `yaml:"config_dir"`
+	}
+	if err := yaml.Unmarshal(data, &peek); err != nil {
+		return "", err
+	}
+	return peek.ConfigDir, nil
+}
@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestWatchedFiles(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	mainPath := filepath.Join(tmpDir, "ai-rulez.yaml")
+	includePath := filepath.Join(tmpDir, "shared.yaml")
+
+	require.NoError(t, os.WriteFile(mainPath, []byte(`
+metadata:
+  name: Main
+outputs:
+  - file: CLAUDE.md
+includes:
+  - shared.yaml
+rules:
+  - name: Main Rule
+    content: Main content
+`), 0o644))
+	require.NoError(t, os.WriteFile(includePath, []byte(`
+rules:
+  - name: Shared Rule
+    content: Shared content
+`), 0o644))
+
+	files, err := config.WatchedFiles(mainPath)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{mainPath, includePath}, files)
+}
+
+func TestWatchedFiles_SkipsRemoteIncludes(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	mainPath := filepath.Join(tmpDir, "ai-rulez.yaml")
+
+	require.NoError(t, os.WriteFile(mainPath, []byte(`
+metadata:
+  name: Main
+outputs:
+  - file: CLAUDE.md
+includes:
+  - "git::https://github.com/example/rules.git"
+`), 0o644))
+
+	files, err := config.WatchedFiles(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{mainPath}, files)
+}
+
+func TestWatchedFiles_IncludesConfDirFragments(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	mainPath := filepath.Join(tmpDir, "ai-rulez.yaml")
+	fragmentsDir := filepath.Join(tmpDir, "conf.d")
+	require.NoError(t, os.Mkdir(fragmentsDir, 0o755))
+	fragmentPath := filepath.Join(fragmentsDir, "10-rules.yaml")
+
+	require.NoError(t, os.WriteFile(mainPath, []byte(`
+metadata:
+  name: Main
+config_dir: conf.d
+outputs:
+  - file: CLAUDE.md
+`), 0o644))
+	require.NoError(t, os.WriteFile(fragmentPath, []byte(`
+rules:
+  - name: Fragment Rule
+    content: Fragment content
+`), 0o644))
+
+	files, err := config.WatchedFiles(mainPath)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{mainPath, fragmentPath}, files)
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchEventType identifies the kind of event a Watcher emits.
+type WatchEventType string
+
+const (
+	// WatcherReloaded fires once a debounced reload has loaded and
+	// schema-validated a new Config successfully.
+	WatcherReloaded WatchEventType = "reloaded"
+	// WatcherError fires when a debounced reload fails to load or validate;
+	// Current keeps returning the last successfully loaded Config.
+	WatcherError WatchEventType = "error"
+)
+
+// WatchEvent is a structured notification Watcher emits on its Events
+// channel, so an editor extension or a future LSP can subscribe to config
+// reloads without depending on internal/generator.
+type WatchEvent struct {
+	Type   WatchEventType
+	Config *Config    // set on WatcherReloaded
+	Diff   ConfigDiff // set on WatcherReloaded: what changed vs. the previous snapshot
+	Err    error      // set on WatcherError
+}
+
+// defaultWatcherDebounce is the delay Watcher waits after the first change
+// in a burst before reloading, so a save that touches several included
+// files (or conf.d fragments) only reloads once.
+const defaultWatcherDebounce = 200 * time.Millisecond
+
+// Watcher watches a config file, every local file it includes, and every
+// conf.d fragment it merges, reloading and schema-validating on each
+// change. It holds the last successfully loaded Config in memory: a reload
+// that fails to load or validate leaves Current() returning that previous
+// good Config instead, so a caller (e.g. the MCP watch_config tool's
+// runConfigWatch) never sees a broken config and never has to guard against one
+// itself. Unlike generator.Watch, Watcher never writes files; generator.Watch
+// runs its own fsnotify loop and does not use it. Watcher is usable
+// standalone by anything that just wants to know when a config becomes
+// valid again (an editor plugin, a future LSP).
+type Watcher struct {
+	configFile string
+	debounce   time.Duration
+	current    *Config
+}
+
+// NewWatcher creates a Watcher for configFile. debounce, when zero, uses
+// defaultWatcherDebounce (200ms).
+func NewWatcher(configFile string, debounce time.Duration) *Watcher {
+	if debounce <= 0 {
+		debounce = defaultWatcherDebounce
+	}
+	return &Watcher{configFile: configFile, debounce: debounce}
+}
+
+// Current returns the last successfully loaded Config, or nil if Run has
+// never completed a successful reload.
+func (w *Watcher) Current() *Config {
+	return w.current
+}
+
+// Run watches w's config file (and its includes/conf.d fragments) until ctx
+// is done, sending a WatcherReloaded or WatcherError event on events after
+// each debounced change. events, when non-nil, is never closed by Run. Run
+// blocks until ctx is done, returning ctx.Err(); it returns earlier with an
+// error if the file watcher cannot be set up, and with nil if the file
+// watcher's channels close.
+func (w *Watcher) Run(ctx context.Context, events chan<- WatchEvent) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	if err := w.sync(fsWatcher); err != nil {
+		return err
+	}
+	w.reload(events, fsWatcher)
+
+	timer := time.NewTimer(w.debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	pending := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			sendWatchEvent(events, WatchEvent{Type: WatcherError, Err: err})
+
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			if !timer.Stop() && pending {
+				<-timer.C
+			}
+			timer.Reset(w.debounce)
+			pending = true
+
+		case <-timer.C:
+			pending = false
+			w.reload(events, fsWatcher)
+		}
+	}
+}
+
+// reload reloads w's config file, keeping w.current unchanged (and leaving
+// existing output files safe to regenerate from) on any failure, then
+// re-syncs the watched file set against the newly loaded config.
+func (w *Watcher) reload(events chan<- WatchEvent, fsWatcher *fsnotify.Watcher) {
+	cfg, err := LoadConfigWithIncludes(w.configFile)
+	if err != nil {
+		sendWatchEvent(events, WatchEvent{Type: WatcherError, Err: err})
+		return
+	}
+
+	if err := ValidateConfigWithSchema(cfg); err != nil {
+		sendWatchEvent(events, WatchEvent{Type: WatcherError, Err: err})
+		return
+	}
+
+	previous := w.current
+	w.current = cfg
+	if err := w.sync(fsWatcher); err != nil {
+		sendWatchEvent(events, WatchEvent{Type: WatcherError, Err: err})
+		return
+	}
+
+	sendWatchEvent(events, WatchEvent{Type: WatcherReloaded, Config: cfg, Diff: DiffConfigs(previous, cfg)})
+}
+
+// sync adds w's config file and every file WatchedFiles resolves it to
+// (includes, conf.d fragments) to fsWatcher. Adding a path already being
+// watched is a harmless no-op.
+func (w *Watcher) sync(fsWatcher *fsnotify.Watcher) error {
+	files, err := WatchedFiles(w.configFile)
+	if err != nil {
+		return fmt.Errorf("failed to collect watched files: %w", err)
+	}
+
+	for _, file := range files {
+		if err := fsWatcher.Add(file); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+func sendWatchEvent(events chan<- WatchEvent, event WatchEvent) {
+	if events == nil {
+		return
+	}
+	events <- event
+}
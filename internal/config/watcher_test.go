@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestWatcher_ReloadsOnValidChangeAndKeepsLastGoodOnFailure(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "ai-rulez.yaml")
+
+	writeConfig := func(ruleContent string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(`
+metadata:
+  name: Watcher Project
+outputs:
+  - file: CLAUDE.md
+rules:
+  - name: Rule 1
+    content: `+ruleContent+`
+`), 0o644))
+	}
+	writeConfig("original content")
+
+	watcher := config.NewWatcher(configPath, 50*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	events := make(chan config.WatchEvent, 8)
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx, events) }()
+
+	waitForEvent := func(t *testing.T, want config.WatchEventType) config.WatchEvent {
+		t.Helper()
+		for {
+			select {
+			case event := <-events:
+				if event.Type == want {
+					return event
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("timed out waiting for %s event", want)
+			}
+		}
+	}
+
+	initial := waitForEvent(t, config.WatcherReloaded)
+	require.Len(t, initial.Config.Rules, 1)
+	assert.Equal(t, "original content", initial.Config.Rules[0].Content)
+	assert.Equal(t, []string{"Rule 1"}, initial.Diff.RulesAdded)
+
+	time.Sleep(100 * time.Millisecond)
+	writeConfig("updated content")
+	reloaded := waitForEvent(t, config.WatcherReloaded)
+	assert.Equal(t, "updated content", reloaded.Config.Rules[0].Content)
+	assert.Equal(t, "updated content", watcher.Current().Rules[0].Content)
+	assert.Equal(t, []string{"Rule 1"}, reloaded.Diff.RulesModified)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("not: [valid"), 0o644))
+	waitForEvent(t, config.WatcherError)
+	assert.Equal(t, "updated content", watcher.Current().Rules[0].Content)
+
+	cancel()
+	err := <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
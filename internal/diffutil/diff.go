@@ -0,0 +1,68 @@
+// Package diffutil compares generator.RenderAll's in-memory output against
+// what's currently on disk, producing the unified diffs the diff command
+// and the diff_output MCP tool both print.
+package diffutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FileDiff is the result of comparing one rendered output against the
+// current content of its file on disk.
+type FileDiff struct {
+	File string `json:"file"`
+	// Existed is false when the file doesn't exist yet on disk.
+	Existed bool `json:"existed"`
+	Changed bool `json:"changed"`
+	// Unified is the unified diff between what's on disk and what would be
+	// generated; empty when Changed is false.
+	Unified string `json:"unified,omitempty"`
+}
+
+// Compute compares each entry of rendered (as returned by
+// generator.RenderAll) against the current content of baseDir/file. The
+// returned slice is in map-iteration order; callers that need a stable
+// order should sort it by File.
+func Compute(baseDir string, rendered map[string][]byte) ([]FileDiff, error) {
+	diffs := make([]FileDiff, 0, len(rendered))
+	for file, content := range rendered {
+		fd, err := computeOne(baseDir, file, content)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, fd)
+	}
+	return diffs, nil
+}
+
+func computeOne(baseDir, file string, content []byte) (FileDiff, error) {
+	fullPath := filepath.Join(baseDir, file)
+
+	existing, err := os.ReadFile(fullPath)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return FileDiff{}, fmt.Errorf("reading %s: %w", fullPath, err)
+	}
+
+	if existed && string(existing) == string(content) {
+		return FileDiff{File: file, Existed: true, Changed: false}, nil
+	}
+
+	unified := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existing)),
+		B:        difflib.SplitLines(string(content)),
+		FromFile: file,
+		ToFile:   file + " (generated)",
+		Context:  3,
+	}
+	text, err := difflib.GetUnifiedDiffString(unified)
+	if err != nil {
+		return FileDiff{}, fmt.Errorf("computing diff for %s: %w", file, err)
+	}
+
+	return FileDiff{File: file, Existed: existed, Changed: true, Unified: text}, nil
+}
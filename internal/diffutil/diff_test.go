@@ -0,0 +1,48 @@
+package diffutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/diffutil"
+)
+
+func TestComputeUnchangedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "CLAUDE.md"), []byte("same content\n"), 0o644))
+
+	diffs, err := diffutil.Compute(tmpDir, map[string][]byte{"CLAUDE.md": []byte("same content\n")})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.True(t, diffs[0].Existed)
+	assert.False(t, diffs[0].Changed)
+	assert.Empty(t, diffs[0].Unified)
+}
+
+func TestComputeChangedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "CLAUDE.md"), []byte("old content\n"), 0o644))
+
+	diffs, err := diffutil.Compute(tmpDir, map[string][]byte{"CLAUDE.md": []byte("new content\n")})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.True(t, diffs[0].Existed)
+	assert.True(t, diffs[0].Changed)
+	assert.True(t, strings.Contains(diffs[0].Unified, "-old content"))
+	assert.True(t, strings.Contains(diffs[0].Unified, "+new content"))
+}
+
+func TestComputeMissingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	diffs, err := diffutil.Compute(tmpDir, map[string][]byte{"CLAUDE.md": []byte("new content\n")})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.False(t, diffs[0].Existed)
+	assert.True(t, diffs[0].Changed)
+}
@@ -0,0 +1,76 @@
+package fsutil
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BasePathFS wraps another Filesystem, rooting every path passed to it
+// under Base first. Useful for a generator or loader that should only ever
+// touch paths inside a project directory, regardless of what (possibly
+// absolute, possibly "../"-relative) path a config or CLI flag supplies -
+// the same role afero.BasePathFs plays for afero.Fs.
+type BasePathFS struct {
+	inner Filesystem
+	base  string
+}
+
+// NewBasePathFS roots fs at base: every path BasePathFS is given is joined
+// onto base before being passed through.
+func NewBasePathFS(fs Filesystem, base string) *BasePathFS {
+	return &BasePathFS{inner: fs, base: normalizeBase(base)}
+}
+
+// rooted joins name onto b.base. name is first cleaned as if it were rooted
+// at "/", so a leading "../" cannot climb above b.base.
+func (b *BasePathFS) rooted(name string) string {
+	return filepath.Join(b.base, filepath.Join(string(filepath.Separator), name))
+}
+
+// unrooted strips b.base back off a path returned by the inner Filesystem
+// (Walk and ReadDir report paths relative to what they were given, which is
+// already rooted - this restores the caller's own, unrooted view).
+func (b *BasePathFS) unrooted(name string) string {
+	rel, err := filepath.Rel(b.base, name)
+	if err != nil {
+		return name
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (b *BasePathFS) Stat(name string) (fs.FileInfo, error) {
+	return b.inner.Stat(b.rooted(name))
+}
+
+func (b *BasePathFS) ReadFile(name string) ([]byte, error) {
+	return b.inner.ReadFile(b.rooted(name))
+}
+
+func (b *BasePathFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return b.inner.WriteFile(b.rooted(name), data, perm)
+}
+
+func (b *BasePathFS) MkdirAll(path string, perm os.FileMode) error {
+	return b.inner.MkdirAll(b.rooted(path), perm)
+}
+
+func (b *BasePathFS) Walk(root string, walkFn filepath.WalkFunc) error {
+	return b.inner.Walk(b.rooted(root), func(path string, info fs.FileInfo, err error) error {
+		return walkFn(b.unrooted(path), info, err)
+	})
+}
+
+func (b *BasePathFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return b.inner.ReadDir(b.rooted(name))
+}
+
+// normalizeBase makes base safe to root paths under even when it's empty or
+// ".", so NewBasePathFS(fs, "") and NewBasePathFS(fs, ".") behave the same.
+func normalizeBase(base string) string {
+	if base == "" {
+		return "."
+	}
+	return strings.TrimSuffix(base, "/")
+}
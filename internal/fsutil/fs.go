@@ -0,0 +1,64 @@
+// Package fsutil provides a minimal afero-style filesystem abstraction so
+// config loading and generation can run against either the real disk or an
+// in-memory filesystem, without scattering os/filepath calls through callers.
+package fsutil
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Filesystem is implemented by every backend the generator and config loader
+// can read from and write to. Paths are always passed as given by the
+// caller; implementations are responsible for their own path semantics.
+type Filesystem interface {
+	// Stat returns file info for name, or an error satisfying os.IsNotExist
+	// if it does not exist.
+	Stat(name string) (fs.FileInfo, error)
+	// ReadFile returns the full contents of name.
+	ReadFile(name string) ([]byte, error)
+	// WriteFile writes data to name, creating or truncating it.
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	// MkdirAll creates a directory (and any parents) with the given permissions.
+	MkdirAll(path string, perm os.FileMode) error
+	// Walk traverses the tree rooted at root, calling walkFn for each entry,
+	// matching the semantics of filepath.Walk.
+	Walk(root string, walkFn filepath.WalkFunc) error
+	// ReadDir returns the directory entries of name, sorted by filename,
+	// matching the semantics of os.ReadDir.
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// OsFS is the default Filesystem, backed directly by the os and filepath packages.
+type OsFS struct{}
+
+// NewOsFS returns an OsFS. It has no state, but the constructor keeps the
+// call site symmetric with other Filesystem implementations.
+func NewOsFS() OsFS {
+	return OsFS{}
+}
+
+func (OsFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OsFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OsFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OsFS) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OsFS) Walk(root string, walkFn filepath.WalkFunc) error {
+	return filepath.Walk(root, walkFn)
+}
+
+func (OsFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
@@ -0,0 +1,107 @@
+package fsutil_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/fsutil"
+)
+
+func TestMemFS_WriteReadStat(t *testing.T) {
+	t.Parallel()
+
+	fs := fsutil.NewMemFS()
+
+	_, err := fs.Stat("out/CLAUDE.md")
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, fs.MkdirAll("out", 0o755))
+	require.NoError(t, fs.WriteFile("out/CLAUDE.md", []byte("hello"), 0o644))
+
+	info, err := fs.Stat("out/CLAUDE.md")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), info.Size())
+
+	data, err := fs.ReadFile("out/CLAUDE.md")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestMemFS_Walk(t *testing.T) {
+	t.Parallel()
+
+	fs := fsutil.NewMemFS()
+	require.NoError(t, fs.WriteFile("out/a.md", []byte("a"), 0o644))
+	require.NoError(t, fs.WriteFile("out/b.md", []byte("b"), 0o644))
+	require.NoError(t, fs.WriteFile("other/c.md", []byte("c"), 0o644))
+
+	var visited []string
+	err := fs.Walk("out", func(path string, _ os.FileInfo, err error) error {
+		require.NoError(t, err)
+		visited = append(visited, path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"out/a.md", "out/b.md"}, visited)
+}
+
+func TestBasePathFS_RootsEveryPath(t *testing.T) {
+	t.Parallel()
+
+	inner := fsutil.NewMemFS()
+	rooted := fsutil.NewBasePathFS(inner, "/project")
+
+	require.NoError(t, rooted.WriteFile("out/CLAUDE.md", []byte("hello"), 0o644))
+
+	data, err := inner.ReadFile("/project/out/CLAUDE.md")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	data, err = rooted.ReadFile("out/CLAUDE.md")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	info, err := rooted.Stat("out/CLAUDE.md")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), info.Size())
+}
+
+func TestBasePathFS_WalkReportsUnrootedPaths(t *testing.T) {
+	t.Parallel()
+
+	inner := fsutil.NewMemFS()
+	rooted := fsutil.NewBasePathFS(inner, "/project")
+	require.NoError(t, rooted.WriteFile("out/a.md", []byte("a"), 0o644))
+	require.NoError(t, rooted.WriteFile("out/b.md", []byte("b"), 0o644))
+
+	var visited []string
+	err := rooted.Walk("out", func(path string, _ os.FileInfo, err error) error {
+		require.NoError(t, err)
+		visited = append(visited, path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"out/a.md", "out/b.md"}, visited)
+}
+
+func TestMemFS_ReadDir(t *testing.T) {
+	t.Parallel()
+
+	fs := fsutil.NewMemFS()
+	require.NoError(t, fs.WriteFile("out/a.md", []byte("a"), 0o644))
+	require.NoError(t, fs.WriteFile("out/b.md", []byte("b"), 0o644))
+	require.NoError(t, fs.WriteFile("out/nested/c.md", []byte("c"), 0o644))
+
+	entries, err := fs.ReadDir("out")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "a.md", entries[0].Name())
+	assert.False(t, entries[0].IsDir())
+	assert.Equal(t, "b.md", entries[1].Name())
+	assert.False(t, entries[1].IsDir())
+	assert.Equal(t, "nested", entries[2].Name())
+	assert.True(t, entries[2].IsDir())
+}
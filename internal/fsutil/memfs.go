@@ -0,0 +1,186 @@
+package fsutil
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory Filesystem, useful for tests and for programmatic
+// use where generated output should be returned as data rather than written
+// to disk (e.g. an editor plugin evaluating a config on a read-only overlay).
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMemFS returns an empty MemFS.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string][]byte)}
+}
+
+// memFileInfo is the fs.FileInfo MemFS reports for a stored file.
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (i memFileInfo) Name() string     { return i.name }
+func (i memFileInfo) Size() int64      { return i.size }
+func (memFileInfo) Mode() fs.FileMode  { return 0o644 }
+func (memFileInfo) ModTime() time.Time { return time.Time{} }
+func (memFileInfo) IsDir() bool        { return false }
+func (memFileInfo) Sys() interface{}   { return nil }
+
+func clean(name string) string {
+	return filepath.ToSlash(filepath.Clean(name))
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.files[clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return memFileInfo{name: filepath.Base(name), size: int64(len(data))}, nil
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.files[clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	out := make([]byte, len(data))
+	copy(out, data)
+	return out, nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, _ os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]byte, len(data))
+	copy(out, data)
+	m.files[clean(name)] = out
+	return nil
+}
+
+// MkdirAll is a no-op: MemFS has no real directories, only file paths.
+func (*MemFS) MkdirAll(string, os.FileMode) error {
+	return nil
+}
+
+// Walk calls walkFn for each file under root in lexical order. MemFS holds
+// no directory entries, so walkFn is never called for a directory and a
+// filepath.SkipDir it returns is passed back as Walk's error.
+func (m *MemFS) Walk(root string, walkFn filepath.WalkFunc) error {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.files))
+	prefix := clean(root)
+	if prefix == "." {
+		prefix = ""
+	}
+	for name := range m.files {
+		if prefix == "" || name == prefix || strings.HasPrefix(name, prefix+"/") {
+			names = append(names, name)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		info, err := m.Stat(name)
+		if err != nil {
+			return err
+		}
+		if err := walkFn(name, info, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memDirEntry is the fs.DirEntry MemFS reports from ReadDir.
+type memDirEntry struct {
+	name  string
+	isDir bool
+}
+
+func (e memDirEntry) Name() string { return e.name }
+func (e memDirEntry) IsDir() bool  { return e.isDir }
+
+func (e memDirEntry) Type() fs.FileMode {
+	if e.isDir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+func (e memDirEntry) Info() (fs.FileInfo, error) {
+	return memFileInfo{name: e.name}, nil
+}
+
+// ReadDir returns the immediate children of name: every stored file
+// directly beneath it, plus one synthesized directory entry per distinct
+// next path segment among deeper files (MemFS has no real directories of
+// its own).
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	prefix := clean(name)
+	if prefix == "." {
+		prefix = ""
+	} else {
+		prefix += "/"
+	}
+
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	for file := range m.files {
+		if !strings.HasPrefix(file, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(file, prefix)
+		if rest == "" {
+			continue
+		}
+
+		segment, isDir := rest, false
+		if idx := strings.Index(rest, "/"); idx >= 0 {
+			segment, isDir = rest[:idx], true
+		}
+		if seen[segment] {
+			continue
+		}
+		seen[segment] = true
+		entries = append(entries, memDirEntry{name: segment, isDir: isDir})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// Files returns a copy of every path currently stored, keyed by its cleaned
+// name. Intended for tests and for callers collecting generated output.
+func (m *MemFS) Files() map[string][]byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make(map[string][]byte, len(m.files))
+	for name, data := range m.files {
+		cp := make([]byte, len(data))
+		copy(cp, data)
+		out[name] = cp
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package generator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/cache"
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/fsutil"
+	"github.com/Goldziher/ai-rulez/internal/generator"
+)
+
+// tamperWithSoleCacheEntry overwrites the single render-cache entry under
+// dir/outputs with sentinel content, so a later render that's served from
+// the cache rather than freshly rendered will surface the sentinel instead
+// of the rule content it was originally written with.
+func tamperWithSoleCacheEntry(t *testing.T, dir, sentinel string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(dir, string(cache.NamespaceOutputs)))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "expected exactly one cached render")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, string(cache.NamespaceOutputs), entries[0].Name()), []byte(sentinel), 0o644))
+}
+
+func TestGenerator_UseCache_ServesRenderFromCacheOnSecondRun(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "v1 content"}},
+		Cache:    &config.CacheConfig{Enabled: true, Dir: cacheDir},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+	require.NoError(t, gen.UseCache(cfg.Cache))
+
+	require.NoError(t, gen.GenerateAll(cfg))
+	first := string(mem.Files()["CLAUDE.md"])
+	assert.Contains(t, first, "v1 content")
+
+	// Tamper with the cache entry directly, then regenerate from the exact
+	// same (unchanged) config: the only way the tampered content can surface
+	// is if the second run is actually served from the cache rather than
+	// re-rendering the rule from scratch.
+	tamperWithSoleCacheEntry(t, cacheDir, "tampered cached content")
+	require.NoError(t, gen.GenerateAll(cfg))
+	second := string(mem.Files()["CLAUDE.md"])
+	assert.Contains(t, second, "tampered cached content", "expected the cached render to be reused rather than re-rendered")
+}
+
+func TestGenerator_UseCache_Disabled_AlwaysRenders(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "v1 content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+	cfg.Rules[0].Content = "v2 content"
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	assert.Contains(t, string(mem.Files()["CLAUDE.md"]), "v2 content")
+}
+
+func TestGenerator_UseCache_ServesPreviewFromCacheOnSecondRun(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "v1 content"}},
+		Cache:    &config.CacheConfig{Enabled: true, Dir: cacheDir},
+	}
+
+	gen := generator.NewWithFS(fsutil.NewMemFS())
+	require.NoError(t, gen.UseCache(cfg.Cache))
+
+	first, err := gen.PreviewAll(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, first["CLAUDE.md"], "v1 content")
+
+	// As in TestGenerator_UseCache_ServesRenderFromCacheOnSecondRun, tamper
+	// with the cache entry directly, then preview the exact same (unchanged)
+	// config: PreviewAll (and the RenderAll it shares with --dry-run,
+	// 'ai-rulez diff', and the diff_output MCP tool) should also consult the
+	// cache, not just GenerateAll.
+	tamperWithSoleCacheEntry(t, cacheDir, "tampered cached content")
+	second, err := gen.PreviewAll(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, second["CLAUDE.md"], "tampered cached content", "expected PreviewAll to reuse the cached render")
+}
+
+func TestGenerator_UseCache_NilConfigDisablesCache(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "v1 content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+	require.NoError(t, gen.UseCache(&config.CacheConfig{Enabled: true, Dir: t.TempDir()}))
+	require.NoError(t, gen.UseCache(nil))
+
+	require.NoError(t, gen.GenerateAll(cfg))
+	cfg.Rules[0].Content = "v2 content"
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	assert.Contains(t, string(mem.Files()["CLAUDE.md"]), "v2 content")
+}
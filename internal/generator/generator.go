@@ -4,55 +4,208 @@ package generator
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Goldziher/ai-rulez/internal/cache"
 	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/diffutil"
+	"github.com/Goldziher/ai-rulez/internal/fsutil"
+	"github.com/Goldziher/ai-rulez/internal/gitignore"
 	"github.com/Goldziher/ai-rulez/internal/templates"
 )
 
 // Generator handles the generation of output files from configuration.
 type Generator struct {
-	renderer   *templates.Renderer
-	baseDir    string
-	configFile string // Source configuration file name
+	renderer    *templates.Renderer
+	baseDir     string
+	configFile  string // Source configuration file name
+	engines     map[templates.EngineName]templates.TemplateEngine
+	ignoresOnce sync.Once
+	ignores     *gitignore.PatternSet // lazily loaded from baseDir/.gitignore
+	fs          fsutil.Filesystem     // defaults to fsutil.OsFS
+	cache       *cache.Cache          // nil unless UseCache was called
+	cacheCfg    *config.CacheConfig
+}
+
+// UseCache enables the internal/cache filecache for rendered outputs,
+// resolving cfg's Dir/MaxAge defaults (see cache.DefaultDir and
+// config.DefaultCacheMaxAge). A nil or disabled cfg clears any previously
+// configured cache, restoring the default of always rendering.
+func (g *Generator) UseCache(cfg *config.CacheConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		g.cache = nil
+		g.cacheCfg = nil
+		return nil
+	}
+
+	dir := cfg.Dir
+	if dir == "" {
+		var err error
+		dir, err = cache.DefaultDir()
+		if err != nil {
+			return err
+		}
+	}
+
+	maxAge, err := cfg.MaxAgeDuration()
+	if err != nil {
+		return err
+	}
+
+	g.cache = cache.New(dir, maxAge)
+	g.cacheCfg = cfg
+	return nil
+}
+
+// outputCacheKey returns the internal/cache key for output's rendered
+// content plus whether output caching is enabled, combining the output's
+// template source (inline text, named template, or @file reference) with a
+// stable snapshot of data - stable meaning TemplateData.Timestamp is
+// excluded, since it otherwise changes every invocation and would defeat
+// caching entirely.
+func (g *Generator) outputCacheKey(output config.Output, data *templates.TemplateData) (string, bool) {
+	if g.cache == nil || !g.cacheCfg.NamespaceEnabled(string(cache.NamespaceOutputs)) {
+		return "", false
+	}
+	if len(output.Compose) > 0 {
+		// Compose has no single Template to key on; caching it would need
+		// to fold in every fragment's selector and template, which isn't
+		// worth the complexity yet.
+		return "", false
+	}
+
+	stableData := *data
+	stableData.Timestamp = time.Time{}
+	dataJSON, err := json.Marshal(stableData)
+	if err != nil {
+		return "", false
+	}
+
+	return cache.Key([]byte(output.Template), []byte(output.Engine), dataJSON), true
 }
 
 // New creates a new generator with the default template renderer.
 func New() *Generator {
-	return &Generator{
-		renderer: templates.NewRenderer(),
+	renderer := templates.NewRenderer()
+	g := &Generator{
+		renderer: renderer,
 		baseDir:  ".",
+		engines:  map[templates.EngineName]templates.TemplateEngine{templates.EngineGo: renderer},
+		fs:       fsutil.NewOsFS(),
 	}
+	g.registerIncludeHelper(renderer)
+	return g
 }
 
 // NewWithBaseDir creates a new generator with a specific base directory.
 func NewWithBaseDir(baseDir string) *Generator {
-	return &Generator{
-		renderer: templates.NewRenderer(),
+	renderer := templates.NewRenderer()
+	g := &Generator{
+		renderer: renderer,
 		baseDir:  baseDir,
+		engines:  map[templates.EngineName]templates.TemplateEngine{templates.EngineGo: renderer},
+		fs:       fsutil.NewOsFS(),
 	}
+	g.registerIncludeHelper(renderer)
+	return g
 }
 
 // NewWithConfigFile creates a new generator with a specific config file.
 func NewWithConfigFile(configFile string) *Generator {
-	return &Generator{
-		renderer:   templates.NewRenderer(),
+	renderer := templates.NewRenderer()
+	g := &Generator{
+		renderer:   renderer,
 		baseDir:    filepath.Dir(configFile),
 		configFile: filepath.Base(configFile),
+		engines:    map[templates.EngineName]templates.TemplateEngine{templates.EngineGo: renderer},
+		fs:         fsutil.NewOsFS(),
 	}
+	g.registerIncludeHelper(renderer)
+	return g
 }
 
 // NewWithRenderer creates a generator with a custom renderer.
 func NewWithRenderer(renderer *templates.Renderer) *Generator {
-	return &Generator{
+	g := &Generator{
 		renderer: renderer,
 		baseDir:  ".",
+		engines:  map[templates.EngineName]templates.TemplateEngine{templates.EngineGo: renderer},
+		fs:       fsutil.NewOsFS(),
+	}
+	g.registerIncludeHelper(renderer)
+	return g
+}
+
+// NewWithFilesystem creates a generator that reads templates and writes
+// output through fs instead of the real disk, e.g. fsutil.NewMemFS() for
+// sandboxed evaluation of untrusted configs or in-process tooling that
+// wants generated output back as data rather than files on disk.
+func NewWithFilesystem(baseDir string, fs fsutil.Filesystem) *Generator {
+	renderer := templates.NewRenderer()
+	g := &Generator{
+		renderer: renderer,
+		baseDir:  baseDir,
+		engines:  map[templates.EngineName]templates.TemplateEngine{templates.EngineGo: renderer},
+		fs:       fs,
 	}
+	g.registerIncludeHelper(renderer)
+	return g
+}
+
+// NewWithFS is NewWithFilesystem with baseDir ".", for callers that already
+// rooted fs at the project directory (e.g. an in-memory overlay built for a
+// dry run).
+func NewWithFS(fs fsutil.Filesystem) *Generator {
+	return NewWithFilesystem(".", fs)
+}
+
+// engineFor returns the TemplateEngine for name, constructing and caching
+// non-Go engines (Handlebars, Mustache) lazily on first use.
+func (g *Generator) engineFor(name templates.EngineName) (templates.TemplateEngine, error) {
+	if engine, ok := g.engines[name]; ok {
+		return engine, nil
+	}
+
+	engine, err := templates.NewEngine(name)
+	if err != nil {
+		return nil, err
+	}
+
+	g.registerIncludeHelper(engine)
+
+	if g.engines == nil {
+		g.engines = make(map[templates.EngineName]templates.TemplateEngine)
+	}
+	g.engines[name] = engine
+	return engine, nil
+}
+
+// registerIncludeHelper adds the "include" helper to engine: read another
+// file, relative to g.baseDir, and return its contents as a string, so a
+// template can pull in shared boilerplate (e.g. a license header) without
+// config-side preprocessing. It's part of the same shared helper library as
+// baseTemplateFuncs/handlebarsHelpers, but lives here rather than in
+// internal/templates because it needs g.baseDir and g.fs. Like the
+// Handlebars engine's own "shell" helper, a failure (e.g. a missing file)
+// is swallowed to an empty string rather than returned as an error, so one
+// function value works for both the Go and Handlebars engines; Mustache's
+// logic-less RegisterHelper rejects it, which is fine since Mustache
+// templates can't use it anyway.
+func (g *Generator) registerIncludeHelper(engine templates.TemplateEngine) {
+	_ = engine.RegisterHelper("include", func(path string) string {
+		content, err := g.fs.ReadFile(filepath.Join(g.baseDir, path))
+		if err != nil {
+			return ""
+		}
+		return string(content)
+	})
 }
 
 // GenerateAll generates all output files defined in the configuration.
@@ -80,7 +233,7 @@ func (g *Generator) GenerateAll(cfg *config.Config) error {
 
 // GenerateOutput generates a single output file.
 func (g *Generator) GenerateOutput(cfg *config.Config, outputFile string) error {
-	templateData := templates.NewTemplateData(cfg)
+	sharedData := templates.NewTemplateData(cfg)
 
 	// Find the output configuration
 	targetOutput := g.findOutputConfig(cfg.Outputs, outputFile)
@@ -88,25 +241,88 @@ func (g *Generator) GenerateOutput(cfg *config.Config, outputFile string) error
 		return fmt.Errorf("output file %s not found in configuration", outputFile)
 	}
 
-	return g.writeOutputFile(*targetOutput, templateData)
+	return g.writeOutputFile(*targetOutput, sharedData)
 }
 
-// writeOutputFile writes a single output file.
-func (g *Generator) writeOutputFile(output config.Output, data *templates.TemplateData) error {
+// writeOutputFile writes a single output file, applying output.Mode's write
+// strategy (overwrite/skip/append/merge; see the OutputMode constants). An
+// output with ForEach set is expanded into one file per matching rule or
+// section instead; see generateForEachOutput.
+func (g *Generator) writeOutputFile(output config.Output, sharedData *templates.TemplateData) error {
+	if output.ForEach != "" {
+		return g.generateForEachOutput(output, sharedData)
+	}
+
+	if g.isIgnored(output.File) {
+		fmt.Fprintf(os.Stderr, "warning: skipping %s: ignored by .gitignore\n", output.File)
+		return nil
+	}
+
+	data := templates.FilterForOutput(sharedData, output)
+	data, err := templates.FilterBySelector(data, output.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector on output %s: %w", output.File, err)
+	}
+
 	// Set the file information for header generation
 	data.ConfigFile = g.configFile
 	data.OutputFile = output.File
 
-	// Render the template
-	content, err := g.renderTemplate(output, data)
-	if err != nil {
-		return err
+	// Render the template, short-circuiting on a cache hit so a large rule
+	// tree with many includes doesn't re-render every output on every run.
+	cacheKey, cacheable := g.outputCacheKey(output, data)
+	var content string
+	var cacheHit bool
+	if cacheable {
+		if cached, ok, err := g.cache.Get(cache.NamespaceOutputs, cacheKey); err == nil && ok {
+			content, cacheHit = string(cached), true
+		}
+	}
+	if !cacheHit {
+		if len(output.Compose) > 0 {
+			content, err = g.renderCompose(output, data)
+		} else {
+			content, err = g.renderTemplate(output, data)
+		}
+		if err != nil {
+			return err
+		}
+		if cacheable {
+			if err := g.cache.Set(cache.NamespaceOutputs, cacheKey, []byte(content)); err != nil {
+				return fmt.Errorf("failed to write cache entry for %s: %w", output.File, err)
+			}
+		}
 	}
 
 	// Prepend the header to the content
-	header := templates.GenerateHeader(data)
+	header, err := g.resolveHeader(output, data)
+	if err != nil {
+		return err
+	}
 	finalContent := header + content
 
+	if output.Mode == config.OutputModeSkip {
+		fullPath := filepath.Join(g.baseDir, output.File)
+		if _, err := g.fs.Stat(fullPath); err == nil {
+			return nil // file already exists; leave it alone
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to stat file %s: %w", fullPath, err)
+		}
+		return g.writeFile(output.File, finalContent)
+	}
+
+	if output.Mode == config.OutputModeAppend || output.Mode == config.OutputModeMerge {
+		existingContent, err := g.readFileIfExists(output.File)
+		if err != nil {
+			return err
+		}
+		if output.Mode == config.OutputModeAppend {
+			finalContent = mergeAppend(existingContent, finalContent)
+		} else {
+			finalContent = mergeRegion(existingContent, finalContent)
+		}
+	}
+
 	// Check if we need to write the file
 	shouldWrite, err := g.shouldWriteFile(output.File, finalContent)
 	if err != nil {
@@ -120,13 +336,87 @@ func (g *Generator) writeOutputFile(output config.Output, data *templates.Templa
 	return g.writeFile(output.File, finalContent)
 }
 
+// generateForEachOutput expands output (whose ForEach is set) into one file
+// per matching rule or section, reusing writeOutputFile for each expanded
+// file so write modes, delims, and .gitignore handling all apply unchanged.
+func (g *Generator) generateForEachOutput(output config.Output, sharedData *templates.TemplateData) error {
+	spec, err := config.ParseForEach(output.ForEach)
+	if err != nil {
+		return fmt.Errorf("invalid for_each on output %s: %w", output.File, err)
+	}
+
+	data := templates.FilterForOutput(sharedData, output)
+
+	switch spec.Kind {
+	case config.ForEachRules:
+		for _, rule := range config.FilterRulesByTag(data.Rules, spec.Tag) {
+			if err := g.writeForEachItem(output, data, rule); err != nil {
+				return fmt.Errorf("failed to generate for_each item %q: %w", rule.Name, err)
+			}
+		}
+	case config.ForEachSections:
+		for _, section := range config.FilterSectionsByTag(data.Sections, spec.Tag) {
+			if err := g.writeForEachItem(output, data, section); err != nil {
+				return fmt.Errorf("failed to generate for_each item %q: %w", section.Title, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeForEachItem renders output.File as a path template scoped to item
+// (a config.Rule or config.Section, exposed to the template as .Item), then
+// writes the resulting file via writeOutputFile with ForEach cleared.
+func (g *Generator) writeForEachItem(output config.Output, data *templates.TemplateData, item any) error {
+	itemData := *data
+	itemData.Item = item
+
+	delims := output.Delims
+	var filePath string
+	var err error
+	if len(delims) == 2 {
+		filePath, err = templates.RenderStringWithDelims(output.File, &itemData, delims[0], delims[1])
+	} else {
+		filePath, err = templates.RenderString(output.File, &itemData)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render file path template %q: %w", output.File, err)
+	}
+
+	itemOutput := output
+	itemOutput.File = filePath
+	itemOutput.ForEach = ""
+
+	return g.writeOutputFile(itemOutput, &itemData)
+}
+
+// readFileIfExists returns the contents of filePath, or "" if it doesn't exist.
+func (g *Generator) readFileIfExists(filePath string) (string, error) {
+	fullPath := filepath.Join(g.baseDir, filePath)
+
+	_, err := g.fs.Stat(fullPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file %s: %w", fullPath, err)
+	}
+
+	data, err := g.fs.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read existing file %s: %w", fullPath, err)
+	}
+	return string(data), nil
+}
+
 // shouldWriteFile determines if a file should be written by comparing content hashes.
 func (g *Generator) shouldWriteFile(filePath, newContent string) (bool, error) {
 	// Resolve the full path relative to base directory
 	fullPath := filepath.Join(g.baseDir, filePath)
 
 	// If file doesn't exist, we should write it
-	stat, err := os.Stat(fullPath)
+	_, err := g.fs.Stat(fullPath)
 	if os.IsNotExist(err) {
 		return true, nil
 	}
@@ -134,29 +424,92 @@ func (g *Generator) shouldWriteFile(filePath, newContent string) (bool, error) {
 		return false, fmt.Errorf("failed to stat file %s: %w", fullPath, err)
 	}
 
-	// For small files (< 1MB), read into memory
-	if stat.Size() < 1024*1024 {
-		existingContent, err := os.ReadFile(fullPath)
-		if err != nil {
-			return false, fmt.Errorf("failed to read existing file %s: %w", fullPath, err)
+	existingContent, err := g.fs.ReadFile(fullPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read existing file %s: %w", fullPath, err)
+	}
+	existingHash := computeContentHash(string(existingContent))
+	newHash := computeContentHash(newContent)
+	return existingHash != newHash, nil
+}
+
+// appendMarker delimits the generated section written by OutputModeAppend;
+// everything above it on disk is left untouched across regenerations.
+const appendMarker = "# ai-rulez:append"
+
+// mergeAppend places generated below appendMarker, replacing whatever
+// followed it on a prior run while preserving any hand-written preface
+// above it.
+func mergeAppend(existing, generated string) string {
+	preface := existing
+	if idx := strings.Index(existing, appendMarker); idx != -1 {
+		preface = existing[:idx]
+	}
+	preface = strings.TrimRight(preface, "\n")
+
+	if preface == "" {
+		return appendMarker + "\n" + generated
+	}
+	return preface + "\n\n" + appendMarker + "\n" + generated
+}
+
+// mergeRegionID names the single generated region mergeRegion manages within
+// a merge-mode output file.
+const mergeRegionID = "generated"
+
+const (
+	mergeBeginMarker = "# ai-rulez:begin id=" + mergeRegionID
+	mergeEndMarker   = "# ai-rulez:end"
+)
+
+// mergeRegion replaces the mergeBeginMarker/mergeEndMarker block in existing
+// with generated, leaving any hand-written content outside the block
+// untouched. If existing has no such block, the generated block is appended
+// to it instead of replacing anything.
+func mergeRegion(existing, generated string) string {
+	block := mergeBeginMarker + "\n" + generated + mergeEndMarker + "\n"
+
+	beginIdx := strings.Index(existing, mergeBeginMarker)
+	if beginIdx == -1 {
+		if existing == "" {
+			return block
 		}
-		existingHash := computeContentHash(string(existingContent))
-		newHash := computeContentHash(newContent)
-		return existingHash != newHash, nil
+		return strings.TrimRight(existing, "\n") + "\n\n" + block
 	}
 
-	// For larger files, use streaming hash
-	existingHash, err := computeFileHashStreaming(fullPath)
-	if err != nil {
-		return false, fmt.Errorf("failed to compute hash for %s: %w", fullPath, err)
+	endIdx := strings.Index(existing[beginIdx:], mergeEndMarker)
+	if endIdx == -1 {
+		// Malformed marker pair: treat everything from the begin marker
+		// onward as the generated region.
+		return existing[:beginIdx] + block
+	}
+	endIdx += beginIdx + len(mergeEndMarker)
+	if endIdx < len(existing) && existing[endIdx] == '\n' {
+		endIdx++ // swallow a single trailing newline after the end marker
 	}
 
-	newHash := computeContentHash(newContent)
-	return existingHash != newHash, nil
+	return existing[:beginIdx] + block + existing[endIdx:]
 }
 
 // Helper methods
 
+// isIgnored reports whether output would be ignored by the .gitignore in the
+// generator's base directory, so a generated file never shadows one the
+// project has deliberately excluded from version control. The pattern set
+// is loaded once and cached for the lifetime of the Generator.
+func (g *Generator) isIgnored(outputFile string) bool {
+	g.ignoresOnce.Do(func() {
+		ps, err := gitignore.LoadPatternSet(filepath.Join(g.baseDir, ".gitignore"))
+		if err == nil {
+			g.ignores = ps
+		}
+	})
+	if g.ignores == nil {
+		return false
+	}
+	return g.ignores.Match(outputFile)
+}
+
 // findOutputConfig finds an output configuration by file path.
 func (*Generator) findOutputConfig(outputs []config.Output, outputFile string) *config.Output {
 	for _, output := range outputs {
@@ -167,13 +520,20 @@ func (*Generator) findOutputConfig(outputs []config.Output, outputFile string) *
 	return nil
 }
 
-// renderTemplate renders a template for the given output configuration.
+// renderTemplate renders a template for the given output configuration,
+// dispatching to the Go, Handlebars, or Mustache engine selected by
+// output.Engine (or by the @file template's extension).
 func (g *Generator) renderTemplate(output config.Output, data *templates.TemplateData) (string, error) {
 	templateName := "default"
 	if output.Template != "" {
 		templateName = output.Template
 	}
 
+	engineName, err := templates.ParseEngineName(output.Engine)
+	if err != nil {
+		return "", err
+	}
+
 	// Check if this is a file reference (starts with @)
 	if strings.HasPrefix(templateName, "@") {
 		templatePath := strings.TrimPrefix(templateName, "@")
@@ -181,35 +541,182 @@ func (g *Generator) renderTemplate(output config.Output, data *templates.Templat
 		fullPath := filepath.Join(g.baseDir, templatePath)
 
 		// Read the template file
-		templateContent, err := os.ReadFile(fullPath)
+		templateContent, err := g.fs.ReadFile(fullPath)
 		if err != nil {
 			return "", fmt.Errorf("failed to read template file %s: %w", fullPath, err)
 		}
 
+		// An explicit engine: field wins; otherwise detect from extension.
+		if output.Engine == "" {
+			engineName = templates.EngineForExtension(filepath.Ext(templatePath))
+		}
+
+		engine, err := g.engineFor(engineName)
+		if err != nil {
+			return "", err
+		}
+
 		// Register and render the template
 		templateID := fmt.Sprintf("file:%s", templatePath)
-		if err := g.renderer.RegisterTemplate(templateID, string(templateContent)); err != nil {
-			return "", fmt.Errorf("failed to register template from %s: %w", templatePath, err)
+		if err := registerTemplateWithDelims(engine, engineName, templateID, string(templateContent), output.Delims); err != nil {
+			return "", newTemplateError(output.File, templatePath, string(templateContent), err)
 		}
 
-		return g.renderer.Render(templateID, data)
+		content, err := engine.Render(templateID, data)
+		if err != nil {
+			return "", newTemplateError(output.File, templatePath, string(templateContent), err)
+		}
+		return content, nil
 	}
 
 	// Check if this is an inline template (contains newlines or template syntax)
-	if strings.Contains(templateName, "\n") || strings.Contains(templateName, "{{") {
-		// This is an inline template
-		return templates.RenderString(templateName, data)
+	if engineName == templates.EngineGo && (strings.Contains(templateName, "\n") || strings.Contains(templateName, "{{")) {
+		// Register and render through g.renderer rather than the standalone
+		// RenderString/RenderStringWithDelims, so Generator-level helpers
+		// like "include" (which need g.baseDir) are available here too.
+		// Keyed by content hash rather than output file, so outputs that
+		// share an identical inline template register it once.
+		templateID := "inline:" + ComputeContentHashPooled(templateName)
+		var err error
+		if len(output.Delims) == 2 {
+			err = g.renderer.RegisterTemplateWithDelims(templateID, templateName, output.Delims[0], output.Delims[1])
+		} else {
+			err = g.renderer.RegisterTemplate(templateID, templateName)
+		}
+		if err != nil {
+			return "", newTemplateError(output.File, "", templateName, err)
+		}
+
+		content, err := g.renderer.Render(templateID, data)
+		if err != nil {
+			return "", newTemplateError(output.File, "", templateName, err)
+		}
+		return content, nil
+	}
+
+	if engineName != templates.EngineGo {
+		engine, err := g.engineFor(engineName)
+		if err != nil {
+			return "", err
+		}
+		// Keyed by content hash rather than output file, so outputs that
+		// share an identical template (e.g. via extends) register it once.
+		templateID := "inline:" + ComputeContentHashPooled(templateName)
+		if err := engine.RegisterTemplate(templateID, templateName); err != nil {
+			return "", newTemplateError(output.File, "", templateName, err)
+		}
+		content, err := engine.Render(templateID, data)
+		if err != nil {
+			return "", newTemplateError(output.File, "", templateName, err)
+		}
+		return content, nil
 	}
 
 	// Otherwise, treat as a named template
 	content, err := g.renderer.Render(templateName, data)
 	if err != nil {
-		return "", fmt.Errorf("failed to render template %s: %w", templateName, err)
+		return "", newTemplateError(output.File, "", "", err)
 	}
 
 	return content, nil
 }
 
+// renderCompose renders output.Compose, concatenating each fragment's own
+// selector-filtered render in order (e.g. a "security" fragment followed
+// by a "frontend" fragment in one output file). data has already had
+// output.Rules/Sections and output.Selector applied; each fragment
+// narrows it further by its own Selector.
+func (g *Generator) renderCompose(output config.Output, data *templates.TemplateData) (string, error) {
+	var b strings.Builder
+	for i, fragment := range output.Compose {
+		fragmentData, err := templates.FilterBySelector(data, fragment.Selector)
+		if err != nil {
+			return "", fmt.Errorf("invalid selector on output %s compose[%d]: %w", output.File, i, err)
+		}
+
+		fragmentOutput := output
+		fragmentOutput.Template = fragment.Template
+		fragmentOutput.Compose = nil
+		content, err := g.renderTemplate(fragmentOutput, fragmentData)
+		if err != nil {
+			return "", fmt.Errorf("failed to render output %s compose[%d]: %w", output.File, i, err)
+		}
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(content)
+	}
+	return b.String(), nil
+}
+
+// resolveHeader returns the banner prepended to output's rendered content.
+// output.Header (or output.HeaderFile) takes precedence over the
+// project-wide Config.Header/Config.HeaderFile default carried on data
+// (see TemplateData.ConfigHeader), which in turn takes precedence over the
+// built-in templates.GenerateHeader banner. header: false, or a header file
+// resolving to one, suppresses the banner entirely.
+func (g *Generator) resolveHeader(output config.Output, data *templates.TemplateData) (string, error) {
+	if output.Header != nil {
+		return g.renderHeaderValue(*output.Header, output, data)
+	}
+	if output.HeaderFile != "" {
+		return g.renderHeaderFile(output.HeaderFile, output, data)
+	}
+	if data.ConfigHeader != nil {
+		return g.renderHeaderValue(*data.ConfigHeader, output, data)
+	}
+	if data.ConfigHeaderFile != "" {
+		return g.renderHeaderFile(data.ConfigHeaderFile, output, data)
+	}
+	return templates.GenerateHeader(data), nil
+}
+
+// renderHeaderValue renders header.Template as a Go template, or suppresses
+// the banner entirely when header.Disabled is set.
+func (g *Generator) renderHeaderValue(header config.HeaderValue, output config.Output, data *templates.TemplateData) (string, error) {
+	if header.Disabled {
+		return "", nil
+	}
+	return renderHeaderTemplate(header.Template, output, data)
+}
+
+// renderHeaderFile reads headerFile (relative to g.baseDir) and renders its
+// content as a Go template, same as an inline Header override.
+func (g *Generator) renderHeaderFile(headerFile string, output config.Output, data *templates.TemplateData) (string, error) {
+	fullPath := filepath.Join(g.baseDir, headerFile)
+	content, err := g.fs.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read header file %s: %w", fullPath, err)
+	}
+	return renderHeaderTemplate(string(content), output, data)
+}
+
+// renderHeaderTemplate renders tmpl as a Go template against data, using
+// output's own Delims the same way renderTemplate does for the main body.
+func renderHeaderTemplate(tmpl string, output config.Output, data *templates.TemplateData) (string, error) {
+	if tmpl == "" {
+		return "", nil
+	}
+	if len(output.Delims) == 2 {
+		return templates.RenderStringWithDelims(tmpl, data, output.Delims[0], output.Delims[1])
+	}
+	return templates.RenderString(tmpl, data)
+}
+
+// registerTemplateWithDelims registers content under templateID on engine,
+// applying delims (an [left, right] pair, e.g. ["<%", "%>"]) when engine is
+// the Go engine and delims is set; every other engine has its own fixed
+// template syntax, so delims is ignored for them.
+func registerTemplateWithDelims(engine templates.TemplateEngine, engineName templates.EngineName, templateID, content string, delims []string) error {
+	if engineName == templates.EngineGo && len(delims) == 2 {
+		if renderer, ok := engine.(*templates.Renderer); ok {
+			return renderer.RegisterTemplateWithDelims(templateID, content, delims[0], delims[1])
+		}
+	}
+	return engine.RegisterTemplate(templateID, content)
+}
+
 // writeFile writes content to a file, creating directories as needed.
 func (g *Generator) writeFile(filePath, content string) error {
 	// Resolve the full path relative to base directory
@@ -217,12 +724,12 @@ func (g *Generator) writeFile(filePath, content string) error {
 
 	// Ensure output directory exists
 	outputDir := filepath.Dir(fullPath)
-	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+	if err := g.fs.MkdirAll(outputDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
 	}
 
 	// Write the file
-	if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+	if err := g.fs.WriteFile(fullPath, []byte(content), 0o644); err != nil {
 		return fmt.Errorf("failed to write output file %s: %w", fullPath, err)
 	}
 
@@ -236,27 +743,24 @@ func computeContentHash(content string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// computeFileHashStreaming computes SHA256 hash of a file without loading entire content into memory.
-func computeFileHashStreaming(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer func() { _ = file.Close() }()
-
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(hash.Sum(nil)), nil
-}
-
 // RegisterTemplate adds a custom template to the generator's renderer.
 func (g *Generator) RegisterTemplate(name, templateStr string) error {
 	return g.renderer.RegisterTemplate(name, templateStr)
 }
 
+// GenerateAllDiff renders every output in cfg via RenderAll and compares
+// each to its on-disk content via diffutil.Compute, without writing
+// anything - the single method 'ai-rulez check' and other CI-style
+// verification callers need instead of composing RenderAll and
+// diffutil.Compute themselves.
+func (g *Generator) GenerateAllDiff(cfg *config.Config) ([]diffutil.FileDiff, error) {
+	rendered, err := g.RenderAll(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return diffutil.Compute(g.baseDir, rendered)
+}
+
 // GetSupportedTemplates returns all available template names.
 func (g *Generator) GetSupportedTemplates() []string {
 	return g.renderer.GetSupportedFormats()
@@ -267,9 +771,21 @@ func (*Generator) ValidateTemplate(templateStr string) error {
 	return templates.ValidateTemplate(templateStr)
 }
 
+// ValidateTemplateForEngine is ValidateTemplate, checking templateStr
+// against engineName's own grammar (go, handlebars, or mustache - see
+// templates.ParseEngineName) instead of assuming Go's, for callers that
+// know which engine an output's `engine:` field picked.
+func (*Generator) ValidateTemplateForEngine(templateStr, engineName string) error {
+	engine, err := templates.ParseEngineName(engineName)
+	if err != nil {
+		return err
+	}
+	return templates.ValidateTemplateForEngine(templateStr, engine)
+}
+
 // PreviewOutput generates output content without writing to file.
 func (g *Generator) PreviewOutput(cfg *config.Config, outputFile string) (string, error) {
-	templateData := templates.NewTemplateData(cfg)
+	sharedData := templates.NewTemplateData(cfg)
 
 	// Find the output configuration
 	targetOutput := g.findOutputConfig(cfg.Outputs, outputFile)
@@ -277,44 +793,107 @@ func (g *Generator) PreviewOutput(cfg *config.Config, outputFile string) (string
 		return "", fmt.Errorf("output file %s not found in configuration", outputFile)
 	}
 
+	templateData := templates.FilterForOutput(sharedData, *targetOutput)
+	templateData, err := templates.FilterBySelector(templateData, targetOutput.Selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid selector on output %s: %w", targetOutput.File, err)
+	}
+
 	// Set the file information for header generation
 	templateData.ConfigFile = g.configFile
 	templateData.OutputFile = targetOutput.File
 
 	// Render the template
-	content, err := g.renderTemplate(*targetOutput, templateData)
+	var content string
+	if len(targetOutput.Compose) > 0 {
+		content, err = g.renderCompose(*targetOutput, templateData)
+	} else {
+		content, err = g.renderTemplate(*targetOutput, templateData)
+	}
 	if err != nil {
 		return "", err
 	}
 
 	// Prepend the header and return
-	header := templates.GenerateHeader(templateData)
+	header, err := g.resolveHeader(*targetOutput, templateData)
+	if err != nil {
+		return "", err
+	}
 	return header + content, nil
 }
 
-// PreviewAll generates all output content without writing files.
-// Returns a map of file paths to their generated content.
+// PreviewAll generates all output content without writing files, returning
+// a map of file paths to their generated content. It's a thin string-typed
+// wrapper around RenderAll, kept for existing callers.
 func (g *Generator) PreviewAll(cfg *config.Config) (map[string]string, error) {
+	rendered, err := g.RenderAll(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]string, len(rendered))
+	for file, content := range rendered {
+		results[file] = string(content)
+	}
+	return results, nil
+}
+
+// RenderAll renders every output in cfg without writing any files, the
+// same rendering path writeOutputFile uses for GenerateAll. It's the
+// single source of truth shared by --dry-run, the diff command, and the
+// diff_output MCP tool, so all three agree on what "would change" means.
+func (g *Generator) RenderAll(cfg *config.Config) (map[string][]byte, error) {
 	if len(cfg.Outputs) == 0 {
 		return nil, errors.New("no outputs defined in configuration")
 	}
 
-	templateData := templates.NewTemplateData(cfg)
-	results := make(map[string]string)
+	sharedData := templates.NewTemplateData(cfg)
+	results := make(map[string][]byte)
 
 	for i, output := range cfg.Outputs {
+		templateData := templates.FilterForOutput(sharedData, output)
+		templateData, err := templates.FilterBySelector(templateData, output.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector on output %d (%s): %w", i, output.File, err)
+		}
+
 		// Set the file information for header generation
 		templateData.ConfigFile = g.configFile
 		templateData.OutputFile = output.File
 
-		content, err := g.renderTemplate(output, templateData)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate output %d (%s): %w", i, output.File, err)
+		// Consult the render cache first, the same way writeOutputFile does,
+		// so --dry-run, the diff command, and the diff_output MCP tool get
+		// the cache-accelerated path too, not just GenerateAll.
+		cacheKey, cacheable := g.outputCacheKey(output, templateData)
+		var content string
+		var cacheHit bool
+		if cacheable {
+			if cached, ok, cacheErr := g.cache.Get(cache.NamespaceOutputs, cacheKey); cacheErr == nil && ok {
+				content, cacheHit = string(cached), true
+			}
+		}
+		if !cacheHit {
+			if len(output.Compose) > 0 {
+				content, err = g.renderCompose(output, templateData)
+			} else {
+				content, err = g.renderTemplate(output, templateData)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate output %d (%s): %w", i, output.File, err)
+			}
+			if cacheable {
+				if err := g.cache.Set(cache.NamespaceOutputs, cacheKey, []byte(content)); err != nil {
+					return nil, fmt.Errorf("failed to write cache entry for %s: %w", output.File, err)
+				}
+			}
 		}
 
 		// Prepend the header
-		header := templates.GenerateHeader(templateData)
-		results[output.File] = header + content
+		header, err := g.resolveHeader(output, templateData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate header for output %d (%s): %w", i, output.File, err)
+		}
+		results[output.File] = []byte(header + content)
 	}
 
 	return results, nil
@@ -0,0 +1,358 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/fsutil"
+	"github.com/Goldziher/ai-rulez/internal/generator"
+)
+
+func TestGenerator_NewWithFilesystem_WritesToMemFS(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFilesystem(".", mem)
+
+	err := gen.GenerateAll(cfg)
+	require.NoError(t, err)
+
+	files := mem.Files()
+	content, ok := files["CLAUDE.md"]
+	require.True(t, ok, "expected CLAUDE.md to be written to the in-memory filesystem")
+	assert.Contains(t, string(content), "Rule 1")
+}
+
+func TestGenerator_NewWithFS_WritesToMemFS(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+
+	err := gen.GenerateAll(cfg)
+	require.NoError(t, err)
+
+	files := mem.Files()
+	content, ok := files["CLAUDE.md"]
+	require.True(t, ok, "expected CLAUDE.md to be written to the in-memory filesystem")
+	assert.Contains(t, string(content), "Rule 1")
+}
+
+func TestGenerator_SkipMode_LeavesExistingFileAlone(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "README.md", Mode: config.OutputModeSkip}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "generated content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("README.md", []byte("hand-written notes"), 0o644))
+	gen := generator.NewWithFilesystem(".", mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	assert.Equal(t, "hand-written notes", string(mem.Files()["README.md"]))
+}
+
+func TestGenerator_SkipMode_WritesWhenFileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "README.md", Mode: config.OutputModeSkip}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "generated content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFilesystem(".", mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	assert.Contains(t, string(mem.Files()["README.md"]), "generated content")
+}
+
+func TestGenerator_AppendMode_ReplacesOnlyBelowMarker(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md", Mode: config.OutputModeAppend}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "v2 content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("CLAUDE.md", []byte(
+		"my own notes\n\n# ai-rulez:append\nv1 content"), 0o644))
+	gen := generator.NewWithFilesystem(".", mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	result := string(mem.Files()["CLAUDE.md"])
+	assert.Contains(t, result, "my own notes")
+	assert.Contains(t, result, "v2 content")
+	assert.NotContains(t, result, "v1 content")
+}
+
+func TestGenerator_MergeMode_PreservesContentOutsideMarkers(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md", Mode: config.OutputModeMerge}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "v2 content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("CLAUDE.md", []byte(
+		"intro notes\n\n# ai-rulez:begin id=generated\nv1 content\n# ai-rulez:end\n\noutro notes"), 0o644))
+	gen := generator.NewWithFilesystem(".", mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	result := string(mem.Files()["CLAUDE.md"])
+	assert.Contains(t, result, "intro notes")
+	assert.Contains(t, result, "outro notes")
+	assert.Contains(t, result, "v2 content")
+	assert.NotContains(t, result, "v1 content")
+}
+
+func TestGenerator_ForEachRules_WritesOneFilePerRule(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs: []config.Output{
+			{File: "rules/{{.Item.Name | slug}}.md", ForEach: "rules"},
+		},
+		Rules: []config.Rule{
+			{Name: "Style Rule", Content: "Use strict mode"},
+			{Name: "Testing Rule", Content: "Write tests"},
+		},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFilesystem(".", mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	files := mem.Files()
+	style, ok := files["rules/style-rule.md"]
+	require.True(t, ok, "expected rules/style-rule.md to be written")
+	assert.Contains(t, string(style), "Use strict mode")
+
+	testingRule, ok := files["rules/testing-rule.md"]
+	require.True(t, ok, "expected rules/testing-rule.md to be written")
+	assert.Contains(t, string(testingRule), "Write tests")
+}
+
+func TestGenerator_ForEachRules_FiltersByTag(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs: []config.Output{
+			{File: "rules/{{.Item.Name | slug}}.md", ForEach: `rules where tag == "security"`},
+		},
+		Rules: []config.Rule{
+			{Name: "Auth Rule", Content: "Validate tokens", Tags: []string{"security"}},
+			{Name: "Style Rule", Content: "Use strict mode", Tags: []string{"style"}},
+		},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFilesystem(".", mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	files := mem.Files()
+	_, ok := files["rules/auth-rule.md"]
+	assert.True(t, ok, "expected rules/auth-rule.md to be written")
+
+	_, ok = files["rules/style-rule.md"]
+	assert.False(t, ok, "style rule does not have the security tag and should be skipped")
+}
+
+func TestGenerator_ForEachSections_WritesOneFilePerSection(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs: []config.Output{
+			{File: "docs/{{.Item.Title | slug}}.md", ForEach: "sections"},
+		},
+		Sections: []config.Section{
+			{Title: "Overview", Content: "Project overview"},
+		},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFilesystem(".", mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content, ok := mem.Files()["docs/overview.md"]
+	require.True(t, ok, "expected docs/overview.md to be written")
+	assert.Contains(t, string(content), "Project overview")
+}
+
+func TestGenerator_Header_DisabledOmitsBanner(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md", Header: &config.HeaderValue{Disabled: true}}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content := string(mem.Files()["CLAUDE.md"])
+	assert.NotContains(t, content, "GENERATED FILE")
+	assert.Contains(t, content, "Rule 1")
+}
+
+func TestGenerator_Header_OutputOverrideRendersAsTemplate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs: []config.Output{
+			{File: "CLAUDE.md", Header: &config.HeaderValue{Template: "// Copyright {{.ProjectName}}\n"}},
+		},
+		Rules: []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content := string(mem.Files()["CLAUDE.md"])
+	assert.Contains(t, content, "// Copyright Mem Project")
+	assert.NotContains(t, content, "GENERATED FILE")
+}
+
+func TestGenerator_Header_ConfigLevelDefaultAppliesToAllOutputs(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Header:   &config.HeaderValue{Template: "// SPDX-License-Identifier: MIT\n"},
+		Outputs: []config.Output{
+			{File: "CLAUDE.md"},
+			{File: ".cursorrules"},
+		},
+		Rules: []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	for _, file := range []string{"CLAUDE.md", ".cursorrules"} {
+		content := string(mem.Files()[file])
+		assert.Contains(t, content, "// SPDX-License-Identifier: MIT", "file %s", file)
+	}
+}
+
+func TestGenerator_Header_OutputHeaderFileOverridesConfigHeader(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Header:   &config.HeaderValue{Template: "// config-level header\n"},
+		Outputs: []config.Output{
+			{File: "CLAUDE.md", HeaderFile: "LICENSE_HEADER.txt"},
+		},
+		Rules: []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	mem := fsutil.NewMemFS()
+	require.NoError(t, mem.WriteFile("LICENSE_HEADER.txt", []byte("// from {{.ConfigFile}}\n"), 0o644))
+	gen := generator.NewWithFS(mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content := string(mem.Files()["CLAUDE.md"])
+	assert.Contains(t, content, "// from")
+	assert.NotContains(t, content, "config-level header")
+}
+
+func TestGenerator_OutputSelector_FiltersRulesByTag(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs: []config.Output{
+			{File: "SECURITY.md", Selector: "security"},
+		},
+		Rules: []config.Rule{
+			{Name: "Auth Rule", Content: "Validate tokens", Tags: []string{"security"}},
+			{Name: "Style Rule", Content: "Use strict mode", Tags: []string{"style"}},
+		},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content := string(mem.Files()["SECURITY.md"])
+	assert.Contains(t, content, "Auth Rule")
+	assert.NotContains(t, content, "Style Rule")
+}
+
+func TestGenerator_OutputCompose_ConcatenatesFragmentsInOrder(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Mem Project"},
+		Outputs: []config.Output{
+			{
+				File: "CLAUDE.md",
+				Compose: []config.ComposeFragment{
+					{Selector: "security", Template: "{{range .Rules}}security: {{.Name}}\n{{end}}"},
+					{Selector: "frontend", Template: "{{range .Rules}}frontend: {{.Name}}\n{{end}}"},
+				},
+			},
+		},
+		Rules: []config.Rule{
+			{Name: "Auth Rule", Content: "Validate tokens", Tags: []string{"security"}},
+			{Name: "Hooks Rule", Content: "Use hooks", Tags: []string{"frontend"}},
+		},
+	}
+
+	mem := fsutil.NewMemFS()
+	gen := generator.NewWithFS(mem)
+
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content := string(mem.Files()["CLAUDE.md"])
+	securityIdx := strings.Index(content, "security: Auth Rule")
+	frontendIdx := strings.Index(content, "frontend: Hooks Rule")
+	require.GreaterOrEqual(t, securityIdx, 0)
+	require.GreaterOrEqual(t, frontendIdx, 0)
+	assert.Less(t, securityIdx, frontendIdx, "expected the security fragment to render before the frontend fragment")
+}
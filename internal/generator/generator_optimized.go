@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"hash"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -50,52 +49,27 @@ func (g *Generator) GenerateAllConcurrent(cfg *config.Config) error {
 	return nil
 }
 
-// computeFileHash computes SHA256 hash of a file without loading entire content into memory.
-func computeFileHash(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer func() { _ = file.Close() }()
-
-	h := sha256.New()
-	if _, err := io.Copy(h, file); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(h.Sum(nil)), nil
-}
-
-// shouldWriteFileOptimized checks if file should be written using streaming hash.
+// shouldWriteFileOptimized checks if file should be written, comparing
+// content hashes through g.fs instead of touching the real disk directly -
+// so this path honors NewWithFilesystem the same way writeFile already
+// does, and a MemFS-backed Generator never shouldWrite-skips against a file
+// that was never really there.
 func (g *Generator) shouldWriteFileOptimized(filePath, newContent string) (bool, error) {
 	fullPath := filepath.Join(g.baseDir, filePath)
 
 	// If file doesn't exist, we should write it
-	stat, err := os.Stat(fullPath)
-	if os.IsNotExist(err) {
+	if _, err := g.fs.Stat(fullPath); os.IsNotExist(err) {
 		return true, nil
-	}
-	if err != nil {
+	} else if err != nil {
 		return false, fmt.Errorf("failed to stat file %s: %w", fullPath, err)
 	}
 
-	// For small files, use the original method
-	if stat.Size() < 1024*1024 { // 1MB
-		existingContent, err := os.ReadFile(fullPath)
-		if err != nil {
-			return false, fmt.Errorf("failed to read existing file %s: %w", fullPath, err)
-		}
-		existingHash := computeContentHash(string(existingContent))
-		newHash := computeContentHash(newContent)
-		return existingHash != newHash, nil
-	}
-
-	// For larger files, use streaming hash
-	existingHash, err := computeFileHash(fullPath)
+	existingContent, err := g.fs.ReadFile(fullPath)
 	if err != nil {
-		return false, fmt.Errorf("failed to compute hash for %s: %w", fullPath, err)
+		return false, fmt.Errorf("failed to read existing file %s: %w", fullPath, err)
 	}
 
+	existingHash := computeContentHash(string(existingContent))
 	newHash := computeContentHash(newContent)
 	return existingHash != newHash, nil
 }
@@ -134,7 +108,7 @@ func (g *Generator) renderTemplateOptimized(output config.Output, data *template
 		fullPath := filepath.Join(g.baseDir, templatePath)
 
 		// Read the template file
-		templateContent, err := os.ReadFile(fullPath)
+		templateContent, err := g.fs.ReadFile(fullPath)
 		if err != nil {
 			return "", fmt.Errorf("failed to read template file %s: %w", fullPath, err)
 		}
@@ -162,10 +136,52 @@ func (g *Generator) renderTemplateOptimized(output config.Output, data *template
 	return content, nil
 }
 
+// renderComposeOptimized is renderCompose's counterpart for the concurrent
+// generation path, rendering each fragment through renderTemplateOptimized.
+func (g *Generator) renderComposeOptimized(output config.Output, data *templates.TemplateData) (string, error) {
+	var b strings.Builder
+	for i, fragment := range output.Compose {
+		fragmentData, err := templates.FilterBySelector(data, fragment.Selector)
+		if err != nil {
+			return "", fmt.Errorf("invalid selector on output %s compose[%d]: %w", output.File, i, err)
+		}
+
+		fragmentOutput := output
+		fragmentOutput.Template = fragment.Template
+		fragmentOutput.Compose = nil
+		content, err := g.renderTemplateOptimized(fragmentOutput, fragmentData)
+		if err != nil {
+			return "", fmt.Errorf("failed to render output %s compose[%d]: %w", output.File, i, err)
+		}
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(content)
+	}
+	return b.String(), nil
+}
+
 // writeOutputFileOptimized writes a single output file with optimizations.
-func (g *Generator) writeOutputFileOptimized(output config.Output, data *templates.TemplateData) error {
+func (g *Generator) writeOutputFileOptimized(output config.Output, sharedData *templates.TemplateData) error {
+	if g.isIgnored(output.File) {
+		fmt.Fprintf(os.Stderr, "warning: skipping %s: ignored by .gitignore\n", output.File)
+		return nil
+	}
+
+	data := templates.FilterForOutput(sharedData, output)
+	data, err := templates.FilterBySelector(data, output.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector on output %s: %w", output.File, err)
+	}
+
 	// Render the template
-	content, err := g.renderTemplateOptimized(output, data)
+	var content string
+	if len(output.Compose) > 0 {
+		content, err = g.renderComposeOptimized(output, data)
+	} else {
+		content, err = g.renderTemplateOptimized(output, data)
+	}
 	if err != nil {
 		return err
 	}
@@ -152,6 +152,32 @@ func TestGenerator_CustomTemplate(t *testing.T) {
 	assert.Equal(t, "Custom: Custom Template Test has 1 rules", string(content))
 }
 
+func TestGenerator_CustomDelimiters(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	outputFile := "cursor.mdc"
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Delims Test"},
+		Outputs: []config.Output{
+			{
+				File:     outputFile,
+				Template: "Hello <%.ProjectName%>! Literal braces: {{ not a template action }}",
+				Delims:   []string{"<%", "%>"},
+			},
+		},
+		Rules: []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	gen := generator.NewWithBaseDir(tmpDir)
+	require.NoError(t, gen.GenerateOutput(cfg, outputFile))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, outputFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Hello Delims Test! Literal braces: {{ not a template action }}")
+}
+
 func TestGenerator_PreviewOutput(t *testing.T) {
 	t.Parallel()
 
@@ -235,6 +261,17 @@ func TestGenerator_ValidateTemplate(t *testing.T) {
 	}
 }
 
+func TestGenerator_ValidateTemplateForEngine(t *testing.T) {
+	t.Parallel()
+
+	gen := generator.New()
+
+	assert.NoError(t, gen.ValidateTemplateForEngine("{{.ProjectName}}", "go"))
+	assert.Error(t, gen.ValidateTemplateForEngine("{{.Invalid}", "go"))
+	assert.NoError(t, gen.ValidateTemplateForEngine("Hello {{ProjectName}}", "handlebars"))
+	assert.Error(t, gen.ValidateTemplateForEngine("{{.ProjectName}}", "jinja"))
+}
+
 func TestGenerator_GetSupportedTemplates(t *testing.T) {
 	t.Parallel()
 
@@ -283,6 +320,73 @@ func TestGenerator_DirectoryCreation(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGenerator_IncludeHelper(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	outputFile := "output.md"
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "LICENSE.txt"), []byte("MIT License"), 0o644))
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Include Test"},
+		Outputs: []config.Output{
+			{File: outputFile, Template: `{{.ProjectName}} says: {{include "LICENSE.txt"}}`},
+		},
+		Rules: []config.Rule{{Name: "Rule", Content: "Content"}},
+	}
+
+	gen := generator.NewWithBaseDir(tmpDir)
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, outputFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "MIT License")
+}
+
+func TestGenerator_GenerateAllDiff(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Diff Test"},
+		Outputs: []config.Output{
+			{File: "CLAUDE.md"},
+			{File: "new.md"},
+		},
+		Rules: []config.Rule{
+			{Name: "Rule 1", Content: "Content 1"},
+		},
+	}
+
+	gen := generator.NewWithBaseDir(tmpDir)
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	// Change the rule content so CLAUDE.md is now stale, and remove new.md so
+	// it shows up as a not-yet-created file.
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "new.md")))
+	cfg.Rules[0].Content = "Content 2"
+
+	diffs, err := gen.GenerateAllDiff(cfg)
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+
+	byFile := make(map[string]bool)
+	for _, d := range diffs {
+		byFile[d.File] = d.Changed
+	}
+	assert.True(t, byFile["CLAUDE.md"], "CLAUDE.md should be reported as changed")
+	assert.True(t, byFile["new.md"], "new.md should be reported as changed (not yet created)")
+
+	// GenerateAllDiff must not write anything to disk.
+	content, err := os.ReadFile(filepath.Join(tmpDir, "CLAUDE.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Content 1")
+	_, err = os.Stat(filepath.Join(tmpDir, "new.md"))
+	assert.True(t, os.IsNotExist(err), "new.md should not have been written")
+}
+
 func TestGenerator_TemplateVariables(t *testing.T) {
 	t.Parallel()
 
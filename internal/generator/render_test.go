@@ -0,0 +1,58 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/generator"
+)
+
+func TestGenerator_RenderAll(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Render Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	gen := generator.New()
+	rendered, err := gen.RenderAll(cfg)
+	require.NoError(t, err)
+	require.Contains(t, rendered, "CLAUDE.md")
+	assert.Contains(t, string(rendered["CLAUDE.md"]), "Rule 1")
+}
+
+func TestGenerator_PreviewAllMatchesRenderAll(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Render Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule 1", Content: "content"}},
+	}
+
+	gen := generator.New()
+	rendered, err := gen.RenderAll(cfg)
+	require.NoError(t, err)
+	preview, err := gen.PreviewAll(cfg)
+	require.NoError(t, err)
+
+	require.Equal(t, len(rendered), len(preview))
+	for file, content := range rendered {
+		assert.Equal(t, string(content), preview[file])
+	}
+}
+
+func TestGenerator_RenderAllNoOutputs(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Metadata: config.Metadata{Name: "Empty"}}
+
+	gen := generator.New()
+	_, err := gen.RenderAll(cfg)
+	assert.Error(t, err)
+}
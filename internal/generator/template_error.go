@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TemplateError wraps a text/template parse or execution failure with the
+// source position Go's text/template embeds in its own error text (e.g.
+// "template: NAME:3: unexpected ..."), pulled out into structured fields,
+// plus a snippet of the offending line. It's the generator-side
+// counterpart to config.ValidationError and config.ConfigError.
+type TemplateError struct {
+	Output  string // the output file being rendered
+	Source  string // @file path the template came from; "" for an inline/named template
+	Line    int
+	Column  int
+	Message string
+	Snippet string
+	Err     error
+}
+
+// templatePosPattern extracts the line (and, for execution errors, column)
+// text/template embeds in its error text, e.g. "template: NAME:3: ..." or
+// "template: NAME:3:12: executing ...: ...".
+var templatePosPattern = regexp.MustCompile(`template: .+?:(\d+)(?::(\d+))?:\s*(.*)`)
+
+// newTemplateError wraps err as a TemplateError if err's message carries a
+// text/template "NAME:LINE[:COL]:" position, attaching a snippet of
+// templateSrc's offending line. err is returned unchanged if no position
+// can be found (e.g. an unrelated error such as a missing @file), so
+// callers can use newTemplateError unconditionally.
+func newTemplateError(output, source, templateSrc string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	m := templatePosPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	column, _ := strconv.Atoi(m[2])
+	return &TemplateError{
+		Output:  output,
+		Source:  source,
+		Line:    line,
+		Column:  column,
+		Message: strings.TrimSpace(m[3]),
+		Snippet: snippetAt(templateSrc, line, column),
+		Err:     err,
+	}
+}
+
+// Error renders as "source:line[:col]: output: message" (falling back to
+// just "output: message" for an inline template, which has no source
+// file), followed by the offending line and a "^" underline when a
+// snippet was captured.
+func (e *TemplateError) Error() string {
+	var loc string
+	if e.Source != "" {
+		loc = fmt.Sprintf("%s:%d", e.Source, e.Line)
+		if e.Column > 0 {
+			loc += fmt.Sprintf(":%d", e.Column)
+		}
+		loc += ": "
+	}
+
+	msg := fmt.Sprintf("%s%s: %s", loc, e.Output, e.Message)
+	if e.Snippet == "" {
+		return msg
+	}
+	return msg + "\n" + e.Snippet
+}
+
+// Unwrap exposes the underlying text/template error to errors.Is/As.
+func (e *TemplateError) Unwrap() error { return e.Err }
+
+// snippetAt returns line (1-indexed) of src together with a "^" underline,
+// or "" if line falls outside src. column (also 1-indexed) positions the
+// underline directly when known (e.g. from a template execution error); 0
+// falls back to underlining the line's first non-blank character.
+func snippetAt(src string, line, column int) string {
+	lines := strings.Split(src, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	text := lines[line-1]
+	indent := column - 1
+	if column <= 0 {
+		indent = len(text) - len(strings.TrimLeft(text, " \t"))
+	}
+	if indent < 0 {
+		indent = 0
+	}
+	return fmt.Sprintf("    %s\n    %s^", text, strings.Repeat(" ", indent))
+}
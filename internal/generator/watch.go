@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/diffutil"
+)
+
+// WatchEventType identifies the kind of event Watch emits.
+type WatchEventType string
+
+const (
+	// WatchFileChanged fires once per triggering file change, before the
+	// debounced regeneration it's part of runs.
+	WatchFileChanged WatchEventType = "file_changed"
+	// WatchStart fires when a debounced regeneration begins.
+	WatchStart WatchEventType = "start"
+	// WatchFinish fires when a regeneration completes successfully.
+	WatchFinish WatchEventType = "finish"
+	// WatchError fires when reloading the config or regenerating fails; Watch
+	// keeps watching afterward rather than returning.
+	WatchError WatchEventType = "error"
+	// WatchReload fires when WatchOptions.Reload forces an immediate
+	// regeneration, before the WatchStart/WatchFinish pair it triggers.
+	WatchReload WatchEventType = "reload"
+)
+
+// WatchEvent is a structured notification Watch emits on its events channel,
+// so an LSP or editor extension can subscribe to watch progress instead of
+// scraping stdout.
+type WatchEvent struct {
+	Type WatchEventType
+	File string // the file that changed, set on WatchFileChanged
+	Err  error  // set on WatchError
+	// Diffs reports, per output, whether RenderAll's freshly rendered content
+	// differs from what was on disk just before GenerateAll wrote it.
+	// GenerateAll renders again for the write (served from the render cache
+	// when one is enabled), and Diffs does not take append, merge or skip
+	// modes into account. Set on WatchFinish.
+	Diffs []diffutil.FileDiff
+}
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Debounce delays regeneration after the first change in a burst, so a
+	// save that touches several included files only regenerates once.
+	// Defaults to 300ms when zero.
+	Debounce time.Duration
+	// Reload, when non-nil, forces an immediate full reload and
+	// regeneration on every receive, bypassing the debounce timer - the
+	// caller's SIGHUP handler is the intended sender, mirroring how
+	// consul-template treats SIGHUP as "reload now" rather than "a file
+	// changed".
+	Reload <-chan struct{}
+	// Ready, when non-nil, is closed once the fsnotify watcher is armed and
+	// watching every file it's going to watch - a test (or a caller that
+	// wants to avoid racing its first write against watcher setup) can wait
+	// on it before mutating a watched file.
+	Ready chan<- struct{}
+}
+
+const defaultWatchDebounce = 300 * time.Millisecond
+
+// Watch monitors configFile, every local file it includes (transitively),
+// and every @file-referenced template among its outputs, regenerating on
+// each change until ctx is done. Each regeneration reloads configFile from
+// scratch, so edits to the config itself (new outputs, changed includes)
+// take effect without restarting the watch. Regeneration reuses
+// writeOutputFile's content-hash short-circuit (shouldWriteFile), so an
+// output whose rendered content hasn't changed is left untouched on disk.
+// events, when non-nil, receives a WatchFileChanged per triggering change
+// and a WatchStart/WatchFinish (or WatchError) pair per regeneration; Watch
+// never closes events. Watch blocks until ctx is done, returning ctx.Err();
+// it returns earlier with an error if the initial watch setup fails, and
+// with nil if the fsnotify watcher's channels close.
+func (g *Generator) Watch(ctx context.Context, configFile string, opts WatchOptions, events chan<- WatchEvent) error {
+	debounce := opts.Debounce
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := g.syncWatchedFiles(watcher, configFile, nil); err != nil {
+		return err
+	}
+
+	if opts.Ready != nil {
+		close(opts.Ready)
+	}
+
+	timer := time.NewTimer(debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	pending := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			emitWatchEvent(events, WatchEvent{Type: WatchError, Err: err})
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			emitWatchEvent(events, WatchEvent{Type: WatchFileChanged, File: event.Name})
+			if !timer.Stop() && pending {
+				<-timer.C
+			}
+			timer.Reset(debounce)
+			pending = true
+
+		case <-timer.C:
+			pending = false
+			g.regenerateOnChange(watcher, configFile, events)
+
+		case <-opts.Reload:
+			if !timer.Stop() && pending {
+				<-timer.C
+			}
+			pending = false
+			emitWatchEvent(events, WatchEvent{Type: WatchReload})
+			g.regenerateOnChange(watcher, configFile, events)
+		}
+	}
+}
+
+// regenerateOnChange reloads configFile and regenerates its outputs,
+// re-syncing the watcher against the reloaded config's includes and
+// template files so a changed includes: list or @template reference takes
+// effect on the next debounced run. It renders via RenderAll to compute the
+// rewritten-vs-unchanged Diffs reported on WatchFinish, then writes the
+// outputs through GenerateAll's normal path, which renders them again
+// (reusing RenderAll's render-cache entries when the cache is enabled).
+func (g *Generator) regenerateOnChange(watcher *fsnotify.Watcher, configFile string, events chan<- WatchEvent) {
+	emitWatchEvent(events, WatchEvent{Type: WatchStart})
+
+	cfg, err := config.LoadConfigWithIncludes(configFile)
+	if err != nil {
+		emitWatchEvent(events, WatchEvent{Type: WatchError, Err: err})
+		return
+	}
+
+	rendered, err := g.RenderAll(cfg)
+	if err != nil {
+		emitWatchEvent(events, WatchEvent{Type: WatchError, Err: err})
+		return
+	}
+
+	diffs, err := diffutil.Compute(g.baseDir, rendered)
+	if err != nil {
+		emitWatchEvent(events, WatchEvent{Type: WatchError, Err: err})
+		return
+	}
+
+	if err := g.GenerateAll(cfg); err != nil {
+		emitWatchEvent(events, WatchEvent{Type: WatchError, Err: err})
+		return
+	}
+
+	if err := g.syncWatchedFiles(watcher, configFile, cfg); err != nil {
+		emitWatchEvent(events, WatchEvent{Type: WatchError, Err: err})
+		return
+	}
+
+	emitWatchEvent(events, WatchEvent{Type: WatchFinish, Diffs: diffs})
+}
+
+// syncWatchedFiles adds configFile, every file it includes, and every
+// @file-referenced template in cfg's outputs to watcher. cfg may be nil for
+// the initial call, before the config has been loaded once. Adding a path
+// already being watched is a harmless no-op.
+func (g *Generator) syncWatchedFiles(watcher *fsnotify.Watcher, configFile string, cfg *config.Config) error {
+	files, err := config.WatchedFiles(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to collect included files: %w", err)
+	}
+
+	if cfg == nil {
+		loaded, loadErr := config.LoadConfigWithIncludes(configFile)
+		if loadErr != nil {
+			return fmt.Errorf("failed to load configuration: %w", loadErr)
+		}
+		cfg = loaded
+	}
+	files = append(files, g.templateFiles(cfg)...)
+
+	for _, file := range files {
+		if err := watcher.Add(file); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// templateFiles returns the absolute paths of every @file-referenced
+// template among cfg's outputs, resolved against g.baseDir the same way
+// renderTemplate resolves them.
+func (g *Generator) templateFiles(cfg *config.Config) []string {
+	seen := make(map[string]bool)
+	var files []string
+
+	for _, output := range cfg.Outputs {
+		if !strings.HasPrefix(output.Template, "@") {
+			continue
+		}
+		fullPath := filepath.Join(g.baseDir, strings.TrimPrefix(output.Template, "@"))
+		if seen[fullPath] {
+			continue
+		}
+		seen[fullPath] = true
+		files = append(files, fullPath)
+	}
+
+	return files
+}
+
+func emitWatchEvent(events chan<- WatchEvent, event WatchEvent) {
+	if events == nil {
+		return
+	}
+	events <- event
+}
@@ -0,0 +1,154 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/diffutil"
+	"github.com/Goldziher/ai-rulez/internal/generator"
+)
+
+func TestGenerator_Watch_RegeneratesOnRuleChange(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "ai-rulez.yaml")
+	outputPath := filepath.Join(tmpDir, "CLAUDE.md")
+
+	writeConfig := func(ruleContent string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(`
+metadata:
+  name: Watch Project
+outputs:
+  - file: CLAUDE.md
+rules:
+  - name: Rule 1
+    content: `+ruleContent+`
+`), 0o644))
+	}
+	writeConfig("original content")
+
+	gen := generator.NewWithBaseDir(tmpDir)
+	cfg, err := config.LoadConfigWithIncludes(configPath)
+	require.NoError(t, err)
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	content, readErr := os.ReadFile(outputPath)
+	require.NoError(t, readErr)
+	require.Contains(t, string(content), "original content")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan generator.WatchEvent, 16)
+	ready := make(chan struct{})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- gen.Watch(ctx, configPath, generator.WatchOptions{Debounce: 10 * time.Millisecond, Ready: ready}, events)
+	}()
+	<-ready
+
+	writeConfig("updated content")
+
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(outputPath)
+		return err == nil && strings.Contains(string(content), "updated content")
+	}, 2*time.Second, 20*time.Millisecond, "expected regeneration after the config file changed")
+
+	var sawFinish bool
+	var finishDiffs []diffutil.FileDiff
+drain:
+	for {
+		select {
+		case event := <-events:
+			if event.Type == generator.WatchFinish {
+				sawFinish = true
+				finishDiffs = event.Diffs
+			}
+		default:
+			break drain
+		}
+	}
+	assert.True(t, sawFinish, "expected at least one WatchFinish event")
+	require.Len(t, finishDiffs, 1)
+	assert.Equal(t, "CLAUDE.md", finishDiffs[0].File)
+	assert.True(t, finishDiffs[0].Changed, "expected the rule content change to be reflected in WatchFinish's Diffs")
+
+	cancel()
+	err = <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGenerator_Watch_ReloadForcesImmediateRegeneration(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "ai-rulez.yaml")
+	outputPath := filepath.Join(tmpDir, "CLAUDE.md")
+
+	writeConfig := func(ruleContent string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(`
+metadata:
+  name: Watch Project
+outputs:
+  - file: CLAUDE.md
+rules:
+  - name: Rule 1
+    content: `+ruleContent+`
+`), 0o644))
+	}
+	writeConfig("original content")
+
+	gen := generator.NewWithBaseDir(tmpDir)
+	cfg, err := config.LoadConfigWithIncludes(configPath)
+	require.NoError(t, err)
+	require.NoError(t, gen.GenerateAll(cfg))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A debounce long enough that, absent Reload, the assertion below would
+	// time out before the regular file-watch path ever fires.
+	reload := make(chan struct{}, 1)
+	events := make(chan generator.WatchEvent, 16)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- gen.Watch(ctx, configPath, generator.WatchOptions{Debounce: time.Minute, Reload: reload}, events)
+	}()
+
+	writeConfig("updated content")
+	reload <- struct{}{}
+
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(outputPath)
+		return err == nil && strings.Contains(string(content), "updated content")
+	}, 2*time.Second, 20*time.Millisecond, "expected Reload to force regeneration despite the long debounce")
+
+	var sawReload bool
+drain:
+	for {
+		select {
+		case event := <-events:
+			if event.Type == generator.WatchReload {
+				sawReload = true
+			}
+		default:
+			break drain
+		}
+	}
+	assert.True(t, sawReload, "expected a WatchReload event")
+
+	cancel()
+	err = <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -9,8 +9,13 @@ import (
 	"strings"
 
 	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/modules"
 )
 
+// inTreeModuleCacheDir is the conventional location teams use when they
+// want the remote-include module cache committed to disk but not to git.
+const inTreeModuleCacheDir = ".ai-rulez/modules/"
+
 // UpdateGitignoreFiles updates .gitignore files in the directories containing config files
 // to include the generated output files if they're not already ignored.
 func UpdateGitignoreFiles(configFile string, cfg *config.Config) error {
@@ -27,7 +32,18 @@ func UpdateGitignoreFiles(configFile string, cfg *config.Config) error {
 		return nil
 	}
 
-	return updateGitignoreFile(gitignorePath, outputFiles)
+	// Developer-local overlay files (e.g. ai-rulez.local.yaml) are never
+	// meant to be committed, so keep them ignored alongside generated outputs.
+	outputFiles = append(outputFiles, "*.local.yaml")
+
+	// If a lockfile is present, a team may keep its resolved module cache
+	// in-tree next to it; that cache dir is reproducible from the lockfile
+	// and shouldn't be committed.
+	if _, err := os.Stat(filepath.Join(configDir, modules.LockfileName)); err == nil {
+		outputFiles = append(outputFiles, inTreeModuleCacheDir)
+	}
+
+	return updateGitignoreFile(configDir, gitignorePath, outputFiles)
 }
 
 // UpdateGitignoreFilesRecursive updates .gitignore files for all provided config files
@@ -47,19 +63,30 @@ func UpdateGitignoreFilesRecursive(configFiles []string) error {
 }
 
 // updateGitignoreFile adds the specified files to the .gitignore file if they're not already present
-func updateGitignoreFile(gitignorePath string, outputFiles []string) error {
-	// Read existing gitignore content
+func updateGitignoreFile(configDir, gitignorePath string, outputFiles []string) error {
+	// Read and compile existing gitignore content once, rather than
+	// re-parsing patterns for every candidate file.
 	existingEntries, err := readGitignoreEntries(gitignorePath)
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to read .gitignore: %w", err)
 	}
 
-	// Find which output files need to be added
+	patternSet, err := CompileGlobs(existingEntries)
+	if err != nil {
+		return fmt.Errorf("failed to compile .gitignore patterns: %w", err)
+	}
+
+	// Find which output files need to be added, skipping anything already
+	// ignored locally or by an ancestor .gitignore further up the tree.
 	var toAdd []string
 	for _, outputFile := range outputFiles {
-		if !isIgnored(outputFile, existingEntries) {
-			toAdd = append(toAdd, outputFile)
+		if patternSet.Match(outputFile) {
+			continue
 		}
+		if ancestorIgnores(configDir, outputFile) {
+			continue
+		}
+		toAdd = append(toAdd, outputFile)
 	}
 
 	// If nothing to add, we're done
@@ -91,62 +118,75 @@ func readGitignoreEntries(gitignorePath string) ([]string, error) {
 	return entries, scanner.Err()
 }
 
-// isIgnored checks if a file would be ignored by any of the existing gitignore patterns
-func isIgnored(filename string, patterns []string) bool {
-	for _, pattern := range patterns {
-		if matchesPattern(filename, pattern) {
-			return true
-		}
+// ancestorIgnores reports whether filename (a path relative to configDir)
+// is already ignored by a .gitignore file strictly above configDir. It
+// walks upward one directory at a time, checking each ancestor's own
+// .gitignore against filename re-based relative to that ancestor, and
+// stops at the first ancestor whose patterns have anything to say about
+// it - a closer ancestor that's silent on filename falls through to the
+// next one up, but one that explicitly re-includes it via negation is
+// respected rather than papered over by a further-out ignore. The walk
+// itself stops at a directory containing .git (the repository root) or
+// at the filesystem root, whichever comes first.
+func ancestorIgnores(configDir, filename string) bool {
+	absConfigDir, err := filepath.Abs(configDir)
+	if err != nil {
+		return false
 	}
-	return false
-}
+	target := filepath.Join(absConfigDir, filename)
 
-// matchesPattern checks if a filename matches a gitignore pattern
-// This is a simplified implementation that handles basic patterns
-func matchesPattern(filename, pattern string) bool {
-	// Exact match
-	if pattern == filename {
-		return true
-	}
+	for dir := absConfigDir; ; {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
 
-	// Pattern ends with / - directory only
-	if strings.HasSuffix(pattern, "/") {
-		return false // We're dealing with files, not directories
-	}
+		rel, err := filepath.Rel(dir, target)
+		if err != nil {
+			return false
+		}
 
-	// Pattern with wildcards
-	if strings.Contains(pattern, "*") {
-		return matchesWildcard(filename, pattern)
-	}
+		ps, err := LoadPatternSet(filepath.Join(dir, ".gitignore"))
+		if err != nil {
+			return false
+		}
+		if ignored, matched := ps.Matched(rel); matched {
+			return ignored
+		}
 
-	// Pattern starting with / - absolute path from repo root
-	if strings.HasPrefix(pattern, "/") {
-		return filename == strings.TrimPrefix(pattern, "/")
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return false
+		}
 	}
-
-	// Simple name or substring match for patterns without special chars
-	return filename == pattern || strings.HasSuffix(filename, "/"+pattern) || strings.Contains(filename, pattern)
 }
 
-// matchesWildcard performs basic wildcard matching
-func matchesWildcard(filename, pattern string) bool {
-	// Very basic wildcard implementation - handles *.extension patterns
-	if pattern == "*" {
-		return true
-	}
-
-	if strings.HasPrefix(pattern, "*.") {
-		extension := strings.TrimPrefix(pattern, "*")
-		return strings.HasSuffix(filename, extension)
+// LoadPatternSet reads and compiles the .gitignore file at path. A missing
+// file yields an empty, always-non-matching PatternSet.
+func LoadPatternSet(gitignorePath string) (*PatternSet, error) {
+	entries, err := readGitignoreEntries(gitignorePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return CompileGlobs(nil)
+		}
+		return nil, fmt.Errorf("failed to read .gitignore: %w", err)
 	}
+	return CompileGlobs(entries)
+}
 
-	if strings.HasSuffix(pattern, "*") {
-		prefix := strings.TrimSuffix(pattern, "*")
-		return strings.HasPrefix(filename, prefix)
+// isIgnored checks if a file would be ignored by any of the given raw gitignore patterns.
+func isIgnored(filename string, patterns []string) bool {
+	ps, err := CompileGlobs(patterns)
+	if err != nil {
+		return false
 	}
+	return ps.Match(filename)
+}
 
-	// For more complex patterns, do a simple contains check
-	return strings.Contains(filename, strings.ReplaceAll(pattern, "*", ""))
+// matchesPattern checks if a filename matches a single gitignore pattern,
+// following the same precedence rules as PatternSet.Match.
+func matchesPattern(filename, pattern string) bool {
+	return isIgnored(filename, []string{pattern})
 }
 
 // appendToGitignore appends new entries to the .gitignore file
@@ -80,6 +80,34 @@ func TestUpdateGitignoreFiles(t *testing.T) {
 	}
 }
 
+func TestUpdateGitignoreFilesIgnoresLocalOverlay(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gitignore_local_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	configPath := filepath.Join(tmpDir, "ai-rulez.yaml")
+	cfg := &config.Config{
+		Outputs: []config.Output{
+			{File: "CLAUDE.md"},
+		},
+	}
+
+	if err := UpdateGitignoreFiles(configPath, cfg); err != nil {
+		t.Fatalf("UpdateGitignoreFiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("Failed to read .gitignore: %v", err)
+	}
+
+	if !strings.Contains(string(content), "*.local.yaml") {
+		t.Error("Expected .gitignore to contain the *.local.yaml overlay pattern")
+	}
+}
+
 func TestMatchesPattern(t *testing.T) {
 	tests := []struct {
 		filename string
@@ -105,9 +133,16 @@ func TestMatchesPattern(t *testing.T) {
 		{"CLAUDE.md", "/CLAUDE.md", true},
 		{"subdir/CLAUDE.md", "/CLAUDE.md", false},
 
-		// Substring matching
-		{"generated_file.md", "generated", true},
+		// Whole-segment matching only: a bare pattern must match a full
+		// path segment, not an arbitrary substring of it.
+		{"generated_file.md", "generated", false},
+		{"generated", "generated", true},
 		{"my_file.txt", "generated", false},
+
+		// Recursive "**" globs
+		{"a/b/c/file.md", "**/file.md", true},
+		{"file.md", "**/file.md", true},
+		{"a/b/anything", "a/**", true},
 	}
 
 	for _, test := range tests {
@@ -148,6 +183,27 @@ func TestIsIgnored(t *testing.T) {
 	}
 }
 
+func TestIsIgnoredNegation(t *testing.T) {
+	patterns := []string{"*.md", "!CLAUDE.md"}
+
+	if !isIgnored("README.md", patterns) {
+		t.Error("expected README.md to be ignored by *.md")
+	}
+	if isIgnored("CLAUDE.md", patterns) {
+		t.Error("expected CLAUDE.md to be re-included by the later !CLAUDE.md negation")
+	}
+}
+
+func TestLoadPatternSetMissingFile(t *testing.T) {
+	ps, err := LoadPatternSet(filepath.Join(t.TempDir(), ".gitignore"))
+	if err != nil {
+		t.Fatalf("LoadPatternSet failed: %v", err)
+	}
+	if ps.Match("anything.md") {
+		t.Error("expected a missing .gitignore to ignore nothing")
+	}
+}
+
 func TestReadGitignoreEntries(t *testing.T) {
 	// Create a temporary file
 	tmpDir, err := os.MkdirTemp("", "gitignore_read_test")
@@ -212,4 +268,79 @@ func TestUpdateGitignoreFilesWithNoOutputs(t *testing.T) {
 	if _, err := os.Stat(gitignorePath); err == nil {
 		t.Error("Expected .gitignore not to be created when there are no outputs")
 	}
-}
\ No newline at end of file
+}
+
+func TestUpdateGitignoreFilesSkipsEntriesAlreadyIgnoredByAncestor(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".git"), nil, 0644); err != nil {
+		t.Fatalf("Failed to mark repo root: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.md\n"), 0644); err != nil {
+		t.Fatalf("Failed to write root .gitignore: %v", err)
+	}
+
+	subDir := filepath.Join(root, "packages", "api")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create subdir: %v", err)
+	}
+
+	configPath := filepath.Join(subDir, "ai-rulez.yaml")
+	cfg := &config.Config{
+		Outputs: []config.Output{
+			{File: "CLAUDE.md"},    // already ignored by the root .gitignore's *.md
+			{File: ".cursorrules"}, // not covered by any ancestor, should still be added
+		},
+	}
+
+	if err := UpdateGitignoreFiles(configPath, cfg); err != nil {
+		t.Fatalf("UpdateGitignoreFiles failed: %v", err)
+	}
+
+	subGitignore := filepath.Join(subDir, ".gitignore")
+	content, err := os.ReadFile(subGitignore)
+	if err != nil {
+		t.Fatalf("Failed to read .gitignore: %v", err)
+	}
+
+	contentStr := string(content)
+	if strings.Contains(contentStr, "CLAUDE.md") {
+		t.Error("Expected CLAUDE.md not to be duplicated; the root .gitignore already ignores *.md")
+	}
+	if !strings.Contains(contentStr, ".cursorrules") {
+		t.Error("Expected .cursorrules to still be added; no ancestor .gitignore covers it")
+	}
+}
+
+func TestUpdateGitignoreFilesRespectsAncestorNegation(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".git"), nil, 0644); err != nil {
+		t.Fatalf("Failed to mark repo root: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.md\n!packages/api/CLAUDE.md\n"), 0644); err != nil {
+		t.Fatalf("Failed to write root .gitignore: %v", err)
+	}
+
+	subDir := filepath.Join(root, "packages", "api")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create subdir: %v", err)
+	}
+
+	configPath := filepath.Join(subDir, "ai-rulez.yaml")
+	cfg := &config.Config{
+		Outputs: []config.Output{
+			{File: "CLAUDE.md"}, // re-included by the root's negated pattern, so it still needs tracking
+		},
+	}
+
+	if err := UpdateGitignoreFiles(configPath, cfg); err != nil {
+		t.Fatalf("UpdateGitignoreFiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(subDir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("Failed to read .gitignore: %v", err)
+	}
+	if !strings.Contains(string(content), "CLAUDE.md") {
+		t.Error("Expected CLAUDE.md to be added: the root .gitignore explicitly re-includes it")
+	}
+}
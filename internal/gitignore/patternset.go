@@ -0,0 +1,186 @@
+package gitignore
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pattern is a single compiled gitignore rule.
+type pattern struct {
+	raw      string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+	re       *regexp.Regexp
+}
+
+// PatternSet is a compiled, ordered collection of gitignore rules. Later
+// patterns take precedence over earlier ones, and a negated pattern
+// (`!pattern`) re-includes a path excluded by an earlier rule - this
+// mirrors git's own precedence rules instead of the ad-hoc substring/prefix
+// checks the original matchesPattern used.
+type PatternSet struct {
+	patterns []pattern
+}
+
+// CompileGlobs compiles a set of raw .gitignore lines (comments and blank
+// lines already stripped) into a PatternSet, analogous to treefmt's
+// CompileGlobs: parsing happens once here rather than per candidate path.
+func CompileGlobs(lines []string) (*PatternSet, error) {
+	ps := &PatternSet{patterns: make([]pattern, 0, len(lines))}
+	for _, line := range lines {
+		p, err := compilePattern(line)
+		if err != nil {
+			return nil, err
+		}
+		ps.patterns = append(ps.patterns, p)
+	}
+	return ps, nil
+}
+
+// Match reports whether path is ignored under this PatternSet, applying
+// negation precedence: the last matching pattern wins. path is treated as
+// relative to the .gitignore's directory.
+func (ps *PatternSet) Match(path string) bool {
+	if ps == nil {
+		return false
+	}
+
+	path = filepath.ToSlash(strings.TrimPrefix(path, "/"))
+
+	ignored := false
+	for _, p := range ps.patterns {
+		if p.dirOnly {
+			// This matcher only ever evaluates file paths, so directory-only
+			// patterns (trailing "/") never match.
+			continue
+		}
+		if p.appliesTo(path) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// Matched reports whether path is ignored under this PatternSet (like
+// Match), and also whether any pattern in ps applied to path at all. A
+// gitignore file that simply has nothing to say about a path (matched ==
+// false) is different from one that explicitly re-includes it via
+// negation (matched == true, ignored == false) - callers walking a stack
+// of ancestor .gitignore files need that distinction to fall through to
+// the next ancestor only in the former case.
+func (ps *PatternSet) Matched(path string) (ignored bool, matched bool) {
+	if ps == nil {
+		return false, false
+	}
+
+	path = filepath.ToSlash(strings.TrimPrefix(path, "/"))
+
+	for _, p := range ps.patterns {
+		if p.dirOnly {
+			continue
+		}
+		if p.appliesTo(path) {
+			ignored = !p.negate
+			matched = true
+		}
+	}
+	return ignored, matched
+}
+
+func (p pattern) appliesTo(path string) bool {
+	if p.anchored {
+		return p.re.MatchString(path)
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if p.re.MatchString(segment) {
+			return true
+		}
+	}
+	return p.re.MatchString(path)
+}
+
+// compilePattern parses a single gitignore line into a pattern.
+func compilePattern(raw string) (pattern, error) {
+	s := raw
+
+	negate := false
+	if strings.HasPrefix(s, "!") {
+		negate = true
+		s = s[1:]
+	} else if strings.HasPrefix(s, `\!`) || strings.HasPrefix(s, `\#`) {
+		s = s[1:] // escaped leading ! or #, treat literally
+	}
+
+	dirOnly := strings.HasSuffix(s, "/") && !strings.HasSuffix(s, `\/`)
+	if dirOnly {
+		s = strings.TrimSuffix(s, "/")
+	}
+
+	anchored := strings.HasPrefix(s, "/")
+	s = strings.TrimPrefix(s, "/")
+	// A pattern containing a non-trailing slash is anchored to the
+	// .gitignore's directory rather than matching at any depth.
+	anchored = anchored || strings.Contains(s, "/")
+
+	regexBody := globToRegex(s)
+	re, err := regexp.Compile("^" + regexBody + "$")
+	if err != nil {
+		return pattern{}, err
+	}
+
+	return pattern{raw: raw, negate: negate, dirOnly: dirOnly, anchored: anchored, re: re}, nil
+}
+
+// globToRegex translates gitignore glob syntax ("**", "*", "?", "[...]")
+// into an equivalent regular expression body.
+func globToRegex(glob string) string {
+	var sb strings.Builder
+	runes := []rune(glob)
+
+	for i := 0; i < len(runes); {
+		switch c := runes[i]; c {
+		case '*':
+			switch {
+			case i+2 < len(runes) && runes[i+1] == '*' && runes[i+2] == '/':
+				sb.WriteString("(?:.*/)?")
+				i += 3
+			case i+1 < len(runes) && runes[i+1] == '*':
+				sb.WriteString(".*")
+				i += 2
+			default:
+				sb.WriteString("[^/]*")
+				i++
+			}
+		case '?':
+			sb.WriteString("[^/]")
+			i++
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end < len(runes) {
+				sb.WriteString(string(runes[i : end+1]))
+				i = end + 1
+			} else {
+				sb.WriteString(`\[`)
+				i++
+			}
+		case '\\':
+			if i+1 < len(runes) {
+				sb.WriteString(regexp.QuoteMeta(string(runes[i+1])))
+				i += 2
+			} else {
+				i++
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+			i++
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,356 @@
+// Package history gives MCP-driven config mutations a rolling undo/redo
+// trail: before each save, the pre-mutation config is snapshotted into a
+// ".ai-rulez/history" directory next to the config file and a journal
+// entry records what operation ran, with what arguments, by whom. This
+// turns an agent's destructive edit into something a caller can walk back
+// via Undo/Redo or reach into directly via RestoreSnapshot.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultMaxEntries is how many journal entries (and their snapshots) are
+// kept when Record is called with maxEntries <= 0.
+const DefaultMaxEntries = 50
+
+// Entry is one journal record: the operation that mutated the config, its
+// arguments, who ran it, and the snapshot of the config as it stood right
+// before the mutation was applied.
+type Entry struct {
+	Timestamp    time.Time      `json:"timestamp"`
+	Op           string         `json:"op"`
+	Args         map[string]any `json:"args,omitempty"`
+	Actor        string         `json:"actor,omitempty"`
+	Snapshot     string         `json:"snapshot"`
+	PrevRevision string         `json:"prev_revision,omitempty"`
+	NewRevision  string         `json:"new_revision,omitempty"`
+}
+
+// redoEntry pairs a journal Entry that Undo rolled back with a snapshot
+// of the config as it stood right before the undo, so Redo can restore
+// forward to exactly that state.
+type redoEntry struct {
+	Entry           Entry  `json:"entry"`
+	ForwardSnapshot string `json:"forward_snapshot"`
+}
+
+func historyDir(configFile string) string {
+	return filepath.Join(filepath.Dir(configFile), ".ai-rulez", "history")
+}
+
+func journalPath(configFile string) string {
+	return filepath.Join(historyDir(configFile), "journal.jsonl")
+}
+
+func redoStackPath(configFile string) string {
+	return filepath.Join(historyDir(configFile), "redo.jsonl")
+}
+
+// Record snapshots preImage - configFile's raw bytes immediately before
+// op was applied - into its history directory, appends a journal entry
+// describing the mutation, clears any pending redo stack (a new edit
+// invalidates it, same as an editor's undo history), and prunes to
+// maxEntries (DefaultMaxEntries if maxEntries <= 0).
+func Record(configFile, op string, args map[string]any, actor string, preImage []byte, prevRevision, newRevision string, maxEntries int) (Entry, error) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	dir := historyDir(configFile)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Entry{}, fmt.Errorf("failed to create history directory %s: %w", dir, err)
+	}
+
+	snapshotName := snapshotFilename(configFile, time.Now().UTC(), op, prevRevision)
+	if err := os.WriteFile(filepath.Join(dir, snapshotName), preImage, 0o644); err != nil {
+		return Entry{}, fmt.Errorf("failed to write snapshot %s: %w", snapshotName, err)
+	}
+
+	entry := Entry{
+		Timestamp:    time.Now().UTC(),
+		Op:           op,
+		Args:         args,
+		Actor:        actor,
+		Snapshot:     snapshotName,
+		PrevRevision: prevRevision,
+		NewRevision:  newRevision,
+	}
+
+	if err := appendEntry(configFile, entry); err != nil {
+		return Entry{}, err
+	}
+
+	_ = os.Remove(redoStackPath(configFile))
+
+	return entry, prune(configFile, maxEntries)
+}
+
+// List returns configFile's journal entries in chronological order, or
+// nil if it has no history yet.
+func List(configFile string) ([]Entry, error) {
+	return readEntries(configFile)
+}
+
+// Undo restores configFile to the state it was in immediately before the
+// most recent journal entry's operation, moving that entry onto the redo
+// stack. It returns the entry that was undone.
+func Undo(configFile string) (*Entry, error) {
+	entries, err := readEntries(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no history to undo")
+	}
+	last := entries[len(entries)-1]
+
+	dir := historyDir(configFile)
+	current, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current config: %w", err)
+	}
+
+	forwardName := snapshotFilename(configFile, time.Now().UTC(), "undo-point-"+last.Op, last.NewRevision)
+	if err := os.WriteFile(filepath.Join(dir, forwardName), current, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to snapshot current state for redo: %w", err)
+	}
+
+	snapshotData, err := os.ReadFile(filepath.Join(dir, last.Snapshot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", last.Snapshot, err)
+	}
+	if err := os.WriteFile(configFile, snapshotData, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to restore snapshot %s: %w", last.Snapshot, err)
+	}
+
+	if err := writeEntries(configFile, entries[:len(entries)-1]); err != nil {
+		return nil, err
+	}
+	if err := appendRedo(configFile, redoEntry{Entry: last, ForwardSnapshot: forwardName}); err != nil {
+		return nil, err
+	}
+
+	return &last, nil
+}
+
+// Redo re-applies the most recently undone operation, restoring
+// configFile to the state Undo snapshotted right before rolling it back.
+func Redo(configFile string) (*Entry, error) {
+	redos, err := readRedoStack(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(redos) == 0 {
+		return nil, fmt.Errorf("no undone history to redo")
+	}
+	last := redos[len(redos)-1]
+
+	dir := historyDir(configFile)
+	data, err := os.ReadFile(filepath.Join(dir, last.ForwardSnapshot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redo snapshot %s: %w", last.ForwardSnapshot, err)
+	}
+	if err := os.WriteFile(configFile, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to restore redo snapshot %s: %w", last.ForwardSnapshot, err)
+	}
+
+	if err := writeRedoStack(configFile, redos[:len(redos)-1]); err != nil {
+		return nil, err
+	}
+
+	entries, err := readEntries(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntries(configFile, append(entries, last.Entry)); err != nil {
+		return nil, err
+	}
+
+	entry := last.Entry
+	return &entry, nil
+}
+
+// RestoreSnapshot restores configFile's content directly from the named
+// snapshot file in its history directory (as named by an Entry.Snapshot
+// from List), without touching the journal or redo stack - a manual
+// escape hatch distinct from the linear Undo/Redo walk.
+func RestoreSnapshot(configFile, snapshotName string) error {
+	if snapshotName == "" || strings.ContainsAny(snapshotName, `/\`) {
+		return fmt.Errorf("invalid snapshot name %q", snapshotName)
+	}
+
+	path := filepath.Join(historyDir(configFile), snapshotName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot %s: %w", snapshotName, err)
+	}
+	if err := os.WriteFile(configFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to restore snapshot %s: %w", snapshotName, err)
+	}
+	return nil
+}
+
+func readEntries(configFile string) ([]Entry, error) {
+	lines, err := readLines(journalPath(configFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(lines))
+	for _, line := range lines {
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func writeEntries(configFile string, entries []Entry) error {
+	values := make([]any, len(entries))
+	for i, entry := range entries {
+		values[i] = entry
+	}
+	return writeLines(journalPath(configFile), values)
+}
+
+func appendEntry(configFile string, entry Entry) error {
+	return appendLine(journalPath(configFile), entry)
+}
+
+func readRedoStack(configFile string) ([]redoEntry, error) {
+	lines, err := readLines(redoStackPath(configFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redo stack: %w", err)
+	}
+
+	redos := make([]redoEntry, 0, len(lines))
+	for _, line := range lines {
+		var redo redoEntry
+		if err := json.Unmarshal([]byte(line), &redo); err != nil {
+			return nil, fmt.Errorf("failed to parse redo entry: %w", err)
+		}
+		redos = append(redos, redo)
+	}
+	return redos, nil
+}
+
+func writeRedoStack(configFile string, redos []redoEntry) error {
+	values := make([]any, len(redos))
+	for i, redo := range redos {
+		values[i] = redo
+	}
+	return writeLines(redoStackPath(configFile), values)
+}
+
+func appendRedo(configFile string, redo redoEntry) error {
+	return appendLine(redoStackPath(configFile), redo)
+}
+
+// prune keeps only the most recent maxEntries journal entries, deleting
+// the snapshot files that fall off the front.
+func prune(configFile string, maxEntries int) error {
+	entries, err := readEntries(configFile)
+	if err != nil {
+		return err
+	}
+	if len(entries) <= maxEntries {
+		return nil
+	}
+
+	drop := entries[:len(entries)-maxEntries]
+	keep := entries[len(entries)-maxEntries:]
+	for _, entry := range drop {
+		_ = os.Remove(filepath.Join(historyDir(configFile), entry.Snapshot))
+	}
+	return writeEntries(configFile, keep)
+}
+
+func snapshotFilename(configFile string, ts time.Time, op, revision string) string {
+	hash := revision
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	ext := filepath.Ext(configFile)
+	if ext == "" {
+		ext = ".yaml"
+	}
+	return fmt.Sprintf("%s-%s-%s%s", ts.Format("20060102T150405.000000000"), sanitizeOp(op), hash, ext)
+}
+
+func sanitizeOp(op string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, op)
+}
+
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func writeLines(path string, values []any) error {
+	var b strings.Builder
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", path, err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func appendLine(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/history"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestRecordAndList(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeConfig(t, configPath, "rules: []\n")
+
+	_, err := history.Record(configPath, "add_rule", map[string]any{"name": "r1"}, "session-1", []byte("rules: []\n"), "rev1", "rev2", 0)
+	require.NoError(t, err)
+
+	entries, err := history.List(configPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "add_rule", entries[0].Op)
+	assert.Equal(t, "session-1", entries[0].Actor)
+	assert.Equal(t, "rev1", entries[0].PrevRevision)
+}
+
+func TestUndoRedoRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeConfig(t, configPath, "rules: []\n")
+
+	_, err := history.Record(configPath, "add_rule", nil, "session-1", []byte("rules: []\n"), "rev1", "rev2", 0)
+	require.NoError(t, err)
+	writeConfig(t, configPath, "rules:\n  - name: r1\n")
+
+	undone, err := history.Undo(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "add_rule", undone.Op)
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "rules: []\n", string(data))
+
+	entries, err := history.List(configPath)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	redone, err := history.Redo(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "add_rule", redone.Op)
+
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "rules:\n  - name: r1\n", string(data))
+
+	entries, err = history.List(configPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestUndoWithNoHistoryErrors(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeConfig(t, configPath, "rules: []\n")
+
+	_, err := history.Undo(configPath)
+	assert.Error(t, err)
+}
+
+func TestRecordClearsRedoStack(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeConfig(t, configPath, "rules: []\n")
+
+	_, err := history.Record(configPath, "add_rule", nil, "", []byte("rules: []\n"), "rev1", "rev2", 0)
+	require.NoError(t, err)
+	writeConfig(t, configPath, "rules:\n  - name: r1\n")
+	_, err = history.Undo(configPath)
+	require.NoError(t, err)
+
+	_, err = history.Record(configPath, "add_rule", nil, "", []byte("rules: []\n"), "rev1", "rev3", 0)
+	require.NoError(t, err)
+
+	_, err = history.Redo(configPath)
+	assert.Error(t, err)
+}
+
+func TestPruneKeepsLastN(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeConfig(t, configPath, "rules: []\n")
+
+	for i := 0; i < 5; i++ {
+		_, err := history.Record(configPath, "add_rule", nil, "", []byte("rules: []\n"), "rev", "rev", 2)
+		require.NoError(t, err)
+	}
+
+	entries, err := history.List(configPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	writeConfig(t, configPath, "rules: []\n")
+
+	entry, err := history.Record(configPath, "add_rule", nil, "", []byte("rules: []\n"), "rev1", "rev2", 0)
+	require.NoError(t, err)
+	writeConfig(t, configPath, "rules:\n  - name: r1\n")
+
+	require.NoError(t, history.RestoreSnapshot(configPath, entry.Snapshot))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "rules: []\n", string(data))
+}
+
+func TestRestoreSnapshotRejectsPathTraversal(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "ai-rulez.yaml")
+	assert.Error(t, history.RestoreSnapshot(configPath, "../../etc/passwd"))
+}
@@ -0,0 +1,415 @@
+// Package lint runs structural and content checks on a loaded
+// config.Config beyond the load/schema validation config.LoadConfig
+// already performs, in the same spirit as promtool check config for
+// Prometheus: each finding carries a stable code, a severity, and a
+// location so results can be filtered, diffed, and wired into CI.
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/generator"
+)
+
+// Severity classifies a Finding for --fail-on filtering.
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Finding is a single lint result.
+type Finding struct {
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// File is the config file the finding was raised against.
+	File string `json:"file,omitempty"`
+	// Location names the rule/section/output the finding is about, e.g.
+	// "rule:Code Quality" or "output:CLAUDE.md".
+	Location string `json:"location,omitempty"`
+}
+
+// DefaultMaxContentLength is the content-length threshold AIR005 flags
+// when Options.MaxContentLength is left at zero.
+const DefaultMaxContentLength = 4000
+
+// Options configures which thresholds Lint applies.
+type Options struct {
+	// MaxContentLength is the longest a rule/section Content may be before
+	// AIR005 flags it. Zero means DefaultMaxContentLength.
+	MaxContentLength int
+}
+
+// Lint runs every check against cfg, as loaded from configFile, and
+// returns all findings in a stable, deterministic order (errors before
+// warnings, then by code, then by location).
+func Lint(cfg *config.Config, configFile string, opts Options) []Finding {
+	maxLen := opts.MaxContentLength
+	if maxLen <= 0 {
+		maxLen = DefaultMaxContentLength
+	}
+
+	var findings []Finding
+	findings = append(findings, checkDuplicateRuleNames(cfg, configFile)...)
+	findings = append(findings, checkDuplicateSectionNames(cfg, configFile)...)
+	findings = append(findings, checkRulePriorityAndContent(cfg, configFile, maxLen)...)
+	findings = append(findings, checkSectionPriorityAndContent(cfg, configFile, maxLen)...)
+	findings = append(findings, checkDuplicateOutputFiles(cfg, configFile)...)
+	findings = append(findings, checkOutputTemplates(cfg, configFile)...)
+	findings = append(findings, checkOutputPaths(cfg, configFile)...)
+	findings = append(findings, checkOutputRefs(cfg, configFile)...)
+	findings = append(findings, checkProfiles(cfg, configFile)...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity == SeverityError
+		}
+		if findings[i].Code != findings[j].Code {
+			return findings[i].Code < findings[j].Code
+		}
+		return findings[i].Location < findings[j].Location
+	})
+	return findings
+}
+
+// namedRuleList pairs a []config.Rule with the location label it should be
+// reported under, e.g. "config" or "profile:web-app".
+type namedRuleList struct {
+	label string
+	rules []config.Rule
+}
+
+func ruleLists(cfg *config.Config) []namedRuleList {
+	lists := []namedRuleList{{label: "config", rules: cfg.Rules}}
+	for _, name := range sortedProfileNames(cfg.Profiles) {
+		lists = append(lists, namedRuleList{label: "profile:" + name, rules: cfg.Profiles[name].Rules})
+	}
+	return lists
+}
+
+type namedSectionList struct {
+	label    string
+	sections []config.Section
+}
+
+func sectionLists(cfg *config.Config) []namedSectionList {
+	lists := []namedSectionList{{label: "config", sections: cfg.Sections}}
+	for _, name := range sortedProfileNames(cfg.Profiles) {
+		lists = append(lists, namedSectionList{label: "profile:" + name, sections: cfg.Profiles[name].Sections})
+	}
+	return lists
+}
+
+func sortedProfileNames(profiles map[string]config.Profile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkDuplicateRuleNames is AIR001: a rule name (case-insensitive)
+// repeated within the same rule list - the base config or a single
+// profile's overlay - almost always indicates a copy-paste mistake, since
+// the later entry silently shadows the earlier one at merge time.
+func checkDuplicateRuleNames(cfg *config.Config, configFile string) []Finding {
+	var findings []Finding
+	for _, list := range ruleLists(cfg) {
+		seen := map[string]bool{}
+		for _, rule := range list.rules {
+			key := strings.ToLower(rule.Name)
+			if key == "" {
+				continue
+			}
+			if seen[key] {
+				findings = append(findings, Finding{
+					Code:     "AIR001",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("duplicate rule name %q in %s", rule.Name, list.label),
+					File:     configFile,
+					Location: "rule:" + rule.Name,
+				})
+			}
+			seen[key] = true
+		}
+	}
+	return findings
+}
+
+// checkDuplicateSectionNames is AIR002, the Section equivalent of AIR001.
+func checkDuplicateSectionNames(cfg *config.Config, configFile string) []Finding {
+	var findings []Finding
+	for _, list := range sectionLists(cfg) {
+		seen := map[string]bool{}
+		for _, section := range list.sections {
+			key := strings.ToLower(section.Title)
+			if key == "" {
+				continue
+			}
+			if seen[key] {
+				findings = append(findings, Finding{
+					Code:     "AIR002",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("duplicate section title %q in %s", section.Title, list.label),
+					File:     configFile,
+					Location: "section:" + section.Title,
+				})
+			}
+			seen[key] = true
+		}
+	}
+	return findings
+}
+
+// checkRulePriorityAndContent covers AIR003 (priority outside 1-10),
+// AIR004 (empty content) and AIR005 (content over maxLen) for every rule
+// in the base config and every profile overlay.
+func checkRulePriorityAndContent(cfg *config.Config, configFile string, maxLen int) []Finding {
+	var findings []Finding
+	for _, list := range ruleLists(cfg) {
+		for _, rule := range list.rules {
+			loc := "rule:" + rule.Name
+			if rule.Priority != 0 && (rule.Priority < 1 || rule.Priority > 10) {
+				findings = append(findings, Finding{
+					Code:     "AIR003",
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("priority %d for rule %q is outside the conventional 1-10 range", rule.Priority, rule.Name),
+					File:     configFile,
+					Location: loc,
+				})
+			}
+			if strings.TrimSpace(rule.Content) == "" {
+				findings = append(findings, Finding{
+					Code:     "AIR004",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("rule %q has empty content", rule.Name),
+					File:     configFile,
+					Location: loc,
+				})
+			} else if len(rule.Content) > maxLen {
+				findings = append(findings, Finding{
+					Code:     "AIR005",
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("rule %q content is %d characters, over the %d-character limit", rule.Name, len(rule.Content), maxLen),
+					File:     configFile,
+					Location: loc,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkSectionPriorityAndContent is the Section equivalent of
+// checkRulePriorityAndContent.
+func checkSectionPriorityAndContent(cfg *config.Config, configFile string, maxLen int) []Finding {
+	var findings []Finding
+	for _, list := range sectionLists(cfg) {
+		for _, section := range list.sections {
+			loc := "section:" + section.Title
+			if section.Priority != 0 && (section.Priority < 1 || section.Priority > 10) {
+				findings = append(findings, Finding{
+					Code:     "AIR003",
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("priority %d for section %q is outside the conventional 1-10 range", section.Priority, section.Title),
+					File:     configFile,
+					Location: loc,
+				})
+			}
+			if strings.TrimSpace(section.Content) == "" {
+				findings = append(findings, Finding{
+					Code:     "AIR004",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("section %q has empty content", section.Title),
+					File:     configFile,
+					Location: loc,
+				})
+			} else if len(section.Content) > maxLen {
+				findings = append(findings, Finding{
+					Code:     "AIR005",
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("section %q content is %d characters, over the %d-character limit", section.Title, len(section.Content), maxLen),
+					File:     configFile,
+					Location: loc,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkDuplicateOutputFiles is AIR006: two outputs whose File normalizes
+// (via filepath.Clean) to the same path will overwrite each other on
+// generate, usually because one was entered with a redundant "./" or a
+// different path separator.
+func checkDuplicateOutputFiles(cfg *config.Config, configFile string) []Finding {
+	var findings []Finding
+	seen := map[string]string{}
+	for _, output := range cfg.Outputs {
+		clean := filepath.Clean(output.File)
+		if prior, ok := seen[clean]; ok {
+			findings = append(findings, Finding{
+				Code:     "AIR006",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("output %q normalizes to the same path as %q", output.File, prior),
+				File:     configFile,
+				Location: "output:" + output.File,
+			})
+			continue
+		}
+		seen[clean] = output.File
+	}
+	return findings
+}
+
+// checkOutputTemplates is AIR007 (a "@file" template that doesn't exist)
+// and AIR008 (any other failure to render the output, e.g. a parse error or
+// a template referencing a field the render data doesn't have), reusing
+// generator.PreviewOutput so the same rendering path `generate` and `diff`
+// use decides what counts as an error.
+func checkOutputTemplates(cfg *config.Config, configFile string) []Finding {
+	var findings []Finding
+	gen := generator.NewWithConfigFile(configFile)
+
+	for _, output := range cfg.Outputs {
+		loc := "output:" + output.File
+		if _, err := gen.PreviewOutput(cfg, output.File); err != nil {
+			msg := err.Error()
+			if strings.Contains(msg, "failed to read template file") {
+				findings = append(findings, Finding{
+					Code:     "AIR007",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("output %q references a template that can't be read: %v", output.File, err),
+					File:     configFile,
+					Location: loc,
+				})
+				continue
+			}
+			findings = append(findings, Finding{
+				Code:     "AIR008",
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("output %q template failed to render: %v", output.File, err),
+				File:     configFile,
+				Location: loc,
+			})
+		}
+	}
+	return findings
+}
+
+// checkOutputPaths is AIR010: an output File that escapes the directory
+// ai-rulez generates into (an absolute path, or a relative path that
+// climbs above it via "..") would let a config write anywhere on disk,
+// so it's always an error rather than a style warning.
+func checkOutputPaths(cfg *config.Config, configFile string) []Finding {
+	var findings []Finding
+	for _, output := range cfg.Outputs {
+		if output.File == "" {
+			continue
+		}
+		if filepath.IsAbs(output.File) || strings.HasPrefix(filepath.ToSlash(filepath.Clean(output.File)), "../") || filepath.Clean(output.File) == ".." {
+			findings = append(findings, Finding{
+				Code:     "AIR010",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("output %q must be a relative path inside the project, not an absolute path or one that escapes it via \"..\"", output.File),
+				File:     configFile,
+				Location: "output:" + output.File,
+			})
+		}
+	}
+	return findings
+}
+
+// checkOutputRefs is AIR011: an output's Rules or Sections allow-list (see
+// templates.FilterForOutput) naming an ID/Name that matches nothing always
+// silently renders that output with less content than intended.
+func checkOutputRefs(cfg *config.Config, configFile string) []Finding {
+	var findings []Finding
+	ruleNames := map[string]bool{}
+	for _, rule := range cfg.Rules {
+		ruleNames[rule.Name] = true
+		if rule.ID != "" {
+			ruleNames[rule.ID] = true
+		}
+	}
+	sectionNames := map[string]bool{}
+	for _, section := range cfg.Sections {
+		sectionNames[section.Title] = true
+		if section.ID != "" {
+			sectionNames[section.ID] = true
+		}
+	}
+
+	for _, output := range cfg.Outputs {
+		loc := "output:" + output.File
+		for _, ref := range output.Rules {
+			if !ruleNames[ref] {
+				findings = append(findings, Finding{
+					Code:     "AIR011",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("output %q references rule %q, which doesn't match any rule's id or name", output.File, ref),
+					File:     configFile,
+					Location: loc,
+				})
+			}
+		}
+		for _, ref := range output.Sections {
+			if !sectionNames[ref] {
+				findings = append(findings, Finding{
+					Code:     "AIR011",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("output %q references section %q, which doesn't match any section's id or title", output.File, ref),
+					File:     configFile,
+					Location: loc,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkProfiles is AIR009: a profile whose Extends chain loops back on
+// itself can never be resolved by LoadConfigWithProfile.
+func checkProfiles(cfg *config.Config, configFile string) []Finding {
+	var findings []Finding
+	for _, name := range sortedProfileNames(cfg.Profiles) {
+		if err := checkProfileChain(cfg.Profiles, name); err != nil {
+			findings = append(findings, Finding{
+				Code:     "AIR009",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("profile %q can't be loaded: %v", name, err),
+				File:     configFile,
+				Location: "profile:" + name,
+			})
+		}
+	}
+	return findings
+}
+
+// checkProfileChain walks name's Extends links the same way
+// config.resolveProfileChain does, without requiring access to that
+// unexported helper, reporting a circular chain as an error.
+func checkProfileChain(profiles map[string]config.Profile, name string) error {
+	visited := map[string]bool{}
+	current := name
+	for current != "" {
+		if visited[current] {
+			return fmt.Errorf("circular extends chain detected at %q", current)
+		}
+		visited[current] = true
+
+		profile, ok := profiles[current]
+		if !ok {
+			return nil
+		}
+		current = profile.Extends
+	}
+	return nil
+}
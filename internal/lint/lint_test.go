@@ -0,0 +1,114 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/lint"
+)
+
+func codes(findings []lint.Finding) []string {
+	codes := make([]string, len(findings))
+	for i, f := range findings {
+		codes[i] = f.Code
+	}
+	return codes
+}
+
+func TestLintFindsDuplicateAndOutOfRangeIssues(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs: []config.Output{
+			{File: "CLAUDE.md"},
+			{File: "./CLAUDE.md"},
+		},
+		Rules: []config.Rule{
+			{Name: "Code Quality", Content: "be clean", Priority: 1},
+			{Name: "code quality", Content: "be clean again", Priority: 20},
+			{Name: "Empty", Content: "   "},
+		},
+		Sections: []config.Section{
+			{Title: "Overview", Content: "intro"},
+			{Title: "Overview", Content: "dup"},
+		},
+	}
+
+	findings := lint.Lint(cfg, "ai_rulez.yaml", lint.Options{})
+
+	assert.Contains(t, codes(findings), "AIR001")
+	assert.Contains(t, codes(findings), "AIR002")
+	assert.Contains(t, codes(findings), "AIR003")
+	assert.Contains(t, codes(findings), "AIR004")
+	assert.Contains(t, codes(findings), "AIR006")
+}
+
+func TestLintContentLengthThreshold(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Long", Content: "0123456789"}},
+	}
+
+	none := lint.Lint(cfg, "ai_rulez.yaml", lint.Options{MaxContentLength: 20})
+	assert.NotContains(t, codes(none), "AIR005")
+
+	tooLong := lint.Lint(cfg, "ai_rulez.yaml", lint.Options{MaxContentLength: 5})
+	assert.Contains(t, codes(tooLong), "AIR005")
+}
+
+func TestLintFindsMissingTemplateFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "ai_rulez.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("placeholder"), 0o644))
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs:  []config.Output{{File: "CLAUDE.md", Template: "@missing.tmpl"}},
+		Rules:    []config.Rule{{Name: "Rule", Content: "content"}},
+	}
+
+	findings := lint.Lint(cfg, configFile, lint.Options{})
+	assert.Contains(t, codes(findings), "AIR007")
+}
+
+func TestLintFindsCircularProfileExtends(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{Name: "Rule", Content: "content"}},
+		Profiles: map[string]config.Profile{
+			"a": {Extends: "b"},
+			"b": {Extends: "a"},
+		},
+	}
+
+	findings := lint.Lint(cfg, "ai_rulez.yaml", lint.Options{})
+	assert.Contains(t, codes(findings), "AIR009")
+}
+
+func TestLintFindsUnsafeOutputPath(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs:  []config.Output{{File: "../outside.md"}},
+		Rules:    []config.Rule{{Name: "Rule", Content: "content"}},
+	}
+
+	findings := lint.Lint(cfg, "ai_rulez.yaml", lint.Options{})
+	assert.Contains(t, codes(findings), "AIR010")
+}
+
+func TestLintFindsUnknownOutputRef(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test"},
+		Outputs:  []config.Output{{File: "CLAUDE.md", Rules: []string{"nonexistent"}}},
+		Rules:    []config.Rule{{Name: "Rule", Content: "content"}},
+	}
+
+	findings := lint.Lint(cfg, "ai_rulez.yaml", lint.Options{})
+	assert.Contains(t, codes(findings), "AIR011")
+}
@@ -0,0 +1,206 @@
+package modules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// CacheDirName is the directory under the user cache root that holds
+// resolved remote include sources, content-addressed by their Source.Raw.
+const CacheDirName = "ai-rulez/modules"
+
+// DefaultCacheDir returns "~/.cache/ai-rulez/modules" (or the platform
+// equivalent), matching the location Terraform-style module caches use.
+func DefaultCacheDir() (string, error) {
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(userCache, CacheDirName), nil
+}
+
+// Fetcher fetches a remote Source's content to the local filesystem. The
+// zero-value Resolver uses execFetcher, which shells out to git and issues
+// a plain HTTP GET; tests inject a fake Fetcher to stub git fetches without
+// a network or git binary.
+type Fetcher interface {
+	FetchGit(src *Source, destDir string) error
+	FetchHTTP(src *Source, destFile string) error
+}
+
+// execFetcher is the default Fetcher: git clone for KindGit, http.Get for KindHTTP.
+type execFetcher struct{}
+
+// Resolver fetches remote Sources into a local content-addressed cache.
+type Resolver struct {
+	CacheDir string
+	// Offline restricts resolution to whatever is already cached.
+	Offline bool
+	// Fetcher performs the actual fetch; nil uses execFetcher.
+	Fetcher Fetcher
+}
+
+// NewResolver creates a Resolver backed by DefaultCacheDir.
+func NewResolver() (*Resolver, error) {
+	cacheDir, err := DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{CacheDir: cacheDir}, nil
+}
+
+// fetcher returns r.Fetcher, defaulting to execFetcher.
+func (r *Resolver) fetcher() Fetcher {
+	if r.Fetcher == nil {
+		return execFetcher{}
+	}
+	return r.Fetcher
+}
+
+// CacheKey returns the content-addressed directory name for a source,
+// keyed by its raw reference so a pinned ref always resolves to the same slot.
+func CacheKey(src *Source) string {
+	hash := sha256.Sum256([]byte(src.Raw))
+	return hex.EncodeToString(hash[:])
+}
+
+// cacheFileName returns the filename Resolve should use inside a source's
+// cache slot: the base name of its Subpath when set, or otherwise the base
+// name of the Repo URL itself (the plain "https://host/rules.yaml" form,
+// which has no subpath to split out). Falls back to "content" if that's
+// still empty, e.g. a bare "https://host/" with a trailing slash.
+func cacheFileName(src *Source) string {
+	if src.Subpath != "" {
+		return filepath.Base(src.Subpath)
+	}
+	if base := filepath.Base(src.Repo); base != "" && base != "." && base != "/" {
+		return base
+	}
+	return "content"
+}
+
+// Resolve fetches src (if not already cached) and returns the local path to
+// the included file, along with the SHA256 of its contents for lockfile pinning.
+func (r *Resolver) Resolve(src *Source) (path, hash string, err error) {
+	key := CacheKey(src)
+	destDir := filepath.Join(r.CacheDir, key)
+	destFile := filepath.Join(destDir, cacheFileName(src))
+
+	if _, statErr := os.Stat(destFile); statErr == nil {
+		sum, hashErr := hashFile(destFile)
+		if hashErr != nil {
+			return "", "", hashErr
+		}
+		return destFile, sum, nil
+	} else if !os.IsNotExist(statErr) {
+		return "", "", fmt.Errorf("failed to stat cached module %s: %w", destFile, statErr)
+	}
+
+	if r.Offline {
+		return "", "", fmt.Errorf("module %s is not cached and offline mode is enabled: %w", src.Raw, ErrNotCached)
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create module cache dir %s: %w", destDir, err)
+	}
+
+	switch src.Kind {
+	case KindGit:
+		err = r.fetcher().FetchGit(src, destDir)
+	case KindHTTP:
+		err = r.fetcher().FetchHTTP(src, destFile)
+	default:
+		err = fmt.Errorf("unsupported module source kind: %s", src.Kind)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	sum, err := hashFile(destFile)
+	if err != nil {
+		return "", "", err
+	}
+
+	return destFile, sum, nil
+}
+
+// FetchGit clones src.Repo at src.Ref (default HEAD) into destDir.
+func (execFetcher) FetchGit(src *Source, destDir string) error {
+	args := []string{"clone", "--depth", "1"}
+	if src.Ref != "" {
+		args = append(args, "--branch", src.Ref)
+	}
+	args = append(args, src.Repo, destDir)
+
+	cmd := exec.Command("git", args...) //nolint:gosec // source is author-controlled config
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone %s failed: %w: %s", src.Repo, err, out)
+	}
+	return nil
+}
+
+// FetchHTTP downloads src.Repo (joined with Subpath, if set) to destFile.
+func (execFetcher) FetchHTTP(src *Source, destFile string) error {
+	target := src.Repo
+	if src.Subpath != "" {
+		target = src.Repo + "/" + src.Subpath
+	}
+
+	resp, err := http.Get(target) //nolint:gosec // source is author-controlled config
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", target, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s: HTTP %d", target, resp.StatusCode)
+	}
+
+	out, err := os.Create(destFile) //nolint:gosec // destFile is within the module cache
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", destFile, err)
+	}
+	defer func() { _ = out.Close() }()
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return fmt.Errorf("failed to write %s: %w", destFile, err)
+	}
+	return nil
+}
+
+// VerifyHash checks that the file at path matches the expected SHA256 hash.
+func VerifyHash(path, expectedHash string) error {
+	actual, err := hashFile(path)
+	if err != nil {
+		return err
+	}
+	if actual != expectedHash {
+		return fmt.Errorf("integrity check failed for %s: expected %s, got %s", path, expectedHash, actual)
+	}
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s for hashing: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ErrNotCached is returned by Resolve in offline mode when a source has
+// never been fetched before.
+var ErrNotCached = errors.New("module not cached")
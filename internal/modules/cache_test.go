@@ -0,0 +1,101 @@
+package modules_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/modules"
+)
+
+func TestResolverFetchesOverHTTP(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("rules: from the server\n"))
+	}))
+	defer server.Close()
+
+	resolver := &modules.Resolver{CacheDir: t.TempDir()}
+	src, err := modules.ParseSource(server.URL + "/rules.yaml")
+	require.NoError(t, err)
+
+	path, hash, err := resolver.Resolve(src)
+	require.NoError(t, err)
+	assert.NotEmpty(t, hash)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "rules: from the server\n", string(data))
+
+	// A second resolve hits the cache, not the server.
+	server.Close()
+	path2, hash2, err := resolver.Resolve(src)
+	require.NoError(t, err)
+	assert.Equal(t, path, path2)
+	assert.Equal(t, hash, hash2)
+}
+
+// fakeFetcher stubs git fetches by writing fixed content directly into
+// destDir, so git-source tests don't need a network or the git binary.
+type fakeFetcher struct {
+	content string
+}
+
+func (f fakeFetcher) FetchGit(src *modules.Source, destDir string) error {
+	return os.WriteFile(filepath.Join(destDir, filepath.Base(src.Subpath)), []byte(f.content), 0o644)
+}
+
+func (fakeFetcher) FetchHTTP(_ *modules.Source, _ string) error {
+	return nil
+}
+
+func TestResolverFetchesGitWithFakeFetcher(t *testing.T) {
+	t.Parallel()
+
+	resolver := &modules.Resolver{
+		CacheDir: t.TempDir(),
+		Fetcher:  fakeFetcher{content: "rules: from git\n"},
+	}
+	src, err := modules.ParseSource("git+https://example.com/org/repo@v1.0.0#path=rules.yaml")
+	require.NoError(t, err)
+
+	path, hash, err := resolver.Resolve(src)
+	require.NoError(t, err)
+	assert.NotEmpty(t, hash)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "rules: from git\n", string(data))
+}
+
+func TestLockfileVerifyDetectsTamperedContent(t *testing.T) {
+	t.Parallel()
+
+	src, err := modules.ParseSource("https://example.com/rules.yaml")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("original\n"), 0o644))
+
+	lf := &modules.Lockfile{Modules: make(map[string]modules.LockEntry)}
+	lf.Pin(src, "", sha256Hex(t, "original\n"))
+	require.NoError(t, lf.Verify(src, path))
+
+	require.NoError(t, os.WriteFile(path, []byte("tampered\n"), 0o644))
+	assert.Error(t, lf.Verify(src, path))
+}
+
+func sha256Hex(t *testing.T, content string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package modules
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LockfileName is the file that pins resolved remote includes so teammates
+// and CI get reproducible rule sets, analogous to a package manager lockfile.
+const LockfileName = "ai-rulez.lock"
+
+// LockEntry pins a single remote include to the commit/content it resolved to.
+type LockEntry struct {
+	Source   string `yaml:"source"`
+	Resolved string `yaml:"resolved,omitempty"` // resolved ref/commit, when known
+	Hash     string `yaml:"hash"`               // SHA256 of the fetched content
+}
+
+// Lockfile pins every remote include used by a config, keyed by its raw source string.
+type Lockfile struct {
+	Modules map[string]LockEntry `yaml:"modules"`
+}
+
+// LoadLockfile reads a lockfile from path. A missing file returns an empty Lockfile.
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Lockfile{Modules: make(map[string]LockEntry)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile %s: %w", path, err)
+	}
+
+	var lf Lockfile
+	if err := yaml.Unmarshal(data, &lf); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if lf.Modules == nil {
+		lf.Modules = make(map[string]LockEntry)
+	}
+	return &lf, nil
+}
+
+// SaveLockfile writes the lockfile to path.
+func SaveLockfile(lf *Lockfile, path string) error {
+	data, err := yaml.Marshal(lf)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write lockfile %s: %w", path, err)
+	}
+	return nil
+}
+
+// Pin records (or updates) the resolved hash for a source in the lockfile.
+func (lf *Lockfile) Pin(src *Source, resolved, hash string) {
+	if lf.Modules == nil {
+		lf.Modules = make(map[string]LockEntry)
+	}
+	lf.Modules[src.Raw] = LockEntry{Source: src.Raw, Resolved: resolved, Hash: hash}
+}
+
+// Verify checks the fetched content at path against the pinned hash for src,
+// if one is recorded. A source with no pinned entry passes verification.
+func (lf *Lockfile) Verify(src *Source, path string) error {
+	entry, pinned := lf.Modules[src.Raw]
+	if !pinned {
+		return nil
+	}
+	return VerifyHash(path, entry.Hash)
+}
@@ -0,0 +1,138 @@
+// Package modules resolves remote (git/HTTP) include sources into local
+// paths the config loader can read, caching fetched content under a
+// content-addressed directory similar to Terraform's module cache.
+package modules
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Kind identifies the transport used to fetch a Source.
+type Kind string
+
+const (
+	// KindGit fetches a source via `git clone` (optionally pinned to a ref).
+	KindGit Kind = "git"
+	// KindHTTP fetches a source with a plain HTTP(S) GET.
+	KindHTTP Kind = "http"
+)
+
+// Source is a parsed remote include reference, e.g.
+// "git::https://github.com/org/repo//path/rules.yaml?ref=v1.2.0".
+type Source struct {
+	Raw     string // the original include string
+	Kind    Kind
+	Repo    string // the repository/base URL, without the subpath or ref query
+	Subpath string // path within the repo to the file to include, e.g. "path/rules.yaml"
+	Ref     string // git ref (tag, branch, or commit) pinned via ?ref=
+	Version string // semver constraint pinned via ?version=
+}
+
+// IsRemoteSource reports whether raw looks like a remote include reference
+// rather than a path relative to the config file.
+func IsRemoteSource(raw string) bool {
+	return strings.HasPrefix(raw, "git::") || strings.HasPrefix(raw, "git+") ||
+		strings.HasPrefix(raw, "http://") || strings.HasPrefix(raw, "https://")
+}
+
+// ParseSource parses a remote include string into its components. Two
+// forms are accepted:
+//
+//	git::https://github.com/org/repo//path/rules.yaml?ref=v1.2.0
+//	git+https://github.com/org/repo@v1.2.0#path=path/rules.yaml
+//
+// and a plain "https://host/file.yaml" HTTP(S) URL.
+func ParseSource(raw string) (*Source, error) {
+	if !IsRemoteSource(raw) {
+		return nil, fmt.Errorf("not a remote source: %s", raw)
+	}
+
+	if strings.HasPrefix(raw, "git+") {
+		return parseGitPlusSource(raw)
+	}
+
+	kind := KindHTTP
+	rest := raw
+	if strings.HasPrefix(raw, "git::") {
+		kind = KindGit
+		rest = strings.TrimPrefix(raw, "git::")
+	}
+
+	// Split off the in-repo subpath, marked by a double slash: repo//path/to/file.
+	repo := rest
+	subpath := ""
+	if idx := strings.Index(rest, "//"); idx != -1 {
+		// Skip the scheme's own "//" (e.g. "https://") when locating the split.
+		schemeEnd := strings.Index(rest, "://")
+		searchFrom := 0
+		if schemeEnd != -1 {
+			searchFrom = schemeEnd + len("://")
+		}
+		if splitIdx := strings.Index(rest[searchFrom:], "//"); splitIdx != -1 {
+			absoluteIdx := searchFrom + splitIdx
+			repo = rest[:absoluteIdx]
+			subpath = rest[absoluteIdx+2:]
+		}
+	}
+
+	parsed, err := url.Parse(repo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source URL %s: %w", raw, err)
+	}
+
+	ref := parsed.Query().Get("ref")
+	version := parsed.Query().Get("version")
+
+	// Subpath may itself carry the query string if there was no repo-level query.
+	if idx := strings.Index(subpath, "?"); idx != -1 {
+		query, err := url.ParseQuery(subpath[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid query in source %s: %w", raw, err)
+		}
+		if ref == "" {
+			ref = query.Get("ref")
+		}
+		if version == "" {
+			version = query.Get("version")
+		}
+		subpath = subpath[:idx]
+	}
+
+	parsed.RawQuery = ""
+
+	return &Source{
+		Raw:     raw,
+		Kind:    kind,
+		Repo:    parsed.String(),
+		Subpath: subpath,
+		Ref:     ref,
+		Version: version,
+	}, nil
+}
+
+// parseGitPlusSource parses the "git+https://repo@ref#path=sub/file.yaml"
+// form: a git repo URL, an optional "@ref" pin, and a mandatory
+// "#path=..." fragment naming the file within the repo to include.
+func parseGitPlusSource(raw string) (*Source, error) {
+	rest := strings.TrimPrefix(raw, "git+")
+
+	repoAndRef, subpath, found := strings.Cut(rest, "#path=")
+	if !found || subpath == "" {
+		return nil, fmt.Errorf("git+ source %s is missing a #path=<file> fragment", raw)
+	}
+
+	repo := repoAndRef
+	ref := ""
+	schemeEnd := strings.Index(repoAndRef, "://")
+	if schemeEnd != -1 {
+		if idx := strings.LastIndex(repoAndRef[schemeEnd+len("://"):], "@"); idx != -1 {
+			absoluteIdx := schemeEnd + len("://") + idx
+			repo = repoAndRef[:absoluteIdx]
+			ref = repoAndRef[absoluteIdx+1:]
+		}
+	}
+
+	return &Source{Raw: raw, Kind: KindGit, Repo: repo, Subpath: subpath, Ref: ref}, nil
+}
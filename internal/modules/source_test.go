@@ -0,0 +1,90 @@
+package modules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/modules"
+)
+
+func TestParseSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		raw         string
+		wantKind    modules.Kind
+		wantRepo    string
+		wantSubpath string
+		wantRef     string
+	}{
+		{
+			name:        "git source with subpath and ref",
+			raw:         "git::https://github.com/org/repo//path/rules.yaml?ref=v1.2.0",
+			wantKind:    modules.KindGit,
+			wantRepo:    "https://github.com/org/repo",
+			wantSubpath: "path/rules.yaml",
+			wantRef:     "v1.2.0",
+		},
+		{
+			name:     "plain https source",
+			raw:      "https://example.com/rules.yaml",
+			wantKind: modules.KindHTTP,
+			wantRepo: "https://example.com/rules.yaml",
+		},
+		{
+			name:        "git+https source with ref and path fragment",
+			raw:         "git+https://github.com/org/repo@v1.2.0#path=path/rules.yaml",
+			wantKind:    modules.KindGit,
+			wantRepo:    "https://github.com/org/repo",
+			wantSubpath: "path/rules.yaml",
+			wantRef:     "v1.2.0",
+		},
+		{
+			name:        "git+https source without a ref",
+			raw:         "git+https://github.com/org/repo#path=rules.yaml",
+			wantKind:    modules.KindGit,
+			wantRepo:    "https://github.com/org/repo",
+			wantSubpath: "rules.yaml",
+			wantRef:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src, err := modules.ParseSource(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKind, src.Kind)
+			assert.Equal(t, tt.wantRepo, src.Repo)
+			assert.Equal(t, tt.wantSubpath, src.Subpath)
+			assert.Equal(t, tt.wantRef, src.Ref)
+		})
+	}
+}
+
+func TestIsRemoteSource(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, modules.IsRemoteSource("git::https://github.com/org/repo.git"))
+	assert.True(t, modules.IsRemoteSource("git+https://github.com/org/repo@main#path=rules.yaml"))
+	assert.True(t, modules.IsRemoteSource("https://example.com/rules.yaml"))
+	assert.False(t, modules.IsRemoteSource("../shared/rules.yaml"))
+}
+
+func TestParseSourceRejectsLocalPath(t *testing.T) {
+	t.Parallel()
+
+	_, err := modules.ParseSource("./rules.yaml")
+	assert.Error(t, err)
+}
+
+func TestParseSourceRejectsGitPlusWithoutPathFragment(t *testing.T) {
+	t.Parallel()
+
+	_, err := modules.ParseSource("git+https://github.com/org/repo@main")
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+// Package output provides pluggable result rendering for CLI commands and
+// MCP handlers: the same value can be printed as text, JSON, YAML, or a
+// table, selected by a single --output/-o flag (or an MCP "format"
+// argument) instead of each command hand-rolling fmt.Printf/json.MarshalIndent.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is one of the rendering modes a Printer supports.
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	Table Format = "table"
+)
+
+// Tabular is implemented by values that know how to lay themselves out as
+// a table. Table falls back to Text rendering for a value that doesn't
+// implement it.
+type Tabular interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// Printer renders a value to w.
+type Printer interface {
+	Print(w io.Writer, v any) error
+}
+
+// New returns the Printer for format ("text", "json", "yaml", "table"; ""
+// defaults to "text").
+func New(format string) (Printer, error) {
+	switch Format(format) {
+	case "", Text:
+		return textPrinter{}, nil
+	case JSON:
+		return jsonPrinter{}, nil
+	case YAML:
+		return yamlPrinter{}, nil
+	case Table:
+		return tablePrinter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q: expected text, json, yaml, or table", format)
+	}
+}
+
+// textPrinter prints v.String() when v implements fmt.Stringer, else v's
+// default Go formatting.
+type textPrinter struct{}
+
+func (textPrinter) Print(w io.Writer, v any) error {
+	if s, ok := v.(fmt.Stringer); ok {
+		_, err := fmt.Fprintln(w, s.String())
+		return err
+	}
+	_, err := fmt.Fprintln(w, v)
+	return err
+}
+
+type jsonPrinter struct{}
+
+func (jsonPrinter) Print(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+type yamlPrinter struct{}
+
+func (yamlPrinter) Print(w io.Writer, v any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// tablePrinter renders Tabular values as a tab-aligned table; anything
+// else falls back to textPrinter.
+type tablePrinter struct{}
+
+func (tablePrinter) Print(w io.Writer, v any) error {
+	t, ok := v.(Tabular)
+	if !ok {
+		return textPrinter{}.Print(w, v)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.Header(), "\t"))
+	for _, row := range t.Rows() {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,77 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/output"
+)
+
+type fruitList []string
+
+func (f fruitList) String() string { return "fruits: " + strings.Join(f, ", ") }
+
+func (f fruitList) Header() []string { return []string{"NAME"} }
+
+func (f fruitList) Rows() [][]string {
+	rows := make([][]string, len(f))
+	for i, name := range f {
+		rows[i] = []string{name}
+	}
+	return rows
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	_, err := output.New("xml")
+	assert.Error(t, err)
+}
+
+func TestTextPrinterUsesStringer(t *testing.T) {
+	p, err := output.New("text")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Print(&buf, fruitList{"apple", "pear"}))
+	assert.Equal(t, "fruits: apple, pear\n", buf.String())
+}
+
+func TestJSONPrinter(t *testing.T) {
+	p, err := output.New("json")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Print(&buf, fruitList{"apple", "pear"}))
+	assert.Contains(t, buf.String(), `"apple"`)
+}
+
+func TestYAMLPrinter(t *testing.T) {
+	p, err := output.New("yaml")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Print(&buf, fruitList{"apple", "pear"}))
+	assert.Contains(t, buf.String(), "- apple")
+}
+
+func TestTablePrinter(t *testing.T) {
+	p, err := output.New("table")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Print(&buf, fruitList{"apple", "pear"}))
+	assert.Contains(t, buf.String(), "NAME")
+	assert.Contains(t, buf.String(), "apple")
+}
+
+func TestTablePrinterFallsBackToTextForNonTabular(t *testing.T) {
+	p, err := output.New("table")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Print(&buf, 42))
+	assert.Equal(t, "42\n", buf.String())
+}
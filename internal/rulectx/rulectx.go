@@ -0,0 +1,148 @@
+// Package rulectx manages named contexts, each mapping a short name to a
+// config file, default profile set, and optional output base directory, so
+// a user can switch between workspaces without repeatedly passing --config.
+// The store lives at $XDG_CONFIG_HOME/ai-rulez/contexts.yaml (or the
+// platform config directory equivalent), mirroring how internal/scaffold
+// locates user-installed templates.
+package rulectx
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Context is one named entry in the store: where its config file lives,
+// which profiles it defaults to, and where generated output should be
+// rooted if that differs from the config file's directory.
+type Context struct {
+	ConfigFile string   `yaml:"config_file"`
+	Profiles   []string `yaml:"profiles,omitempty"`
+	OutputDir  string   `yaml:"output_dir,omitempty"`
+}
+
+// Store is the on-disk contexts.yaml: every known context by name, plus
+// which one is active.
+type Store struct {
+	Current  string              `yaml:"current,omitempty"`
+	Contexts map[string]*Context `yaml:"contexts,omitempty"`
+}
+
+// StorePath returns the path to the user-level contexts.yaml file.
+func StorePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "ai-rulez", "contexts.yaml"), nil
+}
+
+// Load reads the store from StorePath, returning an empty Store if the file
+// doesn't exist yet.
+func Load() (*Store, error) {
+	path, err := StorePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Store{Contexts: map[string]*Context{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var s Store
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if s.Contexts == nil {
+		s.Contexts = map[string]*Context{}
+	}
+	return &s, nil
+}
+
+// Save writes the store to StorePath, creating its parent directory if
+// needed.
+func (s *Store) Save() error {
+	path, err := StorePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contexts: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Get returns the named context, or an error if it isn't defined.
+func (s *Store) Get(name string) (*Context, error) {
+	ctx, ok := s.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q is not defined", name)
+	}
+	return ctx, nil
+}
+
+// Add defines or replaces a named context.
+func (s *Store) Add(name string, ctx *Context) {
+	if s.Contexts == nil {
+		s.Contexts = map[string]*Context{}
+	}
+	s.Contexts[name] = ctx
+}
+
+// Remove deletes a named context, clearing Current if it was active.
+func (s *Store) Remove(name string) error {
+	if _, ok := s.Contexts[name]; !ok {
+		return fmt.Errorf("context %q is not defined", name)
+	}
+	delete(s.Contexts, name)
+	if s.Current == name {
+		s.Current = ""
+	}
+	return nil
+}
+
+// Use marks name as the active context. name must already be defined.
+func (s *Store) Use(name string) error {
+	if _, ok := s.Contexts[name]; !ok {
+		return fmt.Errorf("context %q is not defined", name)
+	}
+	s.Current = name
+	return nil
+}
+
+// Active loads the store and returns its current context, or nil if no
+// context is active (not an error - callers should fall back to
+// config.FindConfigFile).
+func Active() (*Context, error) {
+	s, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if s.Current == "" {
+		return nil, nil
+	}
+	return s.Get(s.Current)
+}
+
+// Resolve loads the store and returns the named context.
+func Resolve(name string) (*Context, error) {
+	s, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(name)
+}
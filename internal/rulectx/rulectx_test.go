@@ -0,0 +1,62 @@
+package rulectx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/rulectx"
+)
+
+func TestAddUseRemove(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	s, err := rulectx.Load()
+	require.NoError(t, err)
+	require.Empty(t, s.Contexts)
+
+	s.Add("work", &rulectx.Context{ConfigFile: "/work/ai-rulez.yaml", Profiles: []string{"api"}})
+	require.NoError(t, s.Use("work"))
+	require.NoError(t, s.Save())
+
+	reloaded, err := rulectx.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "work", reloaded.Current)
+	ctx, err := reloaded.Get("work")
+	require.NoError(t, err)
+	assert.Equal(t, "/work/ai-rulez.yaml", ctx.ConfigFile)
+
+	active, err := rulectx.Active()
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, "/work/ai-rulez.yaml", active.ConfigFile)
+
+	require.NoError(t, reloaded.Remove("work"))
+	assert.Empty(t, reloaded.Current)
+	require.NoError(t, reloaded.Save())
+
+	afterRemove, err := rulectx.Load()
+	require.NoError(t, err)
+	active, err = rulectx.Active()
+	require.NoError(t, err)
+	assert.Nil(t, active)
+	_, err = afterRemove.Get("work")
+	assert.Error(t, err)
+}
+
+func TestUseUndefinedContext(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	s, err := rulectx.Load()
+	require.NoError(t, err)
+	assert.Error(t, s.Use("missing"))
+}
+
+func TestActiveWithNoContexts(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	active, err := rulectx.Active()
+	require.NoError(t, err)
+	assert.Nil(t, active)
+}
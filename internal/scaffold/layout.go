@@ -0,0 +1,223 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+// ruleDomains classifies Template rules into the bare includes
+// WriteProjectLayout splits them into, keyed by a keyword matched against
+// the rule's name (case-insensitive). The first match wins; "style" has no
+// keywords and is the catch-all for everything else.
+var ruleDomains = []struct {
+	Name    string
+	Keyword string
+}{
+	{Name: "security", Keyword: "secur"},
+	{Name: "testing", Keyword: "test"},
+	{Name: "style", Keyword: ""},
+}
+
+// ruleFragment is the shape of the bare-include files WriteProjectLayout
+// writes into layout.IncludesDir: just a rules list, the same minimal
+// fragment shape used elsewhere for includes (see internal/config's
+// includes tests).
+type ruleFragment struct {
+	Rules []config.Rule `yaml:"rules"`
+}
+
+// defaultOutputTemplate is the starter Go template WriteProjectLayout
+// writes for each targeted assistant; it matches the field names the
+// default in-binary renderer already exposes (ProjectName, Description,
+// Version, Timestamp, RuleCount, SectionCount, AllContent), so the
+// scaffolded project generates real output immediately and teams can
+// customize it from there.
+const defaultOutputTemplate = `# {{.ProjectName}}
+{{- if .Description}}
+
+{{.Description}}
+{{- end}}
+{{- if .Version}}
+
+Version: {{.Version}}
+{{- end}}
+{{- range .AllContent}}
+{{- if .IsRule}}
+
+## {{.Title}}
+
+**Priority:** {{.Priority}}
+
+{{.Content}}
+{{- else}}
+
+{{.Content}}
+{{- end}}
+{{- end}}
+`
+
+const ciWorkflow = `name: ai-rulez
+
+on:
+  pull_request:
+  push:
+    branches: [main]
+
+jobs:
+  validate:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version-file: go.mod
+      - name: Install ai-rulez
+        run: go install github.com/Goldziher/ai-rulez@latest
+      - name: Validate configuration
+        run: ai-rulez validate
+      - name: Preview generated output (dry run)
+        run: ai-rulez generate --dry-run
+`
+
+const preCommitConfig = `repos:
+  - repo: local
+    hooks:
+      - id: ai-rulez-validate
+        name: ai-rulez validate
+        entry: ai-rulez validate
+        language: system
+        pass_filenames: false
+      - id: ai-rulez-generate
+        name: ai-rulez generate --dry-run
+        entry: ai-rulez generate --dry-run
+        language: system
+        pass_filenames: false
+`
+
+// WriteProjectLayout turns cfg into a full starter layout instead of a
+// single ai_rulez.yaml: its Rules are split by domain into bare includes
+// under layout.IncludesDir (resolved the same way any other bare include
+// is, see isBareIncludeName in internal/config), each Output gets its own
+// customizable Go template file under a top-level templates/ directory, and
+// a GitHub Actions workflow plus a pre-commit config are added so the
+// generated outputs stay in sync with CI and local commits. layout.Scaffold
+// must have already created layout.IncludesDir. WriteProjectLayout returns
+// a copy of cfg with Rules/Includes/Outputs updated to point at the new
+// files; the caller still writes it out with config.SaveConfig like any
+// other init.
+func WriteProjectLayout(cfg *config.Config, layout *config.Layout) (*config.Config, error) {
+	out := *cfg
+
+	includes, err := writeRuleDomains(out.Rules, layout)
+	if err != nil {
+		return nil, err
+	}
+	out.Rules = nil
+	out.Includes = append(append([]config.IncludeEntry{}, out.Includes...), includes...)
+
+	outputs, err := writeOutputTemplates(out.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	out.Outputs = outputs
+
+	if err := os.MkdirAll(filepath.Join(".github", "workflows"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create .github/workflows: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(".github", "workflows", "ai-rulez.yml"), []byte(ciWorkflow), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write CI workflow: %w", err)
+	}
+	if err := os.WriteFile(".pre-commit-config.yaml", []byte(preCommitConfig), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write pre-commit config: %w", err)
+	}
+
+	return &out, nil
+}
+
+// writeRuleDomains groups rules by ruleDomains and writes each non-empty
+// group to layout.IncludesDir/<name>.yaml, returning the bare IncludeEntry
+// for each file written.
+func writeRuleDomains(rules []config.Rule, layout *config.Layout) ([]config.IncludeEntry, error) {
+	grouped := make(map[string][]config.Rule, len(ruleDomains))
+	for _, rule := range rules {
+		domain := classifyRule(rule.Name)
+		grouped[domain] = append(grouped[domain], rule)
+	}
+
+	var includes []config.IncludeEntry
+	for _, domain := range ruleDomains {
+		group := grouped[domain.Name]
+		if len(group) == 0 {
+			continue
+		}
+		data, err := yaml.Marshal(ruleFragment{Rules: group})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s rules: %w", domain.Name, err)
+		}
+		dest := filepath.Join(layout.IncludesDir, domain.Name+".yaml")
+		if err := os.WriteFile(dest, data, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", dest, err)
+		}
+		includes = append(includes, config.IncludeEntry{Path: domain.Name})
+	}
+	return includes, nil
+}
+
+// classifyRule returns the ruleDomains entry whose keyword matches name,
+// falling back to the last (keyword-less) entry as the catch-all.
+func classifyRule(name string) string {
+	lower := strings.ToLower(name)
+	for _, domain := range ruleDomains {
+		if domain.Keyword != "" && strings.Contains(lower, domain.Keyword) {
+			return domain.Name
+		}
+	}
+	return ruleDomains[len(ruleDomains)-1].Name
+}
+
+// writeOutputTemplates writes a defaultOutputTemplate file for each output
+// under a top-level templates/ directory and points output.Template at it,
+// returning the updated outputs slice.
+func writeOutputTemplates(outputs []config.Output) ([]config.Output, error) {
+	if len(outputs) == 0 {
+		return outputs, nil
+	}
+	if err := os.MkdirAll("templates", 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	updated := make([]config.Output, len(outputs))
+	for i, output := range outputs {
+		name := templateFileName(output.File)
+		dest := filepath.Join("templates", name)
+		if err := os.WriteFile(dest, []byte(defaultOutputTemplate), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", dest, err)
+		}
+		output.Template = "@" + dest
+		updated[i] = output
+	}
+	return updated, nil
+}
+
+// templateFileName derives a safe *.tmpl filename from an output's
+// filename, e.g. "CLAUDE.md" -> "claude.tmpl", ".cursorrules" ->
+// "cursorrules.tmpl".
+func templateFileName(outputFile string) string {
+	base := filepath.Base(outputFile)
+	// filepath.Ext treats a dotfile's own name as its "extension" (e.g.
+	// ".cursorrules"), so only strip it when there's a real name left over.
+	if ext := filepath.Ext(base); ext != "" && ext != base {
+		base = strings.TrimSuffix(base, ext)
+	}
+	base = strings.Trim(strings.ToLower(base), ".")
+	if base == "" {
+		base = "output"
+	}
+	return base + ".tmpl"
+}
@@ -0,0 +1,65 @@
+package scaffold_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/scaffold"
+)
+
+func TestWriteProjectLayoutSplitsRulesAndTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	layout := &config.Layout{
+		Root:        tmpDir,
+		ConfigDir:   filepath.Join(tmpDir, "ai_rules"),
+		IncludesDir: filepath.Join(tmpDir, "ai_rules", "includes"),
+		CacheDir:    filepath.Join(tmpDir, "ai_rules", "cache"),
+	}
+	require.NoError(t, os.MkdirAll(layout.IncludesDir, 0o755))
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test Project"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}, {File: ".cursorrules"}},
+		Rules: []config.Rule{
+			{Name: "Code Quality", Content: "write clean code"},
+			{Name: "Security Checks", Content: "no secrets in code"},
+			{Name: "Testing Standards", Content: "cover the happy path"},
+		},
+	}
+
+	out, err := scaffold.WriteProjectLayout(cfg, layout)
+	require.NoError(t, err)
+
+	assert.Empty(t, out.Rules)
+	assert.ElementsMatch(t,
+		[]config.IncludeEntry{{Path: "security"}, {Path: "testing"}, {Path: "style"}},
+		out.Includes,
+	)
+
+	for _, f := range []string{
+		filepath.Join(layout.IncludesDir, "style.yaml"),
+		filepath.Join(layout.IncludesDir, "security.yaml"),
+		filepath.Join(layout.IncludesDir, "testing.yaml"),
+		filepath.Join("templates", "claude.tmpl"),
+		filepath.Join("templates", "cursorrules.tmpl"),
+		filepath.Join(".github", "workflows", "ai-rulez.yml"),
+		".pre-commit-config.yaml",
+	} {
+		_, err := os.Stat(f)
+		assert.NoError(t, err, "expected %s to exist", f)
+	}
+
+	for _, output := range out.Outputs {
+		assert.NotEmpty(t, output.Template, "output %s should get a template", output.File)
+	}
+}
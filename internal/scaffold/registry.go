@@ -0,0 +1,214 @@
+package scaffold
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates/*.yaml
+var embeddedTemplatesFS embed.FS
+
+// UserTemplatesDir returns the directory ai-rulez searches for
+// user-installed templates: $XDG_CONFIG_HOME/ai-rulez/templates, or the
+// platform config directory equivalent.
+func UserTemplatesDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "ai-rulez", "templates"), nil
+}
+
+// Registry holds the combined set of available init templates, keyed by name.
+type Registry struct {
+	templates map[string]*Template
+	order     []string
+}
+
+// NewRegistry builds a Registry from the embedded templates, the user
+// template directory (if it exists), and catalogURL (if non-empty). Later
+// sources override earlier ones when a template name collides.
+func NewRegistry(catalogURL string) (*Registry, error) {
+	r := &Registry{templates: make(map[string]*Template)}
+
+	entries, err := embeddedTemplatesFS.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded templates: %w", err)
+	}
+	for _, entry := range entries {
+		data, err := embeddedTemplatesFS.ReadFile(filepath.Join("templates", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded template %s: %w", entry.Name(), err)
+		}
+		if err := r.addYAML(data); err != nil {
+			return nil, fmt.Errorf("failed to parse embedded template %s: %w", entry.Name(), err)
+		}
+	}
+
+	userDir, err := UserTemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadDir(userDir); err != nil {
+		return nil, err
+	}
+
+	if catalogURL != "" {
+		if err := r.loadRemoteCatalog(catalogURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// addYAML parses data as a single Template and registers it, overriding any
+// earlier template of the same name.
+func (r *Registry) addYAML(data []byte) error {
+	var tmpl Template
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return err
+	}
+	if tmpl.Name == "" {
+		return fmt.Errorf("template is missing a name")
+	}
+	if _, exists := r.templates[tmpl.Name]; !exists {
+		r.order = append(r.order, tmpl.Name)
+	}
+	r.templates[tmpl.Name] = &tmpl
+	return nil
+}
+
+// loadDir loads every *.yaml file in dir as a template. A missing dir is not
+// an error: most installs never create a user template directory.
+func (r *Registry) loadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read user template directory %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+		if err := r.addYAML(data); err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// catalogIndex is the format of a remote template catalog: a flat list of
+// templates, each fetched individually from its own URL.
+type catalogIndex struct {
+	Templates []struct {
+		URL string `yaml:"url"`
+	} `yaml:"templates"`
+}
+
+// loadRemoteCatalog fetches the catalog index at url, then fetches and
+// registers every template it lists, overriding same-named templates.
+func (r *Registry) loadRemoteCatalog(url string) error {
+	data, err := fetchURL(url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch template catalog %s: %w", url, err)
+	}
+
+	var index catalogIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("failed to parse template catalog %s: %w", url, err)
+	}
+
+	for _, entry := range index.Templates {
+		tmplData, err := fetchURL(entry.URL)
+		if err != nil {
+			return fmt.Errorf("failed to fetch template %s: %w", entry.URL, err)
+		}
+		if err := r.addYAML(tmplData); err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", entry.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// Get returns the named template, or an error listing the available names.
+func (r *Registry) Get(name string) (*Template, error) {
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(r.order, ", "))
+	}
+	return tmpl, nil
+}
+
+// List returns every known template, in the order first encountered
+// (embedded, then user, then remote).
+func (r *Registry) List() []*Template {
+	result := make([]*Template, 0, len(r.order))
+	for _, name := range r.order {
+		result = append(result, r.templates[name])
+	}
+	return result
+}
+
+// Install fetches the template at url and writes it into the user template
+// directory as "<name>.yaml", returning the template's name. It overrides
+// any previously installed template of the same name.
+func Install(url string) (string, error) {
+	data, err := fetchURL(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch template %s: %w", url, err)
+	}
+
+	var tmpl Template
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", url, err)
+	}
+	if tmpl.Name == "" {
+		return "", fmt.Errorf("template at %s is missing a name", url)
+	}
+
+	dir, err := UserTemplatesDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create user template directory %s: %w", dir, err)
+	}
+
+	dest := filepath.Join(dir, tmpl.Name+".yaml")
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write template %s: %w", dest, err)
+	}
+
+	return tmpl.Name, nil
+}
+
+func fetchURL(url string) ([]byte, error) {
+	resp, err := http.Get(url) //nolint:gosec // catalog/template URL is author-controlled config
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
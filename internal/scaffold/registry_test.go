@@ -0,0 +1,137 @@
+package scaffold_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/scaffold"
+)
+
+func TestNewRegistryLoadsEmbeddedTemplates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	registry, err := scaffold.NewRegistry("")
+	require.NoError(t, err)
+
+	tmpl, err := registry.Get("basic")
+	require.NoError(t, err)
+	assert.Equal(t, "basic", tmpl.Name)
+	assert.NotEmpty(t, tmpl.Config.Rules)
+
+	names := make([]string, 0)
+	for _, t := range registry.List() {
+		names = append(names, t.Name)
+	}
+	assert.Contains(t, names, "basic")
+	assert.Contains(t, names, "react")
+	assert.Contains(t, names, "typescript")
+}
+
+func TestRegistryGetUnknownTemplate(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	registry, err := scaffold.NewRegistry("")
+	require.NoError(t, err)
+
+	_, err = registry.Get("does-not-exist")
+	assert.Error(t, err)
+}
+
+func TestUserTemplateOverridesEmbedded(t *testing.T) {
+	xdgHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgHome)
+
+	userDir := filepath.Join(xdgHome, "ai-rulez", "templates")
+	require.NoError(t, os.MkdirAll(userDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(userDir, "basic.yaml"), []byte(`name: basic
+description: "Custom override"
+config:
+  metadata:
+    name: "placeholder"
+  outputs:
+    - file: "claude.md"
+  rules:
+    - name: "Custom Rule"
+      content: "Custom content"
+`), 0o644))
+
+	registry, err := scaffold.NewRegistry("")
+	require.NoError(t, err)
+
+	tmpl, err := registry.Get("basic")
+	require.NoError(t, err)
+	assert.Equal(t, "Custom override", tmpl.Description)
+	assert.Len(t, tmpl.Config.Rules, 1)
+	assert.Equal(t, "Custom Rule", tmpl.Config.Rules[0].Name)
+}
+
+func TestNewRegistryLoadsRemoteCatalog(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	const templateYAML = `name: nextjs
+description: "Next.js project rules"
+config:
+  metadata:
+    name: "placeholder"
+  outputs:
+    - file: "claude.md"
+  rules:
+    - name: "Server Components"
+      content: "Prefer server components unless interactivity is required."
+`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/template.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(templateYAML))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/index.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("templates:\n  - url: " + server.URL + "/template.yaml\n"))
+	})
+
+	registry, err := scaffold.NewRegistry(server.URL + "/index.yaml")
+	require.NoError(t, err)
+
+	tmpl, err := registry.Get("nextjs")
+	require.NoError(t, err)
+	assert.Equal(t, "Next.js project rules", tmpl.Description)
+}
+
+func TestInstall(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	const templateYAML = `name: django
+description: "Django project rules"
+config:
+  metadata:
+    name: "placeholder"
+  outputs:
+    - file: "claude.md"
+  rules:
+    - name: "Models"
+      content: "Keep business logic out of views."
+`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(templateYAML))
+	}))
+	defer server.Close()
+
+	name, err := scaffold.Install(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "django", name)
+
+	registry, err := scaffold.NewRegistry("")
+	require.NoError(t, err)
+	tmpl, err := registry.Get("django")
+	require.NoError(t, err)
+	assert.Equal(t, "Django project rules", tmpl.Description)
+}
@@ -0,0 +1,23 @@
+// Package scaffold provides the template catalog used by `ai-rulez init`.
+// Project starter templates are loaded from an embedded set, a user
+// template directory, and an optional remote catalog, in that precedence
+// order, so the template set can grow (nextjs, django, rust-axum, etc.)
+// without recompiling the binary.
+package scaffold
+
+import "github.com/Goldziher/ai-rulez/internal/config"
+
+// Manifest describes a template: its name, human-readable blurb, and the
+// tags/tools a caller can use to pick one without reading its rules.
+type Manifest struct {
+	Name          string   `yaml:"name"`
+	Description   string   `yaml:"description,omitempty"`
+	Tags          []string `yaml:"tags,omitempty"`
+	RequiredTools []string `yaml:"required_tools,omitempty"`
+}
+
+// Template is a Manifest paired with the ai_rules.yaml fragment it installs.
+type Template struct {
+	Manifest `yaml:",inline"`
+	Config   *config.Config `yaml:"config"`
+}
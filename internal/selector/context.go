@@ -0,0 +1,49 @@
+package selector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// projectFactFiles are paths (relative to a project root, checked literally;
+// globs are not expanded) whose presence is recorded as a tag of the same
+// name so selectors can write `has(go.mod)`
+// or `has(package.json)` without the caller wiring up detection itself.
+var projectFactFiles = []string{
+	"go.mod",
+	"package.json",
+	".github/workflows",
+}
+
+// DetectContext builds a Context from CLI --tag flags, the AI_RULEZ_TAGS
+// environment variable (comma-separated), and project facts detected by
+// the presence of well-known files under rootDir.
+func DetectContext(rootDir string, cliTags []string) *Context {
+	ctx := NewContext()
+
+	for _, tag := range cliTags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			ctx.Tags[tag] = true
+		}
+	}
+
+	if env := os.Getenv("AI_RULEZ_TAGS"); env != "" {
+		for _, tag := range strings.Split(env, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				ctx.Tags[tag] = true
+			}
+		}
+	}
+
+	for _, name := range projectFactFiles {
+		if _, err := os.Stat(filepath.Join(rootDir, name)); err == nil {
+			ctx.Tags[name] = true
+		}
+	}
+
+	ctx.Facts["os"] = runtime.GOOS
+
+	return ctx
+}
@@ -0,0 +1,162 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+	tokEq
+	tokNeq
+	tokAnd
+	tokOr
+	tokNot
+	tokIn
+	tokNotIn
+	tokHas
+	tokExists
+	tokGt
+	tokLt
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lexer splits a selector expression into tokens. Identifiers are any run
+// of letters, digits, '_', '.', or '-' (so "go.mod" and "ci-build" are
+// single identifiers); everything else is punctuation or a keyword.
+type lexer struct {
+	input string
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: input}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF}, nil
+	}
+
+	ch := l.input[l.pos]
+	switch {
+	case ch == '(':
+		l.pos++
+		return token{kind: tokLParen}, nil
+	case ch == ')':
+		l.pos++
+		return token{kind: tokRParen}, nil
+	case ch == '[':
+		l.pos++
+		return token{kind: tokLBracket}, nil
+	case ch == ']':
+		l.pos++
+		return token{kind: tokRBracket}, nil
+	case ch == ',':
+		l.pos++
+		return token{kind: tokComma}, nil
+	case ch == '=' && l.peek(1) == '=':
+		l.pos += 2
+		return token{kind: tokEq}, nil
+	case ch == '=':
+		l.pos++
+		return token{kind: tokEq}, nil
+	case ch == '!' && l.peek(1) == '=':
+		l.pos += 2
+		return token{kind: tokNeq}, nil
+	case ch == '!':
+		l.pos++
+		return token{kind: tokNot, text: "!"}, nil
+	case ch == '&' && l.peek(1) == '&':
+		l.pos += 2
+		return token{kind: tokAnd, text: "&&"}, nil
+	case ch == '|' && l.peek(1) == '|':
+		l.pos += 2
+		return token{kind: tokOr, text: "||"}, nil
+	case ch == '>':
+		l.pos++
+		return token{kind: tokGt}, nil
+	case ch == '<':
+		l.pos++
+		return token{kind: tokLt}, nil
+	case ch == '"' || ch == '\'':
+		return l.lexString(ch)
+	case isIdentRune(rune(ch)):
+		return l.lexIdent(), nil
+	default:
+		return token{}, fmt.Errorf("unexpected character %q", ch)
+	}
+}
+
+func (l *lexer) peek(offset int) byte {
+	if l.pos+offset >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos+offset]
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+func (l *lexer) lexIdent() token {
+	start := l.pos
+	for l.pos < len(l.input) && isIdentRune(rune(l.input[l.pos])) {
+		l.pos++
+	}
+	word := l.input[start:l.pos]
+
+	switch strings.ToLower(word) {
+	case "and":
+		return token{kind: tokAnd, text: word}
+	case "or":
+		return token{kind: tokOr, text: word}
+	case "not":
+		return token{kind: tokNot, text: word}
+	case "in":
+		return token{kind: tokIn, text: word}
+	case "notin":
+		return token{kind: tokNotIn, text: word}
+	case "has":
+		return token{kind: tokHas, text: word}
+	case "exists":
+		return token{kind: tokExists, text: word}
+	default:
+		return token{kind: tokIdent, text: word}
+	}
+}
+
+func (l *lexer) lexString(quote byte) (token, error) {
+	l.pos++ // skip opening quote
+	start := l.pos
+	for l.pos < len(l.input) && l.input[l.pos] != quote {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{}, fmt.Errorf("unterminated string literal")
+	}
+	text := l.input[start:l.pos]
+	l.pos++ // skip closing quote
+	return token{kind: tokString, text: text}, nil
+}
@@ -0,0 +1,368 @@
+package selector
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// node is a selector AST node. Every node evaluates to a bool given a Context.
+type node interface {
+	eval(ctx *Context) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(ctx *Context) bool { return n.left.eval(ctx) && n.right.eval(ctx) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(ctx *Context) bool { return n.left.eval(ctx) || n.right.eval(ctx) }
+
+type notNode struct{ inner node }
+
+func (n notNode) eval(ctx *Context) bool { return !n.inner.eval(ctx) }
+
+// hasNode is true when ctx has a tag or fact named ident (has(ident), or
+// its alias exists(ident)).
+type hasNode struct{ ident string }
+
+func (n hasNode) eval(ctx *Context) bool { return ctx.Has(n.ident) }
+
+// eqNode compares a fact's value (or the tag's own name, for bare tags)
+// against a literal, e.g. `os == linux`.
+type eqNode struct {
+	ident  string
+	value  string
+	negate bool
+}
+
+func (n eqNode) eval(ctx *Context) bool {
+	actual, ok := ctx.Value(n.ident)
+	if !ok {
+		// A bare tag can stand in for its own name, e.g. `ci == ci`.
+		actual, ok = n.ident, ctx.Tags[n.ident]
+	}
+	matched := ok && actual == n.value
+	if n.negate {
+		return !matched
+	}
+	return matched
+}
+
+// inNode is true when ident's fact value (or the tag itself) is one of
+// values; negate flips this to implement `notin`.
+type inNode struct {
+	ident  string
+	values []string
+	negate bool
+}
+
+func (n inNode) eval(ctx *Context) bool {
+	actual, ok := ctx.Value(n.ident)
+	if !ok {
+		if !ctx.Tags[n.ident] {
+			return n.negate
+		}
+		actual = n.ident
+	}
+	found := false
+	for _, v := range n.values {
+		if actual == v {
+			found = true
+			break
+		}
+	}
+	if n.negate {
+		return !found
+	}
+	return found
+}
+
+// cmpNode is a numeric `>`/`<` comparison against a fact's value, e.g.
+// `priority > 5`. Non-numeric or missing facts never match.
+type cmpNode struct {
+	ident string
+	value float64
+	less  bool
+}
+
+func (n cmpNode) eval(ctx *Context) bool {
+	actual, ok := ctx.Value(n.ident)
+	if !ok {
+		return false
+	}
+	num, err := strconv.ParseFloat(actual, 64)
+	if err != nil {
+		return false
+	}
+	if n.less {
+		return num < n.value
+	}
+	return num > n.value
+}
+
+// identNode is a bare identifier used as a boolean, e.g. `ci` alone means
+// "the ci tag/fact is present" (same as has(ci)).
+type identNode struct{ ident string }
+
+func (n identNode) eval(ctx *Context) bool { return ctx.Has(n.ident) }
+
+// parser is a small recursive-descent parser over the selector grammar:
+//
+//	expr   := or
+//	or     := and (OR and)*
+//	and    := unary (AND unary)*
+//	unary  := NOT unary | primary
+//
+// OR, AND, and NOT each accept two spellings: the word form (or/and/not)
+// and the symbolic form (||/&&/!); the lexer maps both onto the same
+// token kind, so the grammar above is unaffected by which one is used.
+//	primary:= (HAS|EXISTS) '(' IDENT ')'
+//	        | IDENT (IN|NOTIN) '[' IDENT (',' IDENT)* ']'
+//	        | IDENT (EQ|NEQ) (IDENT|STRING)
+//	        | IDENT (GT|LT) (IDENT|STRING)
+//	        | IDENT
+//	        | '(' expr ')'
+type parser struct {
+	lexer *lexer
+	cur   token
+	has   bool
+}
+
+func (p *parser) peekToken() (token, error) {
+	if !p.has {
+		tok, err := p.lexer.next()
+		if err != nil {
+			return token{}, err
+		}
+		p.cur = tok
+		p.has = true
+	}
+	return p.cur, nil
+}
+
+func (p *parser) advance() (token, error) {
+	tok, err := p.peekToken()
+	if err != nil {
+		return token{}, err
+	}
+	p.has = false
+	return tok, nil
+}
+
+func (p *parser) atEnd() bool {
+	tok, err := p.peekToken()
+	return err == nil && tok.kind == tokEOF
+}
+
+func (p *parser) parseExpr() (node, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, err := p.peekToken()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind != tokOr {
+			return left, nil
+		}
+		if _, err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, err := p.peekToken()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind != tokAnd {
+			return left, nil
+		}
+		if _, err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok, err := p.peekToken()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokNot {
+		if _, err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok, err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		closing, err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		if closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		return inner, nil
+
+	case tokHas, tokExists:
+		if _, err := p.expect(tokLParen); err != nil {
+			return nil, err
+		}
+		ident, err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		if ident.kind != tokIdent {
+			return nil, fmt.Errorf("expected identifier in %s()", tok.text)
+		}
+		if _, err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return hasNode{ident: ident.text}, nil
+
+	case tokIdent:
+		return p.parseAfterIdent(tok.text)
+
+	default:
+		return nil, fmt.Errorf("unexpected token")
+	}
+}
+
+// parseAfterIdent handles the forms that start with a bare identifier:
+// `ident`, `ident (in|notin) [...]`, `ident ==/!= value`, and
+// `ident (>|<) value`.
+func (p *parser) parseAfterIdent(ident string) (node, error) {
+	next, err := p.peekToken()
+	if err != nil {
+		return nil, err
+	}
+
+	switch next.kind {
+	case tokIn, tokNotIn:
+		if _, err := p.advance(); err != nil {
+			return nil, err
+		}
+		values, err := p.parseBracketList()
+		if err != nil {
+			return nil, err
+		}
+		return inNode{ident: ident, values: values, negate: next.kind == tokNotIn}, nil
+
+	case tokEq, tokNeq:
+		if _, err := p.advance(); err != nil {
+			return nil, err
+		}
+		valueTok, err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		if valueTok.kind != tokIdent && valueTok.kind != tokString {
+			return nil, fmt.Errorf("expected value after %s", ident)
+		}
+		return eqNode{ident: ident, value: valueTok.text, negate: next.kind == tokNeq}, nil
+
+	case tokGt, tokLt:
+		if _, err := p.advance(); err != nil {
+			return nil, err
+		}
+		valueTok, err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		if valueTok.kind != tokIdent && valueTok.kind != tokString {
+			return nil, fmt.Errorf("expected value after %s", ident)
+		}
+		num, err := strconv.ParseFloat(valueTok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number after %s, got %q", ident, valueTok.text)
+		}
+		return cmpNode{ident: ident, value: num, less: next.kind == tokLt}, nil
+
+	default:
+		return identNode{ident: ident}, nil
+	}
+}
+
+func (p *parser) parseBracketList() ([]string, error) {
+	if _, err := p.expect(tokLBracket); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for {
+		tok, err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind != tokIdent && tok.kind != tokString {
+			return nil, fmt.Errorf("expected value in list")
+		}
+		values = append(values, tok.text)
+
+		sep, err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		switch sep.kind {
+		case tokComma:
+			continue
+		case tokRBracket:
+			return values, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or ']' in list")
+		}
+	}
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok, err := p.advance()
+	if err != nil {
+		return token{}, err
+	}
+	if tok.kind != kind {
+		return token{}, fmt.Errorf("unexpected token")
+	}
+	return tok, nil
+}
@@ -0,0 +1,103 @@
+// Package selector evaluates small boolean expressions over a Context of
+// tags and facts, used by Rule, Section, and Include `when:` fields to
+// activate content only for the matching part of a polyglot monorepo.
+package selector
+
+import (
+	"fmt"
+)
+
+// Context is the set of tags and facts a selector expression is evaluated
+// against. Tags come from --tag flags, AI_RULEZ_TAGS and detected project
+// files (e.g. "go.mod" present); facts are key/value pairs like os or
+// language.
+type Context struct {
+	// Tags is the set of bare identifiers has() and in-lists match against.
+	Tags map[string]bool
+	// Facts holds key/value pairs, e.g. Facts["os"] = "linux", matched by
+	// equality and `in` expressions (identifier == value, identifier in [..]).
+	Facts map[string]string
+}
+
+// NewContext returns an empty Context ready to be populated by callers.
+func NewContext() *Context {
+	return &Context{Tags: make(map[string]bool), Facts: make(map[string]string)}
+}
+
+// Has reports whether name is a known tag or fact key.
+func (c *Context) Has(name string) bool {
+	if c == nil {
+		return false
+	}
+	if c.Tags[name] {
+		return true
+	}
+	_, ok := c.Facts[name]
+	return ok
+}
+
+// Value returns the fact value for name, and whether it was set.
+func (c *Context) Value(name string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	v, ok := c.Facts[name]
+	return v, ok
+}
+
+// Evaluate parses and evaluates expr against ctx. An empty expr always
+// matches, so content without a `when:` field is unaffected.
+func Evaluate(expr string, ctx *Context) (bool, error) {
+	compiled, err := Compile(expr)
+	if err != nil {
+		return false, err
+	}
+	return compiled.Eval(ctx), nil
+}
+
+// Compiled is a selector expression parsed once, so a caller evaluating it
+// against many items (e.g. every Rule and Section in an Output's tag
+// selector) doesn't re-lex and re-parse expr on every call.
+type Compiled struct {
+	expr string
+	node node // nil means expr was empty: always matches
+}
+
+// Compile parses expr into a Compiled selector. An empty expr compiles to
+// a selector that always matches, same as Evaluate("", ctx).
+func Compile(expr string) (*Compiled, error) {
+	if expr == "" {
+		return &Compiled{}, nil
+	}
+
+	p := &parser{lexer: newLexer(expr)}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+	}
+	if !p.atEnd() {
+		return nil, fmt.Errorf("invalid selector %q: unexpected trailing input", expr)
+	}
+
+	return &Compiled{expr: expr, node: node}, nil
+}
+
+// Eval evaluates the compiled selector against ctx. A nil ctx matches only
+// an empty expression.
+func (c *Compiled) Eval(ctx *Context) bool {
+	if c == nil || c.node == nil {
+		return true
+	}
+	if ctx == nil {
+		return false
+	}
+	return c.node.eval(ctx)
+}
+
+// String returns the original expression Compile parsed.
+func (c *Compiled) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.expr
+}
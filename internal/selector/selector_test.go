@@ -0,0 +1,175 @@
+package selector_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/selector"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	ctx := &selector.Context{
+		Tags:  map[string]bool{"ci": true, "go.mod": true},
+		Facts: map[string]string{"os": "linux", "language": "go"},
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{"empty selector always matches", "", true},
+		{"bare tag present", "ci", true},
+		{"bare tag absent", "rust", false},
+		{"has() present", "has(go.mod)", true},
+		{"has() absent", "has(package.json)", false},
+		{"equality match", "os == linux", true},
+		{"equality mismatch", "os == windows", false},
+		{"inequality", "os != windows", true},
+		{"in list match", "language in [go, rust]", true},
+		{"in list miss", "language in [python, ruby]", false},
+		{"and both true", "ci and has(go.mod)", true},
+		{"and one false", "ci and rust", false},
+		{"or one true", "rust or ci", true},
+		{"not negates", "not rust", true},
+		{"parens group or before and", "(ci or rust) and has(go.mod)", true},
+		{"and short-circuits on an unknown identifier", "rust and does-not-exist", false},
+		{"notin match", "language notin [python, ruby]", true},
+		{"notin miss", "language notin [go, rust]", false},
+		{"exists() present", "exists(go.mod)", true},
+		{"exists() absent", "exists(package.json)", false},
+		{"single = is an eq alias", "os = linux", true},
+		{"symbolic and/or/not", "ci && (rust || !rust)", true},
+		{"symbolic and false", "ci && rust", false},
+		{"symbolic not", "!rust", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := selector.Evaluate(tt.expr, ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluateNumericComparison(t *testing.T) {
+	t.Parallel()
+
+	ctx := &selector.Context{Facts: map[string]string{"priority": "8"}}
+
+	tests := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{"greater than true", "priority > 5", true},
+		{"greater than false", "priority > 9", false},
+		{"less than true", "priority < 9", true},
+		{"less than false", "priority < 5", false},
+		{"combined with and", "priority > 5 and priority < 10", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := selector.Evaluate(tt.expr, ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluateNumericComparisonNonNumericFactNeverMatches(t *testing.T) {
+	t.Parallel()
+
+	ctx := &selector.Context{Facts: map[string]string{"os": "linux"}}
+
+	got, err := selector.Evaluate("os > 5", ctx)
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestCompileInvalidComparisonValue(t *testing.T) {
+	t.Parallel()
+
+	_, err := selector.Compile("priority > not-a-number")
+	assert.Error(t, err)
+}
+
+func TestEvaluateUnknownIdentifierIsFalseNotError(t *testing.T) {
+	t.Parallel()
+
+	got, err := selector.Evaluate("some_unknown_tag", selector.NewContext())
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestEvaluateInvalidSyntax(t *testing.T) {
+	t.Parallel()
+
+	_, err := selector.Evaluate("ci and (", selector.NewContext())
+	assert.Error(t, err)
+}
+
+func TestCompileEvalMatchesEvaluate(t *testing.T) {
+	t.Parallel()
+
+	ctx := &selector.Context{Tags: map[string]bool{"ci": true}}
+
+	compiled, err := selector.Compile("ci and not rust")
+	require.NoError(t, err)
+	assert.True(t, compiled.Eval(ctx))
+	assert.Equal(t, "ci and not rust", compiled.String())
+
+	// A Compiled selector is reusable against multiple contexts.
+	assert.False(t, compiled.Eval(selector.NewContext()))
+}
+
+func TestCompileEmptyExprAlwaysMatches(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := selector.Compile("")
+	require.NoError(t, err)
+	assert.True(t, compiled.Eval(selector.NewContext()))
+	assert.Empty(t, compiled.String())
+}
+
+func TestCompileInvalidSyntax(t *testing.T) {
+	t.Parallel()
+
+	_, err := selector.Compile("ci and (")
+	assert.Error(t, err)
+}
+
+func TestCompiledEvalNilReceiverAlwaysMatches(t *testing.T) {
+	t.Parallel()
+
+	var compiled *selector.Compiled
+	assert.True(t, compiled.Eval(selector.NewContext()))
+	assert.Empty(t, compiled.String())
+}
+
+func TestDetectContext(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example\n"), 0o644))
+
+	ctx := selector.DetectContext(tmpDir, []string{"integration", " ci "})
+
+	assert.True(t, ctx.Tags["integration"])
+	assert.True(t, ctx.Tags["ci"])
+	assert.True(t, ctx.Tags["go.mod"])
+	assert.False(t, ctx.Tags["package.json"])
+	assert.NotEmpty(t, ctx.Facts["os"])
+}
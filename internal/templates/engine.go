@@ -0,0 +1,236 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aymerick/raymond"
+	"github.com/cbroglie/mustache"
+)
+
+// EngineName identifies a pluggable template engine.
+type EngineName string
+
+const (
+	// EngineGo is the default, backed by Go's text/template (the Renderer type).
+	EngineGo EngineName = "go"
+	// EngineHandlebars renders templates using Handlebars syntax.
+	EngineHandlebars EngineName = "handlebars"
+	// EngineMustache renders templates using Mustache syntax.
+	EngineMustache EngineName = "mustache"
+)
+
+// TemplateEngine is implemented by every pluggable rendering backend so the
+// generator can register and render templates without knowing which engine
+// produced them.
+type TemplateEngine interface {
+	// RegisterTemplate compiles and stores a template under name for later Render calls.
+	RegisterTemplate(name, templateStr string) error
+	// Render executes the named template against data and returns the result.
+	Render(name string, data *TemplateData) (string, error)
+	// RegisterHelper adds a callable helper under name, available to every
+	// template this engine registers from this point on. Matches zk's
+	// RegisterHelper pattern so plugin authors and tests can extend any
+	// engine's helper set the same way, regardless of which one an output
+	// picked; an engine that can't support a helper (e.g. Mustache, which is
+	// logic-less) returns an error instead of silently ignoring it.
+	RegisterHelper(name string, fn interface{}) error
+}
+
+// EngineForExtension maps a template file extension to the engine that should
+// render it, used when an output's template is an `@file` reference.
+func EngineForExtension(ext string) EngineName {
+	switch strings.ToLower(ext) {
+	case ".hbs", ".handlebars":
+		return EngineHandlebars
+	case ".mustache":
+		return EngineMustache
+	default:
+		return EngineGo
+	}
+}
+
+// ParseEngineName normalizes an `engine:` field value, defaulting to EngineGo.
+func ParseEngineName(name string) (EngineName, error) {
+	switch EngineName(name) {
+	case "", EngineGo:
+		return EngineGo, nil
+	case EngineHandlebars:
+		return EngineHandlebars, nil
+	case EngineMustache:
+		return EngineMustache, nil
+	default:
+		return "", fmt.Errorf("unknown template engine %q (supported: go, handlebars, mustache)", name)
+	}
+}
+
+// NewEngine constructs the TemplateEngine implementation for the given name.
+func NewEngine(name EngineName) (TemplateEngine, error) {
+	switch name {
+	case "", EngineGo:
+		return NewRenderer(), nil
+	case EngineHandlebars:
+		return newHandlebarsEngine(), nil
+	case EngineMustache:
+		return newMustacheEngine(), nil
+	default:
+		return nil, fmt.Errorf("unknown template engine %q", name)
+	}
+}
+
+// ValidateTemplateForEngine checks that templateStr is syntactically valid
+// for engine, e.g. so `ai-rulez validate` rejects a Handlebars typo against
+// Handlebars' own grammar instead of (or in addition to) Go's. It registers
+// templateStr on a throwaway engine instance purely to reuse that engine's
+// own parse-time validation; nothing is kept afterward.
+func ValidateTemplateForEngine(templateStr string, engine EngineName) error {
+	eng, err := NewEngine(engine)
+	if err != nil {
+		return err
+	}
+	return eng.RegisterTemplate("validation", templateStr)
+}
+
+// handlebarsHelpers mirrors the small helper set non-Go users reach for most
+// often when writing rule templates, analogous to Handlebars' own built-in helpers.
+func handlebarsHelpers() map[string]interface{} {
+	return map[string]interface{}{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join": func(sep string, items []string) string {
+			return strings.Join(items, sep)
+		},
+		"indent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			lines := strings.Split(s, "\n")
+			for i, line := range lines {
+				lines[i] = pad + line
+			}
+			return strings.Join(lines, "\n")
+		},
+		"wrap": wrap,
+		"env":  os.Getenv,
+		"date": func(layout string) string {
+			return time.Now().Format(layout)
+		},
+		"shell": func(command string) string {
+			out, err := exec.Command("sh", "-c", command).Output() //nolint:gosec // rule templates are author-controlled
+			if err != nil {
+				return ""
+			}
+			return strings.TrimRight(string(out), "\n")
+		},
+
+		// Domain-specific helpers shared with the Go engine's baseTemplateFuncs,
+		// registered under the same names (plus the "slugify" alias Handlebars
+		// users reaching for Hugo/consul-template conventions expect; the Go
+		// engine keeps its established "slug" name for backward compatibility).
+		"filterByTag":      filterByTag,
+		"groupBySection":   groupBySection,
+		"sortRules":        sortRules,
+		"toc":              toc,
+		"shellQuote":       shellQuote,
+		"slugify":          slug,
+		"filterByType":     filterByType,
+		"filterByPriority": filterByPriority,
+		"groupByPriority":  groupByPriority,
+	}
+}
+
+// handlebarsEngine renders `{{ }}` Handlebars templates via raymond, with the
+// shared helper registry available to every rule/section template.
+type handlebarsEngine struct {
+	templates map[string]string
+}
+
+// registerSharedHelpersOnce guards handlebarsHelpers' registration onto
+// raymond's process-global registry: raymond.RegisterHelper panics if a
+// name is already registered, so a second handlebarsEngine in the same
+// process (e.g. `generate --watch` building a fresh Generator on every
+// reload) would otherwise crash the CLI.
+var registerSharedHelpersOnce sync.Once
+
+func newHandlebarsEngine() *handlebarsEngine {
+	registerSharedHelpersOnce.Do(func() {
+		raymond.RegisterHelpers(handlebarsHelpers())
+	})
+	return &handlebarsEngine{templates: make(map[string]string)}
+}
+
+func (e *handlebarsEngine) RegisterTemplate(name, templateStr string) error {
+	if _, err := raymond.Parse(templateStr); err != nil {
+		return fmt.Errorf("failed to parse handlebars template for %s: %w", name, err)
+	}
+	e.templates[name] = templateStr
+	return nil
+}
+
+// RegisterHelper adds fn to raymond's helper registry under name. raymond
+// only exposes process-global registration (raymond.RegisterHelper), not a
+// per-instance one, so this affects every handlebarsEngine in the process,
+// not just e - the same caveat newHandlebarsEngine's call to
+// raymond.RegisterHelpers already carries.
+func (e *handlebarsEngine) RegisterHelper(name string, fn interface{}) error {
+	if fn == nil {
+		return fmt.Errorf("helper %q: fn must not be nil", name)
+	}
+	raymond.RegisterHelper(name, fn)
+	return nil
+}
+
+func (e *handlebarsEngine) Render(name string, data *TemplateData) (string, error) {
+	templateStr, exists := e.templates[name]
+	if !exists {
+		return "", fmt.Errorf("unknown template format: %s", name)
+	}
+
+	result, err := raymond.Render(templateStr, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute handlebars template %s: %w", name, err)
+	}
+	return result, nil
+}
+
+// mustacheEngine renders logic-less `{{ }}` Mustache templates via cbroglie/mustache.
+type mustacheEngine struct {
+	templates map[string]string
+}
+
+func newMustacheEngine() *mustacheEngine {
+	return &mustacheEngine{templates: make(map[string]string)}
+}
+
+func (e *mustacheEngine) RegisterTemplate(name, templateStr string) error {
+	if _, err := mustache.ParseString(templateStr); err != nil {
+		return fmt.Errorf("failed to parse mustache template for %s: %w", name, err)
+	}
+	e.templates[name] = templateStr
+	return nil
+}
+
+// RegisterHelper always fails: Mustache is deliberately logic-less and
+// cbroglie/mustache has no helper-registration concept - a Mustache template
+// can only see functions placed directly in its data (lambdas), which isn't
+// something RegisterHelper's (name, fn) shape can retrofit onto an already-
+// rendered *TemplateData. Callers that need a custom helper should pick the
+// Go or Handlebars engine for that output instead.
+func (e *mustacheEngine) RegisterHelper(name string, _ interface{}) error {
+	return fmt.Errorf("mustache templates are logic-less and do not support custom helpers (tried to register %q); use the go or handlebars engine instead", name)
+}
+
+func (e *mustacheEngine) Render(name string, data *TemplateData) (string, error) {
+	templateStr, exists := e.templates[name]
+	if !exists {
+		return "", fmt.Errorf("unknown template format: %s", name)
+	}
+
+	result, err := mustache.Render(templateStr, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute mustache template %s: %w", name, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/templates"
+)
+
+func TestParseEngineName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    templates.EngineName
+		wantErr bool
+	}{
+		{name: "empty defaults to go", input: "", want: templates.EngineGo},
+		{name: "explicit go", input: "go", want: templates.EngineGo},
+		{name: "handlebars", input: "handlebars", want: templates.EngineHandlebars},
+		{name: "mustache", input: "mustache", want: templates.EngineMustache},
+		{name: "unknown engine errors", input: "jinja", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := templates.ParseEngineName(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEngineForExtension(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, templates.EngineHandlebars, templates.EngineForExtension(".hbs"))
+	assert.Equal(t, templates.EngineMustache, templates.EngineForExtension(".mustache"))
+	assert.Equal(t, templates.EngineGo, templates.EngineForExtension(".tmpl"))
+}
+
+func TestHandlebarsEngineRender(t *testing.T) {
+	t.Parallel()
+
+	engine, err := templates.NewEngine(templates.EngineHandlebars)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.RegisterTemplate("greeting", "Hello {{upper ProjectName}}"))
+
+	data := templates.NewTemplateData(&config.Config{
+		Metadata: config.Metadata{Name: "acme"},
+	})
+
+	out, err := engine.Render("greeting", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello ACME", out)
+}
+
+func TestHandlebarsEngineRegisterHelper(t *testing.T) {
+	t.Parallel()
+
+	engine, err := templates.NewEngine(templates.EngineHandlebars)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.RegisterHelper("shout", func(s string) string { return s + "!!!" }))
+	require.NoError(t, engine.RegisterTemplate("shout-test", "{{shout ProjectName}}"))
+
+	out, err := engine.Render("shout-test", templates.NewTemplateData(&config.Config{
+		Metadata: config.Metadata{Name: "acme"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "acme!!!", out)
+
+	assert.Error(t, engine.RegisterHelper("nilFn", nil))
+}
+
+func TestHandlebarsEngineSharedHelpers(t *testing.T) {
+	t.Setenv("AI_RULEZ_ENGINE_TEST_VAR", "world")
+
+	engine, err := templates.NewEngine(templates.EngineHandlebars)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.RegisterTemplate("shared", `{{wrap 5 "one two"}} {{env "AI_RULEZ_ENGINE_TEST_VAR"}}`))
+
+	out, err := engine.Render("shared", templates.NewTemplateData(&config.Config{}))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo world", out)
+}
+
+func TestMustacheEngineRegisterHelperErrors(t *testing.T) {
+	t.Parallel()
+
+	engine, err := templates.NewEngine(templates.EngineMustache)
+	require.NoError(t, err)
+
+	assert.Error(t, engine.RegisterHelper("shout", func(s string) string { return s }))
+}
+
+func TestValidateTemplateForEngine(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, templates.ValidateTemplateForEngine("{{.ProjectName}}", templates.EngineGo))
+	assert.Error(t, templates.ValidateTemplateForEngine("{{.Invalid}", templates.EngineGo))
+
+	assert.NoError(t, templates.ValidateTemplateForEngine("Hello {{ProjectName}}", templates.EngineHandlebars))
+	assert.Error(t, templates.ValidateTemplateForEngine("Hello {{#if}}", templates.EngineHandlebars))
+
+	assert.NoError(t, templates.ValidateTemplateForEngine("Hello {{ProjectName}}", templates.EngineMustache))
+}
+
+func TestMustacheEngineRender(t *testing.T) {
+	t.Parallel()
+
+	engine, err := templates.NewEngine(templates.EngineMustache)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.RegisterTemplate("greeting", "Hello {{ProjectName}}"))
+
+	data := templates.NewTemplateData(&config.Config{
+		Metadata: config.Metadata{Name: "acme"},
+	})
+
+	out, err := engine.Render("greeting", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello acme", out)
+}
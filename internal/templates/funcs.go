@@ -0,0 +1,394 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// baseTemplateFuncs is the FuncMap every Go-engine template gets, in
+// addition to goTemplateFuncs' "slug": string manipulation, regex, list and
+// dict helpers, date formatting, default-value helpers, and filters over
+// TemplateData.AllContent, in the spirit of Hugo and consul-template's
+// built-in function libraries. A Renderer layers its own RegisterFunc
+// additions on top of this set; RenderString, ValidateTemplate and
+// GenerateHeader (which aren't tied to a Renderer instance) get this set
+// plus "slug" (goTemplateFuncs) and no RegisterFunc additions.
+func baseTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// String ops.
+		"lower":   strings.ToLower,
+		"upper":   strings.ToUpper,
+		"title":   titleCase,
+		"trim":    strings.TrimSpace,
+		"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"split":   func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":    func(sep string, items []string) string { return strings.Join(items, sep) },
+		"indent":  indent,
+		"nindent": func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"wrap":    wrap,
+		"quote":   strconv.Quote,
+
+		// Process environment.
+		"env": os.Getenv,
+
+		// Regex.
+		"regexMatch":      regexMatch,
+		"regexReplaceAll": regexReplaceAll,
+
+		// List ops, operating on a []any built by list or a concrete typed
+		// slice like TemplateData.AllContent alike.
+		"list":    list,
+		"first":   first,
+		"last":    last,
+		"reverse": reverse,
+		"uniq":    uniq,
+		"sortBy":  sortBy,
+
+		// Dict/map construction.
+		"dict":   dict,
+		"get":    get,
+		"hasKey": hasKey,
+
+		// Date formatting.
+		"now":        time.Now,
+		"dateFormat": func(layout string, t time.Time) string { return t.Format(layout) },
+
+		// Defaults and branching.
+		"default":  defaultValue,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+
+		// Filters over TemplateData.AllContent.
+		"filterByType":     filterByType,
+		"filterByPriority": filterByPriority,
+		"filterByTag":      filterByTag,
+		"groupByPriority":  groupByPriority,
+		"groupBySection":   groupBySection,
+		"sortRules":        sortRules,
+		"toc":              toc,
+
+		// Shell helpers, distinct from the handlebars engine's "shell" (which
+		// executes a command): shellQuote only escapes a value for safe
+		// interpolation into one, e.g. a for_each output's `file:` path.
+		"shellQuote": shellQuote,
+	}
+}
+
+// titleCase upper-cases the first letter of each whitespace-separated word,
+// e.g. "security rule" -> "Security Rule". strings.Title is deprecated (it
+// mishandles Unicode word boundaries); this is the simple ASCII-oriented
+// replacement templates actually need.
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r := []rune(word)
+		r[0] = []rune(strings.ToUpper(string(r[0])))[0]
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
+
+// indent prepends spaces worth of leading whitespace to every line of s.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap rewraps s to width columns, breaking only at whitespace (a word
+// longer than width is left on its own line rather than split) and
+// preserving existing blank lines, for templates composing a Markdown
+// output that should stay readable at a fixed line width (e.g. 80 columns).
+func wrap(width int, s string) string {
+	if width <= 0 {
+		return s
+	}
+
+	paragraphs := strings.Split(s, "\n")
+	for i, p := range paragraphs {
+		paragraphs[i] = wrapLine(width, p)
+	}
+	return strings.Join(paragraphs, "\n")
+}
+
+// wrapLine greedily word-wraps a single line (no embedded newlines) to width
+// columns.
+func wrapLine(width int, line string) string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	lineLen := 0
+	for i, word := range words {
+		switch {
+		case i == 0:
+			// First word always starts the first line.
+		case lineLen+1+len(word) > width:
+			b.WriteByte('\n')
+			lineLen = 0
+		default:
+			b.WriteByte(' ')
+			lineLen++
+		}
+		b.WriteString(word)
+		lineLen += len(word)
+	}
+	return b.String()
+}
+
+func regexMatch(pattern, s string) (bool, error) {
+	return regexp.MatchString(pattern, s)
+}
+
+func regexReplaceAll(pattern, repl, s string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
+	return re.ReplaceAllString(s, repl), nil
+}
+
+// list builds a []any from its arguments, for templates that need an
+// ad-hoc list to range over or pass to first/last/reverse/uniq/sortBy.
+func list(items ...any) []any {
+	return items
+}
+
+// first returns list's first element, or nil for an empty list.
+func first(list any) any {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return nil
+	}
+	return v.Index(0).Interface()
+}
+
+// last returns list's last element, or nil for an empty list.
+func last(list any) any {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return nil
+	}
+	return v.Index(v.Len() - 1).Interface()
+}
+
+// reverse returns a copy of list with its elements in reverse order.
+func reverse(list any) []any {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	out := make([]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		out[v.Len()-1-i] = v.Index(i).Interface()
+	}
+	return out
+}
+
+// uniq returns list's elements with later duplicates (by fmt.Sprint)
+// dropped, preserving first-seen order.
+func uniq(list any) []any {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	seen := map[string]bool{}
+	out := make([]any, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i).Interface()
+		key := fmt.Sprint(item)
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// sortBy sorts a copy of list by the named struct field (case-insensitive),
+// ascending. Unknown fields or a non-struct element leave the order
+// unchanged.
+func sortBy(field string, list any) []any {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	out := make([]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		out[i] = v.Index(i).Interface()
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return fmt.Sprint(fieldValue(out[i], field)) < fmt.Sprint(fieldValue(out[j], field))
+	})
+	return out
+}
+
+func fieldValue(item any, field string) any {
+	v := reflect.ValueOf(item)
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f := v.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, field) })
+	if !f.IsValid() {
+		return nil
+	}
+	return f.Interface()
+}
+
+// dict builds a map[string]any from alternating key/value arguments, e.g.
+// dict "a" 1 "b" 2, for templates that need ad-hoc structured data.
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict requires an even number of arguments, got %d", len(pairs))
+	}
+	m := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		m[key] = pairs[i+1]
+	}
+	return m, nil
+}
+
+func get(m map[string]any, key string) any {
+	return m[key]
+}
+
+func hasKey(m map[string]any, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// defaultValue returns def if val is its type's zero value, else val -
+// mirroring sprig's "default" for "{{ .Description | default "none" }}".
+func defaultValue(def, val any) any {
+	if val == nil || reflect.ValueOf(val).IsZero() {
+		return def
+	}
+	return val
+}
+
+// coalesce returns the first argument that isn't its type's zero value, or
+// nil if they all are.
+func coalesce(vals ...any) any {
+	for _, val := range vals {
+		if val != nil && !reflect.ValueOf(val).IsZero() {
+			return val
+		}
+	}
+	return nil
+}
+
+func ternary(cond bool, yes, no any) any {
+	if cond {
+		return yes
+	}
+	return no
+}
+
+// filterByType returns the items of the given Type ("rule" or "section").
+func filterByType(items []ContentItem, itemType string) []ContentItem {
+	var out []ContentItem
+	for _, item := range items {
+		if item.Type == itemType {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// filterByPriority returns the items whose Priority is >= min.
+func filterByPriority(items []ContentItem, min int) []ContentItem {
+	var out []ContentItem
+	for _, item := range items {
+		if item.Priority >= min {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// groupByPriority buckets items by their Priority.
+func groupByPriority(items []ContentItem) map[int][]ContentItem {
+	groups := make(map[int][]ContentItem)
+	for _, item := range items {
+		groups[item.Priority] = append(groups[item.Priority], item)
+	}
+	return groups
+}
+
+// filterByTag returns the items whose Tags includes tag.
+func filterByTag(items []ContentItem, tag string) []ContentItem {
+	var out []ContentItem
+	for _, item := range items {
+		for _, t := range item.Tags {
+			if t == tag {
+				out = append(out, item)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// groupBySection buckets each rule item under the Title of the nearest
+// preceding section item in items (its AllContent order, so normally
+// priority-then-title), for templates that want to render rules nested
+// under their section headers rather than as one flat list. Rules before
+// any section, or with no section in items at all, group under "".
+func groupBySection(items []ContentItem) map[string][]ContentItem {
+	groups := make(map[string][]ContentItem)
+	current := ""
+	for _, item := range items {
+		if !item.IsRule {
+			current = item.Title
+			continue
+		}
+		groups[current] = append(groups[current], item)
+	}
+	return groups
+}
+
+// sortRules returns a copy of items sorted by Priority (descending) then
+// Title (ascending) - AllContent's own default order, reapplied after a
+// filter like filterByTag or filterByType has narrowed it out of that order.
+func sortRules(items []ContentItem) []ContentItem {
+	out := make([]ContentItem, len(items))
+	copy(out, items)
+	sortContent(out)
+	return out
+}
+
+// toc renders items as a Markdown table of contents: one "- [Title](#slug)"
+// line per entry, linking to the anchor a Markdown renderer would generate
+// for a "## Title" heading.
+func toc(items []ContentItem) string {
+	var b strings.Builder
+	for _, item := range items {
+		b.WriteString("- [" + item.Title + "](#" + slug(item.Title) + ")\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// shellQuote wraps s in single quotes for safe interpolation into a shell
+// command, escaping any embedded single quote - unlike the handlebars
+// engine's "shell" helper, this only escapes a value, it doesn't run
+// anything.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
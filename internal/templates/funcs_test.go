@@ -0,0 +1,220 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/templates"
+)
+
+func TestRenderString_StringFuncs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tmpl     string
+		expected string
+	}{
+		{`{{"HELLO" | lower}}`, "hello"},
+		{`{{"hello" | upper}}`, "HELLO"},
+		{`{{"security rule" | title}}`, "Security Rule"},
+		{`{{"  hi  " | trim}}`, "hi"},
+		{`{{"a-b-a" | replace "a" "x"}}`, "x-b-x"},
+		{`{{join "," (split "," "a,b,c")}}`, "a,b,c"},
+		{`{{indent 2 "line"}}`, "  line"},
+		{`{{nindent 2 "line"}}`, "\n  line"},
+		{`{{quote "hi"}}`, `"hi"`},
+	}
+
+	for _, tt := range tests {
+		result, err := templates.RenderString(tt.tmpl, &templates.TemplateData{})
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, result)
+	}
+}
+
+func TestRenderString_WrapFunc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tmpl     string
+		expected string
+	}{
+		{`{{wrap 10 "one two three four"}}`, "one two\nthree four"},
+		{`{{wrap 0 "one two three four"}}`, "one two three four"},
+		{`{{wrap 3 "verylongword"}}`, "verylongword"},
+	}
+
+	for _, tt := range tests {
+		result, err := templates.RenderString(tt.tmpl, &templates.TemplateData{})
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, result)
+	}
+}
+
+func TestRenderString_EnvFunc(t *testing.T) {
+	t.Setenv("AI_RULEZ_FUNCS_TEST_VAR", "hello")
+
+	result, err := templates.RenderString(`{{env "AI_RULEZ_FUNCS_TEST_VAR"}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", result)
+}
+
+func TestRenderString_RegexFuncs(t *testing.T) {
+	t.Parallel()
+
+	matched, err := templates.RenderString(`{{regexMatch "^foo" "foobar"}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "true", matched)
+
+	replaced, err := templates.RenderString(`{{regexReplaceAll "o+" "0" "foobar"}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "f0bar", replaced)
+}
+
+func TestRenderString_ListFuncs(t *testing.T) {
+	t.Parallel()
+
+	result, err := templates.RenderString(`{{first (list 1 2 3)}}-{{last (list 1 2 3)}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "1-3", result)
+
+	reversed, err := templates.RenderString(`{{range reverse (list 1 2 3)}}{{.}}{{end}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "321", reversed)
+
+	deduped, err := templates.RenderString(`{{range uniq (list 1 1 2 2 3)}}{{.}}{{end}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "123", deduped)
+}
+
+func TestRenderString_DictFuncs(t *testing.T) {
+	t.Parallel()
+
+	result, err := templates.RenderString(`{{$d := dict "a" "1"}}{{get $d "a"}}-{{hasKey $d "b"}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "1-false", result)
+}
+
+func TestRenderString_DefaultFuncs(t *testing.T) {
+	t.Parallel()
+
+	withDefault, err := templates.RenderString(`{{"" | default "fallback"}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", withDefault)
+
+	ternary, err := templates.RenderString(`{{ternary true "yes" "no"}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", ternary)
+}
+
+func TestRenderString_ContentFilters(t *testing.T) {
+	t.Parallel()
+
+	data := &templates.TemplateData{
+		AllContent: []templates.ContentItem{
+			{Type: "rule", Title: "A", Priority: 5, IsRule: true},
+			{Type: "section", Title: "B", Priority: 1},
+		},
+	}
+
+	result, err := templates.RenderString(`{{range filterByType .AllContent "rule"}}{{.Title}}{{end}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "A", result)
+
+	highPriority, err := templates.RenderString(`{{range filterByPriority .AllContent 2}}{{.Title}}{{end}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "A", highPriority)
+}
+
+func TestRenderString_SortByAndGroupByPriority(t *testing.T) {
+	t.Parallel()
+
+	data := &templates.TemplateData{
+		AllContent: []templates.ContentItem{
+			{Type: "rule", Title: "B", Priority: 1},
+			{Type: "rule", Title: "A", Priority: 5},
+		},
+	}
+
+	sorted, err := templates.RenderString(`{{range sortBy "Title" .AllContent}}{{.Title}}{{end}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "AB", sorted)
+
+	grouped, err := templates.RenderString(`{{$g := groupByPriority .AllContent}}{{len (index $g 5)}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "1", grouped)
+}
+
+func TestRenderer_RegisterFunc(t *testing.T) {
+	t.Parallel()
+
+	renderer := templates.NewRenderer()
+	renderer.RegisterFunc("shout", func(s string) string { return s + "!!!" })
+
+	require.NoError(t, renderer.RegisterTemplate("shout-test", `{{shout .ProjectName}}`))
+	result, err := renderer.Render("shout-test", &templates.TemplateData{ProjectName: "ai-rulez"})
+	require.NoError(t, err)
+	assert.Equal(t, "ai-rulez!!!", result)
+}
+
+func TestRenderer_RegisterHelper(t *testing.T) {
+	t.Parallel()
+
+	renderer := templates.NewRenderer()
+	require.NoError(t, renderer.RegisterHelper("shout", func(s string) string { return s + "!!!" }))
+
+	require.NoError(t, renderer.RegisterTemplate("shout-test", `{{shout .ProjectName}}`))
+	result, err := renderer.Render("shout-test", &templates.TemplateData{ProjectName: "ai-rulez"})
+	require.NoError(t, err)
+	assert.Equal(t, "ai-rulez!!!", result)
+
+	assert.Error(t, renderer.RegisterHelper("nilFn", nil))
+}
+
+func TestRenderString_FilterByTagGroupBySectionSortRules(t *testing.T) {
+	t.Parallel()
+
+	data := &templates.TemplateData{
+		AllContent: []templates.ContentItem{
+			{Type: "section", Title: "Style", IsRule: false},
+			{Type: "rule", Title: "B", Priority: 1, IsRule: true, Tags: []string{"security"}},
+			{Type: "rule", Title: "A", Priority: 5, IsRule: true},
+		},
+	}
+
+	tagged, err := templates.RenderString(`{{range filterByTag .AllContent "security"}}{{.Title}}{{end}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "B", tagged)
+
+	grouped, err := templates.RenderString(`{{$g := groupBySection .AllContent}}{{len (index $g "Style")}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "2", grouped)
+
+	sorted, err := templates.RenderString(`{{range sortRules .AllContent}}{{.Title}}{{end}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "ABStyle", sorted)
+}
+
+func TestToc(t *testing.T) {
+	t.Parallel()
+
+	data := &templates.TemplateData{
+		AllContent: []templates.ContentItem{
+			{Type: "rule", Title: "Security Rule", IsRule: true},
+		},
+	}
+
+	result, err := templates.RenderString(`{{toc .AllContent}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "- [Security Rule](#security-rule)", result)
+}
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+
+	result, err := templates.RenderString(`{{shellQuote "it's a test"}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, `'it'\''s a test'`, result)
+}
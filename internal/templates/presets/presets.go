@@ -0,0 +1,44 @@
+// Package presets embeds first-party output templates for popular AI
+// coding assistants - claude, cursor, windsurf, copilot-instructions,
+// aider, and continue - so an output can reference one by name (e.g.
+// `template: "cursor"`) with no local template file, the same way the
+// Databricks CLI ships its built-in bundle templates via go:embed.
+package presets
+
+import (
+	"embed"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FS holds every *.tmpl preset, keyed by the file's base name (without the
+// extension) once read through templates.Renderer.registerPresetTemplates.
+//
+//go:embed *.tmpl
+var FS embed.FS
+
+// Names returns the sorted list of preset names, e.g. "claude", "cursor".
+func Names() []string {
+	entries, err := FS.ReadDir(".")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Read returns the raw template source of the preset named name, and
+// whether a preset by that name exists.
+func Read(name string) (string, bool) {
+	content, err := FS.ReadFile(name + ".tmpl")
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
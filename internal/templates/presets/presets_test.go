@@ -0,0 +1,32 @@
+package presets_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Goldziher/ai-rulez/internal/templates/presets"
+)
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	names := presets.Names()
+	assert.Contains(t, names, "claude")
+	assert.Contains(t, names, "cursor")
+	assert.Contains(t, names, "windsurf")
+	assert.Contains(t, names, "copilot-instructions")
+	assert.Contains(t, names, "aider")
+	assert.Contains(t, names, "continue")
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	content, ok := presets.Read("cursor")
+	assert.True(t, ok)
+	assert.Contains(t, content, "alwaysApply")
+
+	_, ok = presets.Read("does-not-exist")
+	assert.False(t, ok)
+}
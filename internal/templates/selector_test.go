@@ -0,0 +1,89 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/templates"
+)
+
+func TestFilterBySelector_EmptyExprIsNoOp(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test Project"},
+		Rules: []config.Rule{
+			{Name: "rule1", Content: "content1", Tags: []string{"security"}},
+		},
+	}
+	data := templates.NewTemplateData(cfg)
+
+	filtered, err := templates.FilterBySelector(data, "")
+	require.NoError(t, err)
+	assert.Same(t, data, filtered)
+}
+
+func TestFilterBySelector_FiltersRulesAndSectionsByTag(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test Project"},
+		Rules: []config.Rule{
+			{Name: "security rule", Content: "lock it down", Tags: []string{"security"}},
+			{Name: "frontend rule", Content: "use hooks", Tags: []string{"frontend"}},
+		},
+		Sections: []config.Section{
+			{Title: "security section", Content: "threat model", Tags: []string{"security"}},
+			{Title: "frontend section", Content: "component style", Tags: []string{"frontend"}},
+		},
+	}
+	data := templates.NewTemplateData(cfg)
+
+	filtered, err := templates.FilterBySelector(data, "security")
+	require.NoError(t, err)
+
+	require.Len(t, filtered.Rules, 1)
+	assert.Equal(t, "security rule", filtered.Rules[0].Name)
+	require.Len(t, filtered.Sections, 1)
+	assert.Equal(t, "security section", filtered.Sections[0].Title)
+	assert.Equal(t, 1, filtered.RuleCount)
+	assert.Equal(t, 1, filtered.SectionCount)
+
+	require.Len(t, filtered.AllContent, 2)
+	for _, item := range filtered.AllContent {
+		assert.Contains(t, item.Title, "security")
+	}
+}
+
+func TestFilterBySelector_InvalidExprReturnsError(t *testing.T) {
+	t.Parallel()
+
+	data := templates.NewTemplateData(&config.Config{Metadata: config.Metadata{Name: "Test Project"}})
+
+	_, err := templates.FilterBySelector(data, "security and (")
+	assert.Error(t, err)
+}
+
+func TestFilterBySelector_FiltersByPriorityAndSetsMatched(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "Test Project"},
+		Rules: []config.Rule{
+			{Name: "terse rule", Content: "keep it short", Priority: 9},
+			{Name: "verbose rule", Content: "explain everything", Priority: 2},
+		},
+	}
+	data := templates.NewTemplateData(cfg)
+
+	filtered, err := templates.FilterBySelector(data, "priority > 5")
+	require.NoError(t, err)
+
+	require.Len(t, filtered.Rules, 1)
+	assert.Equal(t, "terse rule", filtered.Rules[0].Name)
+	assert.Equal(t, filtered.Rules, filtered.Matched)
+	assert.Equal(t, 1, filtered.SkippedRuleCount)
+}
@@ -3,12 +3,16 @@ package templates
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/selector"
+	"github.com/Goldziher/ai-rulez/internal/templates/presets"
 )
 
 // ContentItem represents a unified content item (rule or section).
@@ -18,6 +22,7 @@ type ContentItem struct {
 	Priority int
 	Content  string
 	IsRule   bool
+	Tags     []string // The originating rule/section's Tags, for filterByTag.
 }
 
 // TemplateData contains all variables available for template substitution.
@@ -31,9 +36,24 @@ type TemplateData struct {
 	Timestamp    time.Time
 	RuleCount    int
 	SectionCount int
+	// Matched and SkippedRuleCount describe the effect of an
+	// Output/ComposeFragment Selector: Matched is the Rules that passed
+	// it (nil if no Selector was applied, equal to Rules once one was),
+	// and SkippedRuleCount is how many rules it filtered out. Set by
+	// FilterBySelector; templates use these to render match summaries.
+	Matched          []config.Rule
+	SkippedRuleCount int
 	// Header generation fields
 	ConfigFile string // Source configuration file name
 	OutputFile string // Target output file name
+	// ConfigHeader and ConfigHeaderFile carry the project-wide Config.Header
+	// / Config.HeaderFile default down to each output's header resolution;
+	// nil/empty when the project doesn't define one. See Output.Header.
+	ConfigHeader     *config.HeaderValue
+	ConfigHeaderFile string
+	// Item is set by a for_each output to the single *config.Rule or
+	// *config.Section currently being rendered; nil otherwise.
+	Item any
 }
 
 // NewTemplateData creates template data from a config.
@@ -66,6 +86,7 @@ func NewTemplateData(cfg *config.Config) *TemplateData {
 			Priority: rule.Priority,
 			Content:  rule.Content,
 			IsRule:   true,
+			Tags:     rule.Tags,
 		})
 	}
 
@@ -77,6 +98,7 @@ func NewTemplateData(cfg *config.Config) *TemplateData {
 			Priority: section.Priority,
 			Content:  section.Content,
 			IsRule:   false,
+			Tags:     section.Tags,
 		})
 	}
 
@@ -88,21 +110,209 @@ func NewTemplateData(cfg *config.Config) *TemplateData {
 	sortSectionsByPriority(sortedSections)
 
 	return &TemplateData{
-		ProjectName:  cfg.Metadata.Name,
-		Version:      cfg.Metadata.Version,
-		Description:  cfg.Metadata.Description,
-		Rules:        sortedRules,
-		Sections:     sortedSections,
-		AllContent:   allContent,
-		Timestamp:    time.Now(),
-		RuleCount:    len(allRules),
-		SectionCount: len(allSections),
+		ProjectName:      cfg.Metadata.Name,
+		Version:          cfg.Metadata.Version,
+		Description:      cfg.Metadata.Description,
+		Rules:            sortedRules,
+		Sections:         sortedSections,
+		AllContent:       allContent,
+		Timestamp:        time.Now(),
+		RuleCount:        len(allRules),
+		SectionCount:     len(allSections),
+		ConfigHeader:     cfg.Header,
+		ConfigHeaderFile: cfg.HeaderFile,
 	}
 }
 
+// FilterForOutput narrows data to the rules and sections named by output's
+// Rules and Sections allow-lists (matched against each rule's ID or Name,
+// and each section's ID or Title). An empty allow-list leaves the
+// corresponding content untouched, so outputs without Rules/Sections set
+// see all content exactly as before. Used to produce output variants (e.g.
+// a review-only CLAUDE.review.md) via extends without duplicating content.
+func FilterForOutput(data *TemplateData, output config.Output) *TemplateData {
+	if len(output.Rules) == 0 && len(output.Sections) == 0 {
+		return data
+	}
+
+	ruleNames := toSet(output.Rules)
+	sectionNames := toSet(output.Sections)
+
+	filteredRules := data.Rules
+	if len(ruleNames) > 0 {
+		filteredRules = make([]config.Rule, 0, len(data.Rules))
+		for _, rule := range data.Rules {
+			if ruleNames[rule.ID] || ruleNames[rule.Name] {
+				filteredRules = append(filteredRules, rule)
+			}
+		}
+	}
+
+	filteredSections := data.Sections
+	if len(sectionNames) > 0 {
+		filteredSections = make([]config.Section, 0, len(data.Sections))
+		for _, section := range data.Sections {
+			if sectionNames[section.ID] || sectionNames[section.Title] {
+				filteredSections = append(filteredSections, section)
+			}
+		}
+	}
+
+	keptRuleTitles := make(map[string]bool, len(filteredRules))
+	for _, rule := range filteredRules {
+		keptRuleTitles[rule.Name] = true
+	}
+	keptSectionTitles := make(map[string]bool, len(filteredSections))
+	for _, section := range filteredSections {
+		keptSectionTitles[section.Title] = true
+	}
+
+	filteredContent := make([]ContentItem, 0, len(filteredRules)+len(filteredSections))
+	for _, item := range data.AllContent {
+		if item.IsRule {
+			if len(ruleNames) == 0 || keptRuleTitles[item.Title] {
+				filteredContent = append(filteredContent, item)
+			}
+		} else if len(sectionNames) == 0 || keptSectionTitles[item.Title] {
+			filteredContent = append(filteredContent, item)
+		}
+	}
+
+	filtered := *data
+	filtered.Rules = filteredRules
+	filtered.Sections = filteredSections
+	filtered.AllContent = filteredContent
+	filtered.RuleCount = len(filteredRules)
+	filtered.SectionCount = len(filteredSections)
+	return &filtered
+}
+
+// FilterBySelector narrows data to the rules, sections, and AllContent
+// items whose own Tags satisfy expr (an Output.Selector or
+// ComposeFragment.Selector expression, e.g. `security and not draft`),
+// compiled once via selector.Compile and evaluated per item against a
+// Context built solely from that item's Tags - unlike the `when:` fields'
+// selector.Evaluate, this never sees the --tag/AI_RULEZ_TAGS CLI context.
+// An empty expr leaves data untouched, so an output without Selector set
+// is unaffected. Meant to run on top of FilterForOutput's ID/name
+// allow-list filtering.
+func FilterBySelector(data *TemplateData, expr string) (*TemplateData, error) {
+	if expr == "" {
+		return data, nil
+	}
+
+	compiled, err := selector.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredRules := make([]config.Rule, 0, len(data.Rules))
+	for _, rule := range data.Rules {
+		if compiled.Eval(tagContext(rule.Tags, rule.Priority)) {
+			filteredRules = append(filteredRules, rule)
+		}
+	}
+
+	filteredSections := make([]config.Section, 0, len(data.Sections))
+	for _, section := range data.Sections {
+		if compiled.Eval(tagContext(section.Tags, section.Priority)) {
+			filteredSections = append(filteredSections, section)
+		}
+	}
+
+	keptRuleTitles := make(map[string]bool, len(filteredRules))
+	for _, rule := range filteredRules {
+		keptRuleTitles[rule.Name] = true
+	}
+	keptSectionTitles := make(map[string]bool, len(filteredSections))
+	for _, section := range filteredSections {
+		keptSectionTitles[section.Title] = true
+	}
+
+	filteredContent := make([]ContentItem, 0, len(filteredRules)+len(filteredSections))
+	for _, item := range data.AllContent {
+		if item.IsRule {
+			if keptRuleTitles[item.Title] {
+				filteredContent = append(filteredContent, item)
+			}
+		} else if keptSectionTitles[item.Title] {
+			filteredContent = append(filteredContent, item)
+		}
+	}
+
+	filtered := *data
+	filtered.Rules = filteredRules
+	filtered.Sections = filteredSections
+	filtered.AllContent = filteredContent
+	filtered.RuleCount = len(filteredRules)
+	filtered.SectionCount = len(filteredSections)
+	filtered.Matched = filteredRules
+	filtered.SkippedRuleCount = len(data.Rules) - len(filteredRules)
+	return &filtered, nil
+}
+
+// tagContext builds a selector.Context whose known tags are those in tags
+// and whose only fact is "priority" (so expressions like `priority > 5`
+// work), for evaluating an Output/ComposeFragment Selector against a
+// single rule or section.
+func tagContext(tags []string, priority int) *selector.Context {
+	ctx := selector.NewContext()
+	for _, tag := range tags {
+		ctx.Tags[tag] = true
+	}
+	ctx.Facts["priority"] = strconv.Itoa(priority)
+	return ctx
+}
+
+// goTemplateFuncs are available to every Go-engine template: the built-ins,
+// custom-registered templates, and for_each output `file:` path templates -
+// baseTemplateFuncs' string/regex/list/dict/date/default helpers and
+// AllContent filters, plus slug.
+func goTemplateFuncs() template.FuncMap {
+	funcs := baseTemplateFuncs()
+	funcs["slug"] = slug
+	return funcs
+}
+
+// slug lowercases s and replaces every run of non-alphanumeric characters
+// with a single hyphen, trimming leading/trailing hyphens - e.g. "Security
+// Rule!" -> "security-rule". Used by for_each outputs to turn a rule or
+// section name into a filesystem-safe file name.
+func slug(s string) string {
+	var b strings.Builder
+	prevHyphen := true // true so a leading run of non-alphanumerics is dropped, not hyphenated
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			prevHyphen = false
+		case !prevHyphen:
+			b.WriteByte('-')
+			prevHyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// toSet builds a lookup set from names, skipping the allocation entirely
+// for an empty or nil input.
+func toSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
 // Renderer handles template rendering with different output formats.
 type Renderer struct {
 	templates map[string]*template.Template
+	// custom holds functions added via RegisterFunc, layered on top of
+	// goTemplateFuncs for every template this Renderer registers.
+	custom template.FuncMap
 }
 
 // NewRenderer creates a new template renderer with built-in templates.
@@ -117,6 +327,45 @@ func NewRenderer() *Renderer {
 	return r
 }
 
+// RegisterFunc adds fn to r's FuncMap under name, available to every
+// template r registers from this point on (earlier-registered templates
+// are unaffected - re-register them to pick it up). Lets a Go program
+// embedding this module extend custom templates beyond goTemplateFuncs'
+// built-in set, the same way a Renderer is itself extended by Go code
+// rather than config.
+func (r *Renderer) RegisterFunc(name string, fn any) {
+	if r.custom == nil {
+		r.custom = template.FuncMap{}
+	}
+	r.custom[name] = fn
+}
+
+// RegisterHelper is RegisterFunc with an error return, satisfying
+// TemplateEngine's RegisterHelper method so a *Renderer can be extended the
+// same way regardless of which engine an output picked. fn must be a
+// non-nil function; Go's text/template panics on anything else when the
+// next template is registered, which this surfaces as an error instead.
+func (r *Renderer) RegisterHelper(name string, fn any) error {
+	if fn == nil || reflect.ValueOf(fn).Kind() != reflect.Func {
+		return fmt.Errorf("helper %q: fn must be a non-nil function", name)
+	}
+	r.RegisterFunc(name, fn)
+	return nil
+}
+
+// funcMap returns the FuncMap this Renderer's templates should parse with:
+// goTemplateFuncs' built-ins, overridden by any RegisterFunc additions.
+func (r *Renderer) funcMap() template.FuncMap {
+	if len(r.custom) == 0 {
+		return goTemplateFuncs()
+	}
+	funcs := goTemplateFuncs()
+	for name, fn := range r.custom {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
 // Render processes a template with the given data and returns the result.
 func (r *Renderer) Render(format string, data *TemplateData) (string, error) {
 	tmpl, exists := r.templates[format]
@@ -134,12 +383,24 @@ func (r *Renderer) Render(format string, data *TemplateData) (string, error) {
 
 // RegisterTemplate adds a custom template for a format.
 func (r *Renderer) RegisterTemplate(format, templateStr string) error {
-	tmpl, err := template.New(format).Parse(templateStr)
+	return r.RegisterTemplateWithDelims(format, templateStr, "", "")
+}
+
+// RegisterTemplateWithDelims is RegisterTemplate, additionally accepting Go
+// template action delimiters (e.g. "<%", "%>") in place of the "{{"/"}}"
+// default; left and right empty keeps Go's default delimiters.
+func (r *Renderer) RegisterTemplateWithDelims(format, templateStr, left, right string) error {
+	tmpl := template.New(format).Funcs(r.funcMap())
+	if left != "" || right != "" {
+		tmpl = tmpl.Delims(left, right)
+	}
+
+	parsed, err := tmpl.Parse(templateStr)
 	if err != nil {
 		return fmt.Errorf("failed to parse template for %s: %w", format, err)
 	}
 
-	r.templates[format] = tmpl
+	r.templates[format] = parsed
 	return nil
 }
 
@@ -219,11 +480,25 @@ All content is sorted by priority (highest first), then alphabetically by title.
 	// Register built-in templates (ignore errors since they're hardcoded and valid)
 	_ = r.RegisterTemplate("default", defaultTemplate)
 	_ = r.RegisterTemplate("documentation", documentationTemplate)
+
+	r.registerPresetTemplates()
+}
+
+// registerPresetTemplates registers every first-party preset embedded in
+// internal/templates/presets (claude, cursor, windsurf, ...) under its
+// file name, so an output can reference one via `template: "cursor"` with
+// no local template file.
+func (r *Renderer) registerPresetTemplates() {
+	for _, name := range presets.Names() {
+		if content, ok := presets.Read(name); ok {
+			_ = r.RegisterTemplate(name, content)
+		}
+	}
 }
 
 // ValidateTemplate checks if a template string is valid.
 func ValidateTemplate(templateStr string) error {
-	_, err := template.New("validation").Parse(templateStr)
+	_, err := template.New("validation").Funcs(goTemplateFuncs()).Parse(templateStr)
 	if err != nil {
 		return fmt.Errorf("invalid template syntax: %w", err)
 	}
@@ -232,13 +507,25 @@ func ValidateTemplate(templateStr string) error {
 
 // RenderString is a utility function to render a template string directly.
 func RenderString(templateStr string, data *TemplateData) (string, error) {
-	tmpl, err := template.New("inline").Parse(templateStr)
+	return RenderStringWithDelims(templateStr, data, "", "")
+}
+
+// RenderStringWithDelims is RenderString, additionally accepting Go template
+// action delimiters (e.g. "<%", "%>"); left and right empty keeps Go's
+// default "{{"/"}}" delimiters.
+func RenderStringWithDelims(templateStr string, data *TemplateData, left, right string) (string, error) {
+	tmpl := template.New("inline").Funcs(goTemplateFuncs())
+	if left != "" || right != "" {
+		tmpl = tmpl.Delims(left, right)
+	}
+
+	parsed, err := tmpl.Parse(templateStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
 	var buf strings.Builder
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := parsed.Execute(&buf, data); err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
@@ -275,7 +562,7 @@ Learn more: https://github.com/Goldziher/ai-rulez
 
 `
 
-	tmpl, err := template.New("header").Parse(headerTemplate)
+	tmpl, err := template.New("header").Funcs(goTemplateFuncs()).Parse(headerTemplate)
 	if err != nil {
 		// Fallback to simple header if template parsing fails
 		return fmt.Sprintf(`<!-- Generated by ai-rulez from %s - DO NOT EDIT DIRECTLY -->
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Goldziher/ai-rulez/internal/config"
 	"github.com/Goldziher/ai-rulez/internal/templates"
+	"github.com/Goldziher/ai-rulez/internal/templates/presets"
 )
 
 func TestNewTemplateData(t *testing.T) {
@@ -140,6 +141,21 @@ func TestRenderer_RegisterTemplate_InvalidSyntax(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to parse template")
 }
 
+func TestRenderer_RegisterTemplateWithDelims(t *testing.T) {
+	t.Parallel()
+
+	renderer := templates.NewRenderer()
+
+	// Literal "{{ }}" survives untouched since the action delimiters are "<%"/"%>".
+	customTemplate := "Hello <%.ProjectName%>, literal braces: {{ not a template action }}"
+	err := renderer.RegisterTemplateWithDelims("custom", customTemplate, "<%", "%>")
+	require.NoError(t, err)
+
+	result, err := renderer.Render("custom", &templates.TemplateData{ProjectName: "Test"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Test, literal braces: {{ not a template action }}", result)
+}
+
 func TestRenderer_GetSupportedFormats(t *testing.T) {
 	t.Parallel()
 
@@ -248,6 +264,24 @@ func TestRenderString(t *testing.T) {
 	}
 }
 
+func TestRenderString_SlugFunc(t *testing.T) {
+	t.Parallel()
+
+	result, err := templates.RenderString(`{{"Security Rule!" | slug}}`, &templates.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "security-rule", result)
+}
+
+func TestRenderStringWithDelims(t *testing.T) {
+	t.Parallel()
+
+	data := &templates.TemplateData{ProjectName: "Test Project"}
+
+	result, err := templates.RenderStringWithDelims("Project: <%.ProjectName%>, literal: {{.Unused}}", data, "<%", "%>")
+	require.NoError(t, err)
+	assert.Equal(t, "Project: Test Project, literal: {{.Unused}}", result)
+}
+
 func TestBuiltinTemplates_NoErrors(t *testing.T) {
 	t.Parallel()
 
@@ -302,6 +336,35 @@ func TestBuiltinTemplates_EmptyRules(t *testing.T) {
 	}
 }
 
+func TestPresetTemplates_NoErrors(t *testing.T) {
+	t.Parallel()
+
+	renderer := templates.NewRenderer()
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{
+			Name:        "Test",
+			Version:     "1.0.0",
+			Description: "Description",
+		},
+		Rules: []config.Rule{
+			{Name: "Rule 1", Priority: 10, Content: "Content 1"},
+		},
+		Sections: []config.Section{
+			{Title: "Section 1", Content: "Section content"},
+		},
+	}
+	data := templates.NewTemplateData(cfg)
+
+	for _, format := range presets.Names() {
+		t.Run(format, func(t *testing.T) {
+			result, err := renderer.Render(format, data)
+			require.NoError(t, err)
+			assert.Contains(t, result, "Content 1")
+		})
+	}
+}
+
 func TestGenerateHeader(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Goldziher/ai-rulez/internal/lint"
+)
+
+func TestLintCommandExists(t *testing.T) {
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use == "lint [config-file]" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("lint command not found in root command")
+	}
+}
+
+func TestLintShouldFail(t *testing.T) {
+	warningOnly := []lint.Finding{{Severity: lint.SeverityWarning}}
+	errorOnly := []lint.Finding{{Severity: lint.SeverityError}}
+
+	if lintShouldFail(warningOnly, "error") {
+		t.Error("lintShouldFail(warning, \"error\") = true, want false")
+	}
+	if !lintShouldFail(warningOnly, "warning") {
+		t.Error("lintShouldFail(warning, \"warning\") = false, want true")
+	}
+	if !lintShouldFail(errorOnly, "warning") {
+		t.Error("lintShouldFail(error, \"warning\") = false, want true")
+	}
+	if !lintShouldFail(errorOnly, "error") {
+		t.Error("lintShouldFail(error, \"error\") = false, want true")
+	}
+}
+
+func TestPrintLintFindingsUnknownFormat(t *testing.T) {
+	if err := printLintFindings(nil, "xml"); err == nil {
+		t.Error("printLintFindings() expected an error for an unknown format")
+	}
+}
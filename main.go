@@ -2,21 +2,41 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/Goldziher/ai-rulez/internal/cache"
 	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/config/allconfig"
+	"github.com/Goldziher/ai-rulez/internal/diffutil"
 	"github.com/Goldziher/ai-rulez/internal/generator"
 	"github.com/Goldziher/ai-rulez/internal/gitignore"
+	"github.com/Goldziher/ai-rulez/internal/history"
+	"github.com/Goldziher/ai-rulez/internal/lint"
+	"github.com/Goldziher/ai-rulez/internal/output"
+	"github.com/Goldziher/ai-rulez/internal/rulectx"
+	"github.com/Goldziher/ai-rulez/internal/scaffold"
+	"github.com/Goldziher/ai-rulez/internal/selector"
+	"github.com/Goldziher/ai-rulez/internal/templates/presets"
+	"github.com/fsnotify/fsnotify"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
 	"github.com/spf13/viper"
 )
 
@@ -28,7 +48,22 @@ var (
 	recursive       bool
 	dryRun          bool
 	updateGitignore bool
-	rootCmd         = &cobra.Command{
+	updateIncludes  bool
+	tags            []string
+	listTemplates   bool
+	excludes        []string
+	setOverrides    []string
+	contextName     string
+	outputFormat    string
+	noCache         bool
+	maxAgeOverride  string
+	watchFlag       bool
+	// mcpStorage is the Storage MCP mutate handlers (add/update/delete
+	// rule, section, output) load and save through, instead of calling
+	// config.LoadConfig/SaveConfig directly - swappable so an embedder can
+	// plug in a different backend (e.g. an in-memory one for tests).
+	mcpStorage config.Storage = config.NewFileStorage()
+	rootCmd                   = &cobra.Command{
 		Use:     "ai-rulez",
 		Version: Version,
 		Short:   "A CLI tool for managing AI assistant rules",
@@ -48,17 +83,189 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ai-rulez.yaml)")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "Name of a saved context (see 'ai-rulez context') to resolve the config file from, overriding the active context")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format for listing/inspection commands: text, json, yaml, or table")
 
 	// Add commands
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(docsCmd)
 	rootCmd.AddCommand(initCmd)
+	templatesCmd.AddCommand(templatesAddCmd)
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	rootCmd.AddCommand(templatesCmd)
 	rootCmd.AddCommand(mcpCmd)
+	listCmd.AddCommand(listRuleCmd)
+	listCmd.AddCommand(listSectionCmd)
+	listCmd.AddCommand(listOutputCmd)
+	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(listProfilesCmd)
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextShowCmd)
+	contextCmd.AddCommand(contextAddCmd)
+	contextCmd.AddCommand(contextRemoveCmd)
+	rootCmd.AddCommand(contextCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+	modulesCmd.AddCommand(modulesUpdateCmd)
+	rootCmd.AddCommand(modulesCmd)
+
+	configCmd.AddCommand(configPrintCmd)
+	configCmd.AddCommand(configReferenceCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// resolveConfigFile returns the config file path a command should operate
+// on when the user didn't pass one explicitly: --context (if set) or the
+// active context's ConfigFile (see internal/rulectx), falling back to
+// config.FindConfigFile(startDir).
+func resolveConfigFile(startDir string) (string, error) {
+	if contextName != "" {
+		ctx, err := rulectx.Resolve(contextName)
+		if err != nil {
+			return "", err
+		}
+		return ctx.ConfigFile, nil
+	}
+
+	active, err := rulectx.Active()
+	if err != nil {
+		return "", err
+	}
+	if active != nil && active.ConfigFile != "" {
+		return active.ConfigFile, nil
+	}
+
+	return config.FindConfigFile(startDir)
+}
+
+// resolveMCPConfigFile is resolveConfigFile's MCP-tool counterpart: an
+// explicit config_file argument wins, then the request's context
+// argument, then the active context, then config.FindConfigFile(".").
+func resolveMCPConfigFile(request mcp.CallToolRequest) (string, error) {
+	if configFile := request.GetString("config_file", ""); configFile != "" {
+		return configFile, nil
+	}
+	if ctxName := request.GetString("context", ""); ctxName != "" {
+		ctx, err := rulectx.Resolve(ctxName)
+		if err != nil {
+			return "", err
+		}
+		return ctx.ConfigFile, nil
+	}
+	return resolveConfigFile(".")
+}
+
+// newPrinter returns the output.Printer for the global --output/-o flag.
+func newPrinter() (output.Printer, error) {
+	return output.New(outputFormat)
+}
+
+// mcpPrint renders v per the MCP request's "format" argument (json, yaml,
+// or table; defaults to "json" to match every tool's pre-existing
+// behavior) and wraps it as a text tool result.
+func mcpPrint(request mcp.CallToolRequest, v any) (*mcp.CallToolResult, error) {
+	printer, err := output.New(request.GetString("format", "json"))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var buf bytes.Buffer
+	if err := printer.Print(&buf, v); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(buf.String()), nil
+}
+
+// mcpActor identifies who's calling an MCP tool, for the history
+// journal's Actor field: the client session id when the transport
+// exposes one, or "unknown" for a bare call without session context.
+func mcpActor(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return "unknown"
+}
+
+// saveWithHistory snapshots configFile's pre-mutation bytes and records a
+// history.Entry for op/args before saving cfg through mcpStorage, so
+// every MCP-driven add/update/delete is reversible via handleUndo and
+// handleRedo. History bookkeeping is best-effort: a failure recording it
+// is reported to stderr but never turns an otherwise successful save into
+// a reported failure.
+func saveWithHistory(ctx context.Context, configFile string, cfg *config.Config, rev config.Revision, op string, args map[string]interface{}) error {
+	preImage, err := os.ReadFile(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := mcpStorage.Save(configFile, cfg, rev); err != nil {
+		return err
+	}
+
+	_, newRev, err := mcpStorage.Load(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record history for %s: %v\n", configFile, err)
+		return nil
+	}
+
+	if _, err := history.Record(configFile, op, args, mcpActor(ctx), preImage, string(rev), string(newRev), 0); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record history for %s: %v\n", configFile, err)
+	}
+
+	return nil
+}
+
+// validateMutation runs full validation against cfg as it would stand
+// after an in-progress add/update/delete - schema validation (the same
+// pipeline config.LoadConfig applies on load) plus lint.Lint's structural
+// and template-render checks (duplicate names, priority bounds, output
+// path safety, dangling output rule/section refs) - and returns every
+// error-severity finding. Warnings are omitted: they don't block a write,
+// and apply_changeset's existing rollback check follows the same rule.
+func validateMutation(cfg *config.Config, configFile string) []lint.Finding {
+	var findings []lint.Finding
+	if err := config.ValidateConfigWithSchema(cfg); err != nil {
+		findings = append(findings, lint.Finding{
+			Code:     "AIR000",
+			Severity: lint.SeverityError,
+			Message:  fmt.Sprintf("schema validation failed: %v", err),
+			File:     configFile,
+		})
+	}
+
+	for _, finding := range lint.Lint(cfg, configFile, lint.Options{}) {
+		if finding.Severity == lint.SeverityError {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// mustPrint prints v via newPrinter(), exiting on any error (unknown
+// format or a write failure) the way other CLI commands report fatal
+// errors.
+func mustPrint(v any) {
+	printer, err := newPrinter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := printer.Print(os.Stdout, v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing output: %v\n", err)
+		os.Exit(1)
+	}
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -102,10 +309,47 @@ With the --dry-run flag, it will validate the configuration and show what would
 be generated without writing any files.
 
 With the --update-gitignore flag, it will automatically update .gitignore files
-in config directories to include generated output files.`,
+in config directories to include generated output files.
+
+With the --update flag (alias: --refresh-includes), remote includes
+(git::/git+.../http(s):// sources) whose fetched content no longer matches
+the ai-rulez.lock entry are accepted and re-pinned instead of rejected.
+
+With the --tag flag, rules, sections, and includes gated by a 'when:'
+selector are activated only when they match the given tags, the
+AI_RULEZ_TAGS environment variable, or detected project facts (e.g.
+has(go.mod)).
+
+With the -r/--recursive flag, the --exclude flag (repeatable) prunes
+matching paths from the search, e.g. --exclude '**/node_modules/**'
+--exclude '**/vendor/**'. A .ai-rulezignore file (gitignore syntax) in the
+current directory or any ancestor is applied the same way.
+
+With the --set flag (repeatable), individual config values can be
+overridden without editing the checked-in YAML, using the same KEY shape
+as the AI_RULEZ_<KEY> environment variables (see config.ApplyEnvOverrides),
+e.g. --set METADATA__VERSION=1.2.3 --set OUTPUTS__0__TEMPLATE=@ci.tmpl.
+This is especially useful with -r/--recursive, where only a couple of
+knobs typically need to differ per subtree.
+
+With cache.enabled set in config (see internal/cache), repeated runs skip
+re-rendering outputs whose template and data haven't changed. --no-cache
+disables that for one run, and --max-age overrides cache.maxAge without
+editing the config; 'ai-rulez cache prune'/'cache clean' and 'cache
+clear' reclaim stale or all cache entries respectively.
+
+With --watch, generate stays running and regenerates on every change to the
+config, its includes, and any @file-referenced templates (see 'ai-rulez
+watch --help' for the full behavior, which this flag shares). Combined with
+-r/--recursive, a single shared file watcher covers every discovered
+config, regenerating only the one whose files actually changed.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(_ *cobra.Command, args []string) {
 		if recursive {
+			if watchFlag {
+				runRecursiveWatch()
+				return
+			}
 			runRecursiveGenerate()
 			return
 		}
@@ -120,7 +364,7 @@ in config directories to include generated output files.`,
 			}
 		} else {
 			// Find config file
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -128,11 +372,18 @@ in config directories to include generated output files.`,
 			configFile = foundConfig
 		}
 
+		if watchFlag {
+			runWatchLoop(configFile, "")
+			return
+		}
+
 		// Show which config file we're using
 		fmt.Println("Using config file:", configFile)
 
-		// Load configuration
-		cfg, err := config.LoadConfig(configFile)
+		// Load configuration, resolving includes (local and remote) and
+		// filtering rules/sections/includes by their 'when:' selector
+		ctx := selector.DetectContext(filepath.Dir(configFile), tags)
+		cfg, err := config.LoadConfigWithContext(configFile, updateIncludes, ctx)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 			os.Exit(1)
@@ -140,6 +391,12 @@ in config directories to include generated output files.`,
 
 		// Configuration is already validated during LoadConfig
 
+		if err := applySetOverrides(cfg, setOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+			os.Exit(1)
+		}
+		applyCacheFlags(cfg, noCache, maxAgeOverride)
+
 		if dryRun {
 			fmt.Println("\n=== DRY RUN MODE ===")
 			fmt.Printf("Configuration: %s (v%s)\n", cfg.Metadata.Name, cfg.Metadata.Version)
@@ -156,11 +413,33 @@ in config directories to include generated output files.`,
 			}
 			fmt.Printf("\nTotal rules: %d\n", len(cfg.Rules))
 			fmt.Printf("Total sections: %d\n", len(cfg.Sections))
+
+			gen := generator.NewWithConfigFile(configFile)
+			if err := gen.UseCache(cfg.Cache); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: cache disabled: %v\n", err)
+			}
+			rendered, err := gen.RenderAll(cfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error rendering outputs: %v\n", err)
+				os.Exit(1)
+			}
+			diffs, err := diffutil.Compute(filepath.Dir(configFile), rendered)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error comparing outputs: %v\n", err)
+				os.Exit(1)
+			}
+			changed := 0
+			for _, d := range diffs {
+				if d.Changed {
+					changed++
+				}
+			}
+			fmt.Printf("%d of %d output file(s) would change (see 'ai-rulez diff' for details)\n", changed, len(diffs))
 			return
 		}
 
 		// Generate files
-		gen := generator.NewWithBaseDir(filepath.Dir(configFile))
+		gen := newGeneratorForConfig(cfg, filepath.Dir(configFile))
 		if err := gen.GenerateAll(cfg); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating files: %v\n", err)
 			os.Exit(1)
@@ -180,7 +459,7 @@ in config directories to include generated output files.`,
 
 func runRecursiveGenerate() {
 	// Find all config files
-	configs, err := config.FindAllConfigFiles(".")
+	configs, err := config.FindAllConfigFilesWithExcludes(".", excludes)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error finding configuration files: %v\n", err)
 		os.Exit(1)
@@ -200,8 +479,10 @@ func runRecursiveGenerate() {
 	for _, configFile := range configs {
 		fmt.Printf("\n--- Processing: %s ---\n", configFile)
 
-		// Load configuration
-		cfg, err := config.LoadConfig(configFile)
+		// Load configuration, resolving includes (local and remote) and
+		// filtering rules/sections/includes by their 'when:' selector
+		ctx := selector.DetectContext(filepath.Dir(configFile), tags)
+		cfg, err := config.LoadConfigWithContext(configFile, updateIncludes, ctx)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 			continue
@@ -209,6 +490,12 @@ func runRecursiveGenerate() {
 
 		// Configuration is already validated during LoadConfig
 
+		if err := applySetOverrides(cfg, setOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+			continue
+		}
+		applyCacheFlags(cfg, noCache, maxAgeOverride)
+
 		if dryRun {
 			fmt.Printf("Configuration: %s (v%s)\n", cfg.Metadata.Name, cfg.Metadata.Version)
 			fmt.Printf("Would generate %d output file(s)\n", len(cfg.Outputs))
@@ -217,7 +504,7 @@ func runRecursiveGenerate() {
 		}
 
 		// Generate files
-		gen := generator.NewWithBaseDir(filepath.Dir(configFile))
+		gen := newGeneratorForConfig(cfg, filepath.Dir(configFile))
 		if err := gen.GenerateAll(cfg); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating files: %v\n", err)
 			continue
@@ -243,20 +530,238 @@ func runRecursiveGenerate() {
 	}
 }
 
+// recursiveWatchDebounce mirrors runWatchLoop's own 200ms window; a single
+// shared timer coalesces changes across every discovered config, not just
+// within one.
+const recursiveWatchDebounce = 200 * time.Millisecond
+
+// runRecursiveWatch discovers every config file the way runRecursiveGenerate
+// does, generates each once, then watches all of them through a single
+// shared fsnotify.Watcher until interrupted - regenerating only the config
+// whose own watch set (itself, its includes, conf.d fragments, and
+// @file-referenced templates) contains the changed path. Because a config
+// edit can add or remove any of those, every config's watch set is rebuilt
+// after each regeneration round, the same way a single generate --watch
+// re-syncs after every reload.
+func runRecursiveWatch() {
+	configs, err := config.FindAllConfigFilesWithExcludes(".", excludes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding configuration files: %v\n", err)
+		os.Exit(1)
+	}
+	if len(configs) == 0 {
+		fmt.Fprintf(os.Stderr, "No configuration files found\n")
+		os.Exit(1)
+	}
+	sort.Strings(configs)
+	fmt.Printf("Found %d configuration file(s)\n", len(configs))
+
+	for _, configFile := range configs {
+		regenerateRecursiveWatchTarget(configFile)
+	}
+	fmt.Println("Watching for changes... (Ctrl+C to stop)")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := watchRecursiveConfigs(ctx, configs); err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "Error watching for changes: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// watchRecursiveConfigs runs the shared-watcher loop behind runRecursiveWatch.
+func watchRecursiveConfigs(ctx context.Context, configs []string) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	watched := make(map[string]bool)
+	owners, err := syncRecursiveWatchSet(fsWatcher, configs, watched)
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(recursiveWatchDebounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	timerArmed := false
+	pending := make(map[string]bool)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			owningConfigs := owners[event.Name]
+			if len(owningConfigs) == 0 {
+				continue
+			}
+			for _, owner := range owningConfigs {
+				pending[owner] = true
+			}
+			if !timer.Stop() && timerArmed {
+				<-timer.C
+			}
+			timer.Reset(recursiveWatchDebounce)
+			timerArmed = true
+
+		case <-timer.C:
+			timerArmed = false
+			changed := make([]string, 0, len(pending))
+			for configFile := range pending {
+				changed = append(changed, configFile)
+			}
+			sort.Strings(changed)
+			pending = make(map[string]bool)
+
+			for _, configFile := range changed {
+				regenerateRecursiveWatchTarget(configFile)
+			}
+
+			owners, err = syncRecursiveWatchSet(fsWatcher, configs, watched)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		}
+	}
+}
+
+// syncRecursiveWatchSet adds every file any of configs watches (config,
+// includes, conf.d fragments, @file templates) to fsWatcher, removes files
+// no config watches anymore, and returns the rebuilt file->owning-configs
+// map used to route a change event to the right config(s). watched tracks
+// what's currently registered with fsWatcher across calls.
+func syncRecursiveWatchSet(fsWatcher *fsnotify.Watcher, configs []string, watched map[string]bool) (map[string][]string, error) {
+	owners := make(map[string][]string)
+	for _, configFile := range configs {
+		files, err := config.WatchedFiles(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("collecting watched files for %s: %w", configFile, err)
+		}
+		if cfg, loadErr := config.LoadConfigWithIncludes(configFile); loadErr == nil {
+			files = append(files, recursiveWatchTemplateFiles(configFile, cfg)...)
+		}
+		for _, file := range files {
+			owners[file] = append(owners[file], configFile)
+		}
+	}
+
+	for file := range owners {
+		if watched[file] {
+			continue
+		}
+		if err := fsWatcher.Add(file); err != nil {
+			return nil, fmt.Errorf("failed to watch %s: %w", file, err)
+		}
+		watched[file] = true
+	}
+	for file := range watched {
+		if _, ok := owners[file]; !ok {
+			_ = fsWatcher.Remove(file)
+			delete(watched, file)
+		}
+	}
+
+	return owners, nil
+}
+
+// recursiveWatchTemplateFiles returns the absolute paths of every
+// @file-referenced template among cfg's outputs, resolved against
+// configFile's directory - the same resolution renderTemplate itself uses.
+func recursiveWatchTemplateFiles(configFile string, cfg *config.Config) []string {
+	baseDir := filepath.Dir(configFile)
+	seen := make(map[string]bool)
+	var files []string
+	for _, output := range cfg.Outputs {
+		if !strings.HasPrefix(output.Template, "@") {
+			continue
+		}
+		full := filepath.Join(baseDir, strings.TrimPrefix(output.Template, "@"))
+		if seen[full] {
+			continue
+		}
+		seen[full] = true
+		files = append(files, full)
+	}
+	return files
+}
+
+// regenerateRecursiveWatchTarget reloads and regenerates configFile the way
+// a single generate --watch regeneration does, printing the same colorized
+// rewritten-vs-unchanged summary via printWatchDiffSummary.
+func regenerateRecursiveWatchTarget(configFile string) {
+	fmt.Printf("\n--- %s ---\n", configFile)
+
+	watchCtx := selector.DetectContext(filepath.Dir(configFile), tags)
+	cfg, err := config.LoadConfigWithContext(configFile, updateIncludes, watchCtx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		return
+	}
+	if err := applySetOverrides(cfg, setOverrides); err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+		return
+	}
+	applyCacheFlags(cfg, noCache, maxAgeOverride)
+
+	gen := newGeneratorForConfig(cfg, filepath.Dir(configFile))
+	rendered, err := gen.RenderAll(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering outputs: %v\n", err)
+		return
+	}
+	diffs, err := diffutil.Compute(filepath.Dir(configFile), rendered)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error comparing outputs: %v\n", err)
+		return
+	}
+	if err := gen.GenerateAll(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating files: %v\n", err)
+		return
+	}
+
+	printWatchDiffSummary(diffs)
+}
+
 // validateCmd represents the validate command
 var validateCmd = &cobra.Command{
 	Use:   "validate [config-file]",
 	Short: "Validate AI rules configuration",
 	Long: `Validate your AI rules configuration file for syntax errors,
-schema compliance, and logical issues like circular dependencies.`,
+schema compliance, and logical issues like circular dependencies.
+
+With the --explain-merge flag, it resolves includes and any .local.yaml
+patch file, then prints which file contributed each rule/section field a
+patch touched.
+
+With the --set flag (repeatable), individual config values can be
+overridden before validating, using the same KEY shape as the
+AI_RULEZ_<KEY> environment variables (see generate --help).`,
 	Args: cobra.MaximumNArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		var configFile string
 		if len(args) > 0 {
 			configFile = args[0]
 		} else {
 			// Find config file
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -264,101 +769,113 @@ schema compliance, and logical issues like circular dependencies.`,
 			configFile = foundConfig
 		}
 
+		explainMerge, _ := cmd.Flags().GetBool("explain-merge")
+		if explainMerge {
+			cfg, explain, err := config.LoadConfigWithExplain(configFile)
+			if err != nil {
+				printValidationError(err)
+				os.Exit(1)
+			}
+			if err := applySetOverrides(cfg, setOverrides); err != nil {
+				fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+				os.Exit(1)
+			}
+			printMergeExplanation(explain)
+			mustPrint(validationResult{ConfigFile: configFile, Name: cfg.Metadata.Name, Rules: len(cfg.Rules), Sections: len(cfg.Sections)})
+			return
+		}
+
 		// Load and validate configuration
 		cfg, err := config.LoadConfig(configFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			printValidationError(err)
 			os.Exit(1)
 		}
 
 		// Configuration is already validated during LoadConfig
 
-		fmt.Printf("✓ Configuration is valid: %s\n", configFile)
-		fmt.Printf("  Name: %s\n", cfg.Metadata.Name)
-		fmt.Printf("  Version: %s\n", cfg.Metadata.Version)
-		fmt.Printf("  Rules: %d\n", len(cfg.Rules))
-		fmt.Printf("  Sections: %d\n", len(cfg.Sections))
-		fmt.Printf("  Outputs: %d\n", len(cfg.Outputs))
-	},
-}
-
-// versionCmd represents the version command
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print the version of ai-rulez",
-	Long:  `Print the version of ai-rulez CLI tool.`,
-	Run: func(_ *cobra.Command, _ []string) {
-		fmt.Printf("ai-rulez version %s\n", Version)
-	},
-}
-
-// initCmd represents the init command
-var initCmd = &cobra.Command{
-	Use:   "init [project-name]",
-	Short: "Initialize a new AI rules project",
-	Long: `Initialize a new AI rules project with a basic configuration file
-and example rules. This creates an ai_rulez.yaml file in the current directory.`,
-	Args: cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		projectName := "My Project"
-		if len(args) > 0 {
-			projectName = args[0]
+		if err := applySetOverrides(cfg, setOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+			os.Exit(1)
 		}
-
-		// Check if ai_rulez.yaml already exists
-		if _, err := os.Stat("ai_rulez.yaml"); err == nil {
-			fmt.Fprintf(os.Stderr, "Error: ai_rulez.yaml already exists in current directory\n")
+		if err := config.ValidateOutputs(cfg.Outputs); err != nil {
+			printValidationError(err)
 			os.Exit(1)
 		}
 
-		// Get template type from flag
-		template, _ := cmd.Flags().GetString("template")
+		mustPrint(validationResult{
+			ConfigFile: configFile,
+			Name:       cfg.Metadata.Name,
+			Version:    cfg.Metadata.Version,
+			Rules:      len(cfg.Rules),
+			Sections:   len(cfg.Sections),
+			Outputs:    len(cfg.Outputs),
+		})
+	},
+}
 
-		// Create configuration based on template
-		var cfg *config.Config
-		switch template {
-		case "react":
-			cfg = createReactTemplate(projectName)
-		case "typescript":
-			cfg = createTypescriptTemplate(projectName)
-		default:
-			cfg = createBasicTemplate(projectName)
-		}
+// validationResult is `validate`'s output: a summary of the config that
+// was checked, rendered as text, a table, or structured JSON/YAML via the
+// global --output flag.
+type validationResult struct {
+	ConfigFile string `json:"config_file" yaml:"config_file"`
+	Name       string `json:"name" yaml:"name"`
+	Version    string `json:"version,omitempty" yaml:"version,omitempty"`
+	Rules      int    `json:"rules" yaml:"rules"`
+	Sections   int    `json:"sections" yaml:"sections"`
+	Outputs    int    `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+}
 
-		// Save configuration
-		if err := config.SaveConfig(cfg, "ai_rulez.yaml"); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating configuration file: %v\n", err)
-			os.Exit(1)
-		}
+func (v validationResult) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ Configuration is valid: %s\n", v.ConfigFile)
+	fmt.Fprintf(&b, "  Name: %s\n", v.Name)
+	if v.Version != "" {
+		fmt.Fprintf(&b, "  Version: %s\n", v.Version)
+	}
+	fmt.Fprintf(&b, "  Rules: %d\n", v.Rules)
+	fmt.Fprintf(&b, "  Sections: %d\n", v.Sections)
+	if v.Outputs > 0 {
+		fmt.Fprintf(&b, "  Outputs: %d\n", v.Outputs)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
 
-		fmt.Printf("✓ Initialized new AI rules project: %s\n", projectName)
-		fmt.Println("  - Created ai_rulez.yaml")
-		fmt.Println("  - Run 'ai-rulez generate' to create rule files")
-	},
+func (v validationResult) Header() []string {
+	return []string{"CONFIG_FILE", "NAME", "VERSION", "RULES", "SECTIONS", "OUTPUTS"}
 }
 
-// addCmd represents the add command
-var addCmd = &cobra.Command{
-	Use:   "add",
-	Short: "Add rules or sections to configuration",
-	Long:  `Add new rules or sections to your AI rules configuration file.`,
+func (v validationResult) Rows() [][]string {
+	return [][]string{{
+		v.ConfigFile, v.Name, v.Version,
+		fmt.Sprintf("%d", v.Rules), fmt.Sprintf("%d", v.Sections), fmt.Sprintf("%d", v.Outputs),
+	}}
 }
 
-// addRuleCmd represents the add rule subcommand
-var addRuleCmd = &cobra.Command{
-	Use:   "rule [name]",
-	Short: "Add a new rule to configuration",
-	Long: `Add a new rule to your AI rules configuration file.
-The rule name is provided as an argument, and the content can be provided
-via stdin or will open an editor for you to enter the rule content.`,
-	Args: cobra.ExactArgs(1),
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [config-file]",
+	Short: "Run deep structural and content checks on a configuration",
+	Long: `Lint runs a suite of checks beyond what validate's schema check covers:
+duplicate rule/section names (in the base config and any profile overlay),
+priority values outside 1-10, empty or overly long rule/section content,
+output filenames that collide after path normalization, outputs
+referencing a template file that doesn't exist, templates that fail to
+render (e.g. an unresolved {{ }} variable), and profiles with a circular
+extends chain. Each finding has a code (e.g. AIR001), a severity, and a
+location, in the same spirit as promtool check config for Prometheus.
+
+With --fail-on=warning (the default is error), lint exits non-zero if any
+warning-level finding is reported, not just errors. With --format=json,
+findings are printed as a JSON array instead of text, for consumption by
+other tools.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		ruleName := args[0]
-		priority, _ := cmd.Flags().GetInt("priority")
-		configFile, _ := cmd.Flags().GetString("config")
-
-		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+		var configFile string
+		if len(args) > 0 {
+			configFile = args[0]
+		} else {
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -366,54 +883,1261 @@ via stdin or will open an editor for you to enter the rule content.`,
 			configFile = foundConfig
 		}
 
-		// Load existing configuration
 		cfg, err := config.LoadConfig(configFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			printValidationError(err)
 			os.Exit(1)
 		}
 
-		// Read content from stdin or prompt
-		fmt.Println("Enter rule content (press Ctrl+D when done):")
-		content, err := readFromStdin()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+		if err := applySetOverrides(cfg, setOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Add new rule
-		newRule := config.Rule{
-			Name:     ruleName,
-			Priority: priority,
-			Content:  content,
-		}
-		cfg.Rules = append(cfg.Rules, newRule)
+		maxContentLength, _ := cmd.Flags().GetInt("max-content-length")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+		format, _ := cmd.Flags().GetString("format")
 
-		// Save configuration
-		if err := config.SaveConfig(cfg, configFile); err != nil {
-			fmt.Fprintf(os.Stderr, "Error saving configuration: %v\n", err)
+		findings := lint.Lint(cfg, configFile, lint.Options{MaxContentLength: maxContentLength})
+
+		if err := printLintFindings(findings, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Added rule '%s' with priority %d to %s\n", ruleName, priority, configFile)
+		if lintShouldFail(findings, failOn) {
+			os.Exit(1)
+		}
 	},
 }
 
-// addSectionCmd represents the add section subcommand
-var addSectionCmd = &cobra.Command{
-	Use:   "section [title]",
-	Short: "Add a new section to configuration",
-	Long: `Add a new section to your AI rules configuration file.
-The section title is provided as an argument, and the content can be provided
-via stdin or will open an editor for you to enter the section content.`,
+// printLintFindings renders findings as "text" (one line per finding,
+// grouped implicitly by lint.Lint's error-before-warning ordering) or
+// "json" (the raw Finding slice, for CI tooling to parse).
+func printLintFindings(findings []lint.Finding, format string) error {
+	switch format {
+	case "", "text":
+		if len(findings) == 0 {
+			fmt.Println("✓ No issues found")
+			return nil
+		}
+		for _, f := range findings {
+			loc := f.File
+			if f.Location != "" {
+				loc += " (" + f.Location + ")"
+			}
+			fmt.Printf("[%s] %s: %s - %s\n", strings.ToUpper(string(f.Severity)), f.Code, loc, f.Message)
+		}
+		return nil
+	case "json":
+		jsonResult, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonResult))
+		return nil
+	default:
+		return fmt.Errorf("unknown --format %q: expected text or json", format)
+	}
+}
+
+// lintShouldFail reports whether findings should make lint exit non-zero
+// for failOn ("error", the default, or "warning").
+func lintShouldFail(findings []lint.Finding, failOn string) bool {
+	for _, f := range findings {
+		if f.Severity == lint.SeverityError {
+			return true
+		}
+		if failOn == "warning" && f.Severity == lint.SeverityWarning {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheCmd represents the cache command group
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and maintain the internal/cache filecache",
+	Long: `Manage the on-disk filecache generate consults when a configuration
+sets cache.enabled: true - see internal/cache. Entries live under
+cache.dir (default $XDG_CACHE_HOME/ai-rulez) and expire after cache.maxAge
+(default 24h), but 'cache prune' (alias: 'cache clean') and 'cache clear'
+let you reclaim that space or force a cold re-render without waiting on
+the TTL. 'ai-rulez generate --no-cache'/'--max-age' override caching for a
+single run instead.`,
+}
+
+// cacheConfigForCmd loads the Config [config-file] names (or the resolved
+// default) and returns its Cache block, falling back to an enabled
+// CacheConfig with repo defaults if the config doesn't declare one - prune
+// and clear operate on whatever directory generate would have used, even
+// for a config that never turned caching on.
+func cacheConfigForCmd(args []string) (*config.CacheConfig, error) {
+	var configFile string
+	if len(args) > 0 {
+		configFile = args[0]
+	} else {
+		foundConfig, err := resolveConfigFile(".")
+		if err != nil {
+			return nil, err
+		}
+		configFile = foundConfig
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Cache != nil {
+		return cfg.Cache, nil
+	}
+	return &config.CacheConfig{Enabled: true}, nil
+}
+
+// cachePruneCmd removes stale cache entries
+var cachePruneCmd = &cobra.Command{
+	Use:     "prune [config-file]",
+	Aliases: []string{"clean"},
+	Short:   "Remove cache entries older than cache.maxAge",
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		cacheCfg, err := cacheConfigForCmd(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		dir := cacheCfg.Dir
+		if dir == "" {
+			dir, err = cache.DefaultDir()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		maxAge, err := cacheCfg.MaxAgeDuration()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		removed, err := cache.New(dir, maxAge).Prune()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning cache: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Pruned %d stale cache entries from %s\n", removed, dir)
+	},
+}
+
+// cacheClearCmd removes the entire cache directory
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [config-file]",
+	Short: "Remove the entire cache directory",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		cacheCfg, err := cacheConfigForCmd(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		dir := cacheCfg.Dir
+		if dir == "" {
+			dir, err = cache.DefaultDir()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := cache.New(dir, 0).Clear(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing cache: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Cleared cache at %s\n", dir)
+	},
+}
+
+// modulesCmd represents the modules command group
+var modulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "Manage remote (git::/git+/https://) include sources",
+	Long: `Remote includes (the git::/git+/https:// forms of an includes: entry,
+see internal/modules) are fetched once into a shared, content-addressed
+cache (~/.cache/ai-rulez/modules) and pinned by content hash in
+ai-rulez.lock next to the config file. 'ai-rulez modules update' re-fetches
+every remote include for a config and re-pins any whose content has
+changed upstream, equivalent to 'ai-rulez generate --update' but without
+rendering or writing any outputs.`,
+}
+
+// modulesUpdateCmd represents the modules update command
+var modulesUpdateCmd = &cobra.Command{
+	Use:   "update [config-file]",
+	Short: "Refetch remote includes and re-pin ai-rulez.lock",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		var configFile string
+		if len(args) > 0 {
+			configFile = args[0]
+		} else {
+			foundConfig, err := resolveConfigFile(".")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			configFile = foundConfig
+		}
+
+		if _, err := config.LoadConfigWithIncludesAndUpdate(configFile, true); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating modules: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Modules refreshed and ai-rulez.lock re-pinned")
+	},
+}
+
+// configCmd represents the config command group
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the effective configuration",
+	Long: `Introspect configuration beyond what 'validate' checks: 'config print' shows
+the fully-defaulted configuration generate actually sees (e.g. every rule's
+priority filled in), and 'config reference' documents every recognized field.`,
+}
+
+// configPrintCmd prints the effective, fully-defaulted configuration.
+var configPrintCmd = &cobra.Command{
+	Use:   "print [config-file]",
+	Short: "Print the effective configuration as YAML",
+	Long: `Load [config-file] (or the auto-discovered config), apply every default
+(see internal/config.ApplyDefaults - currently just rule/section priority),
+and print the result as YAML. Unlike the source file, every rule and
+section in the output has an explicit priority.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		var configFile string
+		if len(args) > 0 {
+			configFile = args[0]
+		} else {
+			foundConfig, err := resolveConfigFile(".")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			configFile = foundConfig
+		}
+
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			printValidationError(err)
+			os.Exit(1)
+		}
+
+		rendered, err := allconfig.PrintEffective(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(rendered)
+	},
+}
+
+// configReferenceCmd prints a generated reference doc for every
+// recognized configuration field.
+var configReferenceCmd = &cobra.Command{
+	Use:   "reference",
+	Short: "Print a reference of every recognized configuration field",
+	Run: func(_ *cobra.Command, _ []string) {
+		fmt.Print(allconfig.Reference())
+	},
+}
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [output...]",
+	Short: "Preview what generate would change",
+	Long: `Diff loads the configuration, renders every output in memory via the same
+path --dry-run and the diff_output MCP tool use (generator.RenderAll), and
+prints a unified diff between each output's current on-disk content and
+what would be regenerated. Pass one or more output filenames to limit the
+diff to those outputs.
+
+With --exit-code, diff exits 1 if any output would change (and 0
+otherwise), like 'git diff --exit-code', so CI can gate on "regenerate
+ai-rulez files and commit the result" drift. With --format=json, the
+result is a JSON array of {file, existed, changed, unified} objects
+instead of unified-diff text; --format=yaml and --format=table render the
+same data via internal/output.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile, err := resolveConfigFile(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			printValidationError(err)
+			os.Exit(1)
+		}
+
+		if err := applySetOverrides(cfg, setOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+			os.Exit(1)
+		}
+
+		gen := generator.NewWithConfigFile(configFile)
+		rendered, err := gen.RenderAll(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering outputs: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) > 0 {
+			filtered := make(map[string][]byte, len(args))
+			for _, want := range args {
+				content, ok := rendered[want]
+				if !ok {
+					fmt.Fprintf(os.Stderr, "Error: output %q not found in configuration\n", want)
+					os.Exit(1)
+				}
+				filtered[want] = content
+			}
+			rendered = filtered
+		}
+
+		diffs, err := diffutil.Compute(filepath.Dir(configFile), rendered)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing outputs: %v\n", err)
+			os.Exit(1)
+		}
+		sort.Slice(diffs, func(i, j int) bool { return diffs[i].File < diffs[j].File })
+
+		format, _ := cmd.Flags().GetString("format")
+		if err := printDiffs(diffs, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		exitCode, _ := cmd.Flags().GetBool("exit-code")
+		if exitCode {
+			for _, d := range diffs {
+				if d.Changed {
+					os.Exit(1)
+				}
+			}
+		}
+	},
+}
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check [output...]",
+	Short: "Fail if generate would change any output (CI-friendly diff)",
+	Long: `Check renders every output the same way 'diff' does (generator.GenerateAllDiff)
+and reports which ones are stale, but tuned for CI rather than interactive
+preview: it exits 1 whenever any output is stale unless --exit-code=false,
+and it colors its output only when stdout is a terminal, printing plain
+text when piped into a CI log or file. Pass --json for the same
+{file, existed, changed, unified} objects 'diff --format=json' produces.
+Pass one or more output filenames to limit the check to those outputs.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile, err := resolveConfigFile(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			printValidationError(err)
+			os.Exit(1)
+		}
+
+		if err := applySetOverrides(cfg, setOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+			os.Exit(1)
+		}
+
+		gen := generator.NewWithConfigFile(configFile)
+		diffs, err := gen.GenerateAllDiff(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking outputs: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) > 0 {
+			wanted := make(map[string]bool, len(args))
+			for _, want := range args {
+				wanted[want] = true
+			}
+			filtered := diffs[:0]
+			for _, d := range diffs {
+				if wanted[d.File] {
+					filtered = append(filtered, d)
+					delete(wanted, d.File)
+				}
+			}
+			for missing := range wanted {
+				fmt.Fprintf(os.Stderr, "Error: output %q not found in configuration\n", missing)
+				os.Exit(1)
+			}
+			diffs = filtered
+		}
+		sort.Slice(diffs, func(i, j int) bool { return diffs[i].File < diffs[j].File })
+
+		asJSON, _ := cmd.Flags().GetBool("json")
+		if asJSON {
+			printer, err := output.New("json")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := printer.Print(os.Stdout, diffTableResult(diffs)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			printCheckDiffs(diffs)
+		}
+
+		exitCode, _ := cmd.Flags().GetBool("exit-code")
+		if exitCode {
+			for _, d := range diffs {
+				if d.Changed {
+					os.Exit(1)
+				}
+			}
+		}
+	},
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a
+// pipe or redirected file, so printCheckDiffs can skip ANSI colors when
+// stdout is captured by a CI log or file - ai-rulez has no TTY-detection
+// library dependency, so this is the minimal stdlib equivalent of
+// golang.org/x/term.IsTerminal.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (stat.Mode() & os.ModeCharDevice) != 0
+}
+
+// printCheckDiffs prints one line per output ("stale" or "up to date",
+// colorized like printWatchDiffSummary when stdout is a terminal) followed
+// by the unified diff for every stale output, then a summary line.
+func printCheckDiffs(diffs []diffutil.FileDiff) {
+	colorize := isTerminal(os.Stdout)
+
+	stale := 0
+	for _, d := range diffs {
+		switch {
+		case d.Changed && colorize:
+			stale++
+			fmt.Printf("%s✗ stale%s       %s\n", ansiRed, ansiReset, d.File)
+		case d.Changed:
+			stale++
+			fmt.Printf("stale        %s\n", d.File)
+		case colorize:
+			fmt.Printf("%sup to date%s   %s\n", ansiDim, ansiReset, d.File)
+		default:
+			fmt.Printf("up to date   %s\n", d.File)
+		}
+	}
+	for _, d := range diffs {
+		if d.Changed {
+			fmt.Print(d.Unified)
+		}
+	}
+
+	if stale == 0 {
+		fmt.Println("✓ All outputs up to date")
+	} else {
+		fmt.Printf("✗ %d of %d outputs stale\n", stale, len(diffs))
+	}
+}
+
+// printDiffs renders diffs as "unified" (git-style diff text for each
+// changed file, nothing for unchanged ones) or "json" (the raw FileDiff
+// slice, for editors and AI assistants to parse).
+func printDiffs(diffs []diffutil.FileDiff, format string) error {
+	switch format {
+	case "", "unified":
+		changed := 0
+		for _, d := range diffs {
+			if !d.Changed {
+				continue
+			}
+			changed++
+			fmt.Print(d.Unified)
+		}
+		if changed == 0 {
+			fmt.Println("No changes")
+		}
+		return nil
+	case "json", "yaml", "table":
+		printer, err := output.New(format)
+		if err != nil {
+			return err
+		}
+		return printer.Print(os.Stdout, diffTableResult(diffs))
+	default:
+		return fmt.Errorf("unknown --format %q: expected unified, json, yaml, or table", format)
+	}
+}
+
+// diffTableResult adapts []diffutil.FileDiff for output.Printer: JSON/YAML
+// marshal the same fields diffutil.FileDiff already exposes, and Table
+// renders one row per file.
+type diffTableResult []diffutil.FileDiff
+
+func (d diffTableResult) Header() []string { return []string{"FILE", "EXISTED", "CHANGED"} }
+
+func (d diffTableResult) Rows() [][]string {
+	rows := make([][]string, len(d))
+	for i, fd := range d {
+		rows[i] = []string{fd.File, fmt.Sprintf("%v", fd.Existed), fmt.Sprintf("%v", fd.Changed)}
+	}
+	return rows
+}
+
+// newGeneratorForConfig builds the Generator a real (non-preview) Generate
+// invocation should use: rooted at baseDir, with internal/cache wired in
+// from cfg.Cache if it's set. A cache configuration error is reported as a
+// warning rather than aborting generation, since a cold/unwritable cache
+// directory shouldn't block writing output files.
+func newGeneratorForConfig(cfg *config.Config, baseDir string) *generator.Generator {
+	gen := generator.NewWithBaseDir(baseDir)
+	if err := gen.UseCache(cfg.Cache); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: cache disabled: %v\n", err)
+	}
+	return gen
+}
+
+// applySetOverrides applies --set KEY=VALUE pairs to cfg, in order, using
+// the same dotted-double-underscore KEY shape config.ApplyEnvOverrides
+// reads from the environment (e.g. "METADATA__NAME=value",
+// "OUTPUTS__0__TEMPLATE=@file.tmpl"), so a value can be overridden either
+// via AI_RULEZ_<KEY> or --set <KEY> without learning two conventions.
+func applySetOverrides(cfg *config.Config, sets []string) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(sets))
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return fmt.Errorf("invalid --set %q: expected KEY=VALUE", set)
+		}
+		values[strings.ToUpper(key)] = value
+	}
+
+	getenv := func(key string) string {
+		return values[strings.TrimPrefix(key, config.DefaultEnvPrefix)]
+	}
+	return config.ApplyEnvOverrides(cfg, config.DefaultEnvPrefix, getenv)
+}
+
+// applyCacheFlags layers generateCmd's --no-cache/--max-age over cfg.Cache
+// for this run, without touching the checked-in config: noCache forces
+// caching off outright, otherwise a non-empty maxAge both enables caching
+// (if cfg.Cache didn't already) and overrides its MaxAge.
+func applyCacheFlags(cfg *config.Config, noCache bool, maxAge string) {
+	if noCache {
+		cfg.Cache = nil
+		return
+	}
+	if maxAge == "" {
+		return
+	}
+	if cfg.Cache == nil {
+		cfg.Cache = &config.CacheConfig{}
+	}
+	cfg.Cache.Enabled = true
+	cfg.Cache.MaxAge = maxAge
+}
+
+// printValidationError prints err from config.LoadConfig or
+// config.LoadConfigWithExplain. A config.ValidationErrors - the structured
+// result of a failed schema check - is expanded one "file:line:col: path
+// message" line per violation; any other error falls back to the default
+// single-line rendering.
+func printValidationError(err error) {
+	var verrs config.ValidationErrors
+	if errors.As(err, &verrs) {
+		fmt.Fprintln(os.Stderr, "Error loading configuration:")
+		for _, verr := range verrs {
+			fmt.Fprintf(os.Stderr, "  %s\n", verr.Error())
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+}
+
+// printMergeExplanation prints, per rule/section, which .local.yaml patch
+// file contributed each field it touched.
+func printMergeExplanation(explain *config.MergeExplanation) {
+	if len(explain.Rules) == 0 && len(explain.Sections) == 0 {
+		fmt.Println("No .local.yaml patch fields were applied")
+		return
+	}
+
+	fmt.Println("Merge explanation:")
+	for _, fs := range explain.Rules {
+		fmt.Printf("  rule %q:\n", fs.Key)
+		for field, source := range fs.Fields {
+			fmt.Printf("    %s <- %s\n", field, source)
+		}
+	}
+	for _, fs := range explain.Sections {
+		fmt.Printf("  section %q:\n", fs.Key)
+		for field, source := range fs.Fields {
+			fmt.Printf("    %s <- %s\n", field, source)
+		}
+	}
+}
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [config-file]",
+	Short: "Watch for changes and regenerate automatically",
+	Long: `Watch the configuration file, every local file it includes, every conf.d
+fragment, and every @file-referenced template for changes, regenerating
+outputs automatically.
+
+Changes are debounced over a 200ms window so a single save that touches
+several watched files only triggers one regeneration. A config edit that
+fails to load or schema-validate leaves the previous good config in memory
+and every existing output file untouched - only a change that loads and
+validates cleanly regenerates anything.
+
+Regeneration reuses the same content-hash short-circuit as 'generate', so an
+output whose rendered content hasn't changed is left untouched on disk. Edits
+to the config itself (new outputs, changed includes) take effect on the next
+change without restarting the watch.
+
+Send SIGHUP to force an immediate full reload and regeneration, bypassing the
+debounce window - useful when a watched path lives behind a filesystem that
+doesn't deliver change notifications reliably (e.g. some network mounts).
+
+--exec runs a shell command after each successful regeneration, useful for
+'git add' or for nudging an editor to reload the generated files.
+
+Press Ctrl+C to stop watching.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile, _ := cmd.Flags().GetString("config")
+		if len(args) > 0 {
+			configFile = args[0]
+		}
+		execCmd, _ := cmd.Flags().GetString("exec")
+
+		if configFile != "" {
+			if _, err := os.Stat(configFile); os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Error: Configuration file '%s' not found\n", configFile)
+				os.Exit(1)
+			}
+		} else {
+			foundConfig, err := resolveConfigFile(".")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			configFile = foundConfig
+		}
+
+		runWatchLoop(configFile, execCmd)
+	},
+}
+
+// runWatchLoop is the shared implementation behind 'ai-rulez watch' and
+// 'ai-rulez generate --watch': it generates configFile once, then keeps
+// regenerating on every debounced change until interrupted, printing a
+// colorized rewritten-vs-unchanged summary (see printWatchDiffSummary)
+// after each regeneration. execCmd, when non-empty, runs after every
+// successful regeneration (the watch command's --exec flag).
+func runWatchLoop(configFile, execCmd string) {
+	fmt.Println("Using config file:", configFile)
+
+	cfg, err := config.LoadConfigWithIncludes(configFile)
+	if err != nil {
+		printValidationError(err)
+		os.Exit(1)
+	}
+
+	gen := newGeneratorForConfig(cfg, filepath.Dir(configFile))
+	if err := gen.GenerateAll(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating files: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Generated %d file(s) successfully\n", len(cfg.Outputs))
+	fmt.Println("Watching for changes... (Ctrl+C to stop)")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	reload := make(chan struct{}, 1)
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+	go func() {
+		for range sighup {
+			select {
+			case reload <- struct{}{}:
+			default: // a reload is already pending
+			}
+		}
+	}()
+
+	events := make(chan generator.WatchEvent)
+	go func() {
+		for event := range events {
+			switch event.Type {
+			case generator.WatchFileChanged:
+				fmt.Printf("Changed: %s\n", event.File)
+			case generator.WatchReload:
+				fmt.Println("SIGHUP received, forcing full reload...")
+			case generator.WatchStart:
+				fmt.Println("Regenerating...")
+			case generator.WatchFinish:
+				printWatchDiffSummary(event.Diffs)
+				runWatchExec(execCmd)
+			case generator.WatchError:
+				fmt.Fprintf(os.Stderr, "Error: %v\n", event.Err)
+			}
+		}
+	}()
+
+	err = gen.Watch(ctx, configFile, generator.WatchOptions{Debounce: 200 * time.Millisecond, Reload: reload}, events)
+	close(events)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "Error watching for changes: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// ansiGreen/ansiDim/ansiRed/ansiReset colorize printWatchDiffSummary's and
+// printCheckDiffs's output; ai-rulez has no color library dependency, so
+// these are the minimal raw escape codes for "changed" (green), "unchanged"
+// (dim), and "stale" (red) lines.
+const (
+	ansiGreen = "\033[32m"
+	ansiDim   = "\033[2m"
+	ansiRed   = "\033[31m"
+	ansiReset = "\033[0m"
+)
+
+// printWatchDiffSummary prints one colorized line per output from a watch
+// regeneration: green "rewritten" for outputs whose content actually
+// changed, dim "unchanged" for the rest - diffs comes straight from the
+// render-cache-aware render that fed GenerateAll, so this never re-renders
+// to find out.
+func printWatchDiffSummary(diffs []diffutil.FileDiff) {
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].File < diffs[j].File })
+
+	rewritten := 0
+	for _, d := range diffs {
+		if d.Changed {
+			rewritten++
+			fmt.Printf("  %s✓ rewritten%s  %s\n", ansiGreen, ansiReset, d.File)
+		} else {
+			fmt.Printf("  %sunchanged%s   %s\n", ansiDim, ansiReset, d.File)
+		}
+	}
+	fmt.Printf("✓ Regenerated: %d rewritten, %d unchanged\n", rewritten, len(diffs)-rewritten)
+}
+
+// runWatchExec runs execCmd (the watch command's --exec flag) through the
+// shell after a successful regeneration, streaming its output the same way
+// generated files were just streamed to disk. A no-op when execCmd is empty.
+func runWatchExec(execCmd string) {
+	if execCmd == "" {
+		return
+	}
+	cmd := exec.Command("sh", "-c", execCmd) //nolint:gosec // exec command is author-controlled via --exec
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running --exec command: %v\n", err)
+	}
+}
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of ai-rulez",
+	Long:  `Print the version of ai-rulez CLI tool.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		fmt.Printf("ai-rulez version %s\n", Version)
+	},
+}
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a tab-completion script for ai-rulez.
+
+Bash:
+  $ source <(ai-rulez completion bash)
+
+  # To load completions for each session, execute once:
+  # Linux:
+  $ ai-rulez completion bash > /etc/bash_completion.d/ai-rulez
+  # macOS:
+  $ ai-rulez completion bash > $(brew --prefix)/etc/bash_completion.d/ai-rulez
+
+Zsh:
+  $ ai-rulez completion zsh > "${fpath[1]}/_ai-rulez"
+
+Fish:
+  $ ai-rulez completion fish | source
+  $ ai-rulez completion fish > ~/.config/fish/completions/ai-rulez.fish
+
+PowerShell:
+  PS> ai-rulez completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactValidArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating completion script: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:    "docs",
+	Short:  "Generate CLI reference documentation",
+	Long:   `Generate man pages or markdown reference documentation for every ai-rulez command, walking the full cobra command tree (including subcommands like "add rule" and "update section"). Intended for packagers shipping ai-rulez.1 and for publishing CLI reference docs - not something an end user runs day to day.`,
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+		}
+
+		switch format {
+		case "man":
+			header := &doc.GenManHeader{
+				Title:   "AI-RULEZ",
+				Section: "1",
+				Source:  "ai-rulez " + Version,
+				Manual:  "ai-rulez Manual",
+			}
+			if err := doc.GenManTree(rootCmd, header, outputDir); err != nil {
+				return fmt.Errorf("failed to generate man pages: %w", err)
+			}
+		case "md":
+			if err := doc.GenMarkdownTree(rootCmd, outputDir); err != nil {
+				return fmt.Errorf("failed to generate markdown docs: %w", err)
+			}
+		case "rest":
+			if err := doc.GenReSTTree(rootCmd, outputDir); err != nil {
+				return fmt.Errorf("failed to generate reST docs: %w", err)
+			}
+		default:
+			return fmt.Errorf("unknown format %q: must be one of man, md, rest", format)
+		}
+
+		fmt.Printf("✓ Generated %s documentation in %s\n", format, outputDir)
+		return nil
+	},
+}
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init [project-name]",
+	Short: "Initialize a new AI rules project",
+	Long: `Initialize a new AI rules project with a basic configuration file
+and example rules. This creates an ai_rulez.yaml file in the current directory,
+along with the standard project layout's config, includes, and cache
+directories (see Layout in internal/config; override any of them with
+AI_RULEZ_ROOT, AI_RULEZ_CONFIG_DIR, AI_RULEZ_INCLUDE_DIR, or AI_RULEZ_CACHE_DIR).
+
+Templates come from a catalog: the templates built into the binary, any
+installed under $XDG_CONFIG_HOME/ai-rulez/templates, and an optional remote
+catalog configured via "template_catalog_url" in ~/.ai-rulez.yaml. Use
+--list-templates to see what's available, and 'ai-rulez templates add <url>'
+to install one.
+
+Pass --wizard to skip --template entirely and be walked through project
+name, description, which AI assistants to target, and whether to seed
+example rules and update .gitignore, instead of editing the YAML by hand.
+
+Pass --scaffold to bootstrap a full project layout instead of a single
+file: rules are split by domain (style/security/testing) into includes
+under the includes dir, each output gets its own customizable Go template
+under templates/, and a .github/workflows/ai-rulez.yml CI job plus a
+.pre-commit-config.yaml are added so generated output stays in sync.
+
+With the --set flag (repeatable), individual values in the chosen
+template can be overridden before the config is written, e.g.
+--set METADATA__VERSION=1.2.3 (same KEY shape as AI_RULEZ_<KEY> env vars).`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wizard, _ := cmd.Flags().GetBool("wizard")
+
+		if listTemplates {
+			registry, err := scaffold.NewRegistry(viper.GetString("template_catalog_url"))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading template catalog: %v\n", err)
+				os.Exit(1)
+			}
+			for _, tmpl := range registry.List() {
+				fmt.Printf("  - %-14s %s\n", tmpl.Name, tmpl.Description)
+			}
+			return
+		}
+
+		projectName := "My Project"
+		if len(args) > 0 {
+			projectName = args[0]
+		}
+
+		// Check if ai_rulez.yaml already exists
+		if _, err := os.Stat("ai_rulez.yaml"); err == nil {
+			fmt.Fprintf(os.Stderr, "Error: ai_rulez.yaml already exists in current directory\n")
+			os.Exit(1)
+		}
+
+		var cfg *config.Config
+		wantGitignore := false
+		if wizard {
+			wizardCfg, gitignoreWanted, err := runInitWizard(projectName)
+			if err != nil {
+				if errors.Is(err, errWizardCancelled) {
+					fmt.Println("Aborted.")
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			cfg, wantGitignore = wizardCfg, gitignoreWanted
+		} else {
+			registry, err := scaffold.NewRegistry(viper.GetString("template_catalog_url"))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading template catalog: %v\n", err)
+				os.Exit(1)
+			}
+			templateName, _ := cmd.Flags().GetString("template")
+			tmpl, err := registry.Get(templateName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			cfg = tmpl.Config
+			cfg.Metadata.Name = projectName
+		}
+
+		if err := applySetOverrides(cfg, setOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying --set override: %v\n", err)
+			os.Exit(1)
+		}
+
+		layout, err := config.DetectLayout(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving project layout: %v\n", err)
+			os.Exit(1)
+		}
+		if err := layout.Scaffold(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating project directories: %v\n", err)
+			os.Exit(1)
+		}
+
+		scaffoldProject, _ := cmd.Flags().GetBool("scaffold")
+		if scaffoldProject {
+			cfg, err = scaffold.WriteProjectLayout(cfg, layout)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error scaffolding project layout: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Save configuration
+		if err := config.SaveConfig(cfg, "ai_rulez.yaml"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating configuration file: %v\n", err)
+			os.Exit(1)
+		}
+
+		if wantGitignore {
+			if err := gitignore.UpdateGitignoreFiles("ai_rulez.yaml", cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating .gitignore: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("✓ Initialized new AI rules project: %s\n", projectName)
+		fmt.Println("  - Created ai_rulez.yaml")
+		fmt.Printf("  - Created %s (config dir)\n", layout.ConfigDir)
+		fmt.Printf("  - Created %s (includes dir)\n", layout.IncludesDir)
+		if scaffoldProject {
+			fmt.Println("  - Split rules into includes under the includes dir")
+			fmt.Println("  - Created templates/ with a customizable template per output")
+			fmt.Println("  - Created .github/workflows/ai-rulez.yml and .pre-commit-config.yaml")
+		}
+		fmt.Println("  - Run 'ai-rulez generate' to create rule files")
+	},
+}
+
+// templatesCmd represents the templates command
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Install 'ai-rulez init' templates and inspect built-in output template presets",
+	Long: `Install templates used by 'ai-rulez init' ('templates add'), and list or
+print the output template presets built into the binary ('templates list',
+'templates show'). 'ai-rulez init --list-templates' lists the init templates.`,
+}
+
+// templatesAddCmd represents the templates add subcommand
+var templatesAddCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Download a template and install it for 'ai-rulez init'",
+	Long: `Download a single template from url and install it under
+$XDG_CONFIG_HOME/ai-rulez/templates, overriding any existing template with
+the same name.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		name, err := scaffold.Install(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error installing template: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Installed template %q\n", name)
+	},
+}
+
+// templatesListCmd represents the templates list subcommand
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the first-party output template presets built into the binary",
+	Long: `List the output template presets embedded in the binary (internal/templates/presets)
+- e.g. "cursor", "claude", "windsurf" - any of which an output can select
+with no local template file via 'template: "<name>"'. See 'templates show'
+to inspect and fork one into your repo.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		for _, name := range presets.Names() {
+			fmt.Println(name)
+		}
+	},
+}
+
+// templatesShowCmd represents the templates show subcommand
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a built-in output template preset's source",
+	Long: `Print the raw Go template source of a built-in preset (see 'templates list'),
+so you can copy it into your own @file-referenced template and customize it.`,
 	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		content, ok := presets.Read(args[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown template preset %q (see 'ai-rulez templates list')\n", args[0])
+			os.Exit(1)
+		}
+		fmt.Print(content)
+	},
+}
+
+// listCmd represents the list command group
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List rules, sections, or outputs in a configuration",
+	Long: `List the rules, sections, or outputs defined in a configuration file,
+rendered as text, a table, or structured JSON/YAML via the global
+--output/-o flag.`,
+}
+
+// ruleListResult is `list rules`' output.
+type ruleListResult []config.Rule
+
+func (r ruleListResult) String() string {
+	if len(r) == 0 {
+		return "No rules defined"
+	}
+	var b strings.Builder
+	for _, rule := range r {
+		fmt.Fprintf(&b, "  • %s (priority %d)\n", rule.Name, rule.Priority)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (r ruleListResult) Header() []string { return []string{"NAME", "PRIORITY"} }
+
+func (r ruleListResult) Rows() [][]string {
+	rows := make([][]string, len(r))
+	for i, rule := range r {
+		rows[i] = []string{rule.Name, fmt.Sprintf("%d", rule.Priority)}
+	}
+	return rows
+}
+
+// listRuleCmd represents the list rules subcommand
+var listRuleCmd = &cobra.Command{
+	Use:   "rules [config-file]",
+	Short: "List the rules defined in a configuration",
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		sectionTitle := args[0]
+		cfg := loadConfigForListing(args)
+		mustPrint(ruleListResult(cfg.Rules))
+	},
+}
+
+// sectionListResult is `list sections`' output.
+type sectionListResult []config.Section
+
+func (r sectionListResult) String() string {
+	if len(r) == 0 {
+		return "No sections defined"
+	}
+	var b strings.Builder
+	for _, section := range r {
+		fmt.Fprintf(&b, "  • %s (priority %d)\n", section.Title, section.Priority)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (r sectionListResult) Header() []string { return []string{"TITLE", "PRIORITY"} }
+
+func (r sectionListResult) Rows() [][]string {
+	rows := make([][]string, len(r))
+	for i, section := range r {
+		rows[i] = []string{section.Title, fmt.Sprintf("%d", section.Priority)}
+	}
+	return rows
+}
+
+// listSectionCmd represents the list sections subcommand
+var listSectionCmd = &cobra.Command{
+	Use:   "sections [config-file]",
+	Short: "List the sections defined in a configuration",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForListing(args)
+		mustPrint(sectionListResult(cfg.Sections))
+	},
+}
+
+// outputListResult is `list outputs`' output.
+type outputListResult []config.Output
+
+func (r outputListResult) String() string {
+	if len(r) == 0 {
+		return "No outputs defined"
+	}
+	var b strings.Builder
+	for _, output := range r {
+		fmt.Fprintf(&b, "  • %s\n", output.File)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (r outputListResult) Header() []string { return []string{"FILE", "TEMPLATE"} }
+
+func (r outputListResult) Rows() [][]string {
+	rows := make([][]string, len(r))
+	for i, output := range r {
+		rows[i] = []string{output.File, output.Template}
+	}
+	return rows
+}
+
+// listOutputCmd represents the list outputs subcommand
+var listOutputCmd = &cobra.Command{
+	Use:   "outputs [config-file]",
+	Short: "List the outputs defined in a configuration",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForListing(args)
+		mustPrint(outputListResult(cfg.Outputs))
+	},
+}
+
+// loadConfigForListing resolves and loads the config file a `list`
+// subcommand should read from: args[0] if given, else the same
+// --context/active-context/auto-discover chain resolveConfigFile uses.
+// Exits the process on any error, matching the other listing commands.
+func loadConfigForListing(args []string) *config.Config {
+	var configFile string
+	if len(args) > 0 {
+		configFile = args[0]
+	} else {
+		found, err := resolveConfigFile(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		configFile = found
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		printValidationError(err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add rules or sections to configuration",
+	Long:  `Add new rules or sections to your AI rules configuration file.`,
+}
+
+// addRuleCmd represents the add rule subcommand
+var addRuleCmd = &cobra.Command{
+	Use:   "rule [name]",
+	Short: "Add a new rule to configuration",
+	Long: `Add a new rule to your AI rules configuration file.
+The rule name is provided as an argument, and the content can be provided
+via stdin or will open an editor for you to enter the rule content: the
+editor is used by default when stdin is a TTY, or always/never with
+--editor/--editor=false, and takes extra flags via --editor-args (e.g.
+--editor-args=-w for 'code').
+
+Pass --wizard to be prompted for the name, priority, and content (via
+$EDITOR) interactively instead.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wizard, _ := cmd.Flags().GetBool("wizard")
+		ruleName := ""
+		if len(args) > 0 {
+			ruleName = args[0]
+		}
+		if !wizard && ruleName == "" {
+			fmt.Fprintf(os.Stderr, "Error: a rule name is required unless --wizard is set\n")
+			os.Exit(1)
+		}
 		priority, _ := cmd.Flags().GetInt("priority")
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -428,19 +2152,102 @@ via stdin or will open an editor for you to enter the section content.`,
 			os.Exit(1)
 		}
 
-		// Read content from stdin or prompt
-		fmt.Println("Enter section content (press Ctrl+D when done):")
-		content, err := readFromStdin()
+		var newRule config.Rule
+		if wizard {
+			newRule, err = runAddRuleWizard(ruleName)
+			if err != nil {
+				if errors.Is(err, errWizardCancelled) {
+					fmt.Println("Aborted.")
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			content, err := readOrEditContent(cmd, "ai-rulez-rule-*.md",
+				[]string{fmt.Sprintf("Rule: %s", ruleName), fmt.Sprintf("Priority: %d", priority)}, "",
+				"Enter rule content (press Ctrl+D when done):")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+				os.Exit(1)
+			}
+			newRule = config.Rule{Name: ruleName, Priority: priority, Content: content}
+		}
+		cfg.Rules = append(cfg.Rules, newRule)
+
+		// Save configuration
+		if err := config.SaveConfig(cfg, configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Added rule '%s' with priority %d to %s\n", newRule.Name, newRule.Priority, configFile)
+	},
+}
+
+// addSectionCmd represents the add section subcommand
+var addSectionCmd = &cobra.Command{
+	Use:   "section [title]",
+	Short: "Add a new section to configuration",
+	Long: `Add a new section to your AI rules configuration file.
+The section title is provided as an argument, and the content can be provided
+via stdin or will open an editor for you to enter the section content: the
+editor is used by default when stdin is a TTY, or always/never with
+--editor/--editor=false, and takes extra flags via --editor-args (e.g.
+--editor-args=-w for 'code').
+
+Pass --wizard to be prompted for the title, priority, and content (via
+$EDITOR) interactively instead.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wizard, _ := cmd.Flags().GetBool("wizard")
+		sectionTitle := ""
+		if len(args) > 0 {
+			sectionTitle = args[0]
+		}
+		if !wizard && sectionTitle == "" {
+			fmt.Fprintf(os.Stderr, "Error: a section title is required unless --wizard is set\n")
+			os.Exit(1)
+		}
+		priority, _ := cmd.Flags().GetInt("priority")
+		configFile, _ := cmd.Flags().GetString("config")
+
+		if configFile == "" {
+			foundConfig, err := resolveConfigFile(".")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			configFile = foundConfig
+		}
+
+		// Load existing configuration
+		cfg, err := config.LoadConfig(configFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Add new section
-		newSection := config.Section{
-			Title:    sectionTitle,
-			Priority: priority,
-			Content:  content,
+		var newSection config.Section
+		if wizard {
+			newSection, err = runAddSectionWizard(sectionTitle)
+			if err != nil {
+				if errors.Is(err, errWizardCancelled) {
+					fmt.Println("Aborted.")
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			content, err := readOrEditContent(cmd, "ai-rulez-section-*.md",
+				[]string{fmt.Sprintf("Section: %s", sectionTitle), fmt.Sprintf("Priority: %d", priority)}, "",
+				"Enter section content (press Ctrl+D when done):")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+				os.Exit(1)
+			}
+			newSection = config.Section{Title: sectionTitle, Priority: priority, Content: content}
 		}
 		cfg.Sections = append(cfg.Sections, newSection)
 
@@ -450,7 +2257,7 @@ via stdin or will open an editor for you to enter the section content.`,
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Added section '%s' with priority %d to %s\n", sectionTitle, priority, configFile)
+		fmt.Printf("✓ Added section '%s' with priority %d to %s\n", newSection.Title, newSection.Priority, configFile)
 	},
 }
 
@@ -460,15 +2267,27 @@ var addOutputCmd = &cobra.Command{
 	Short: "Add a new output file to configuration",
 	Long: `Add a new output file to your AI rules configuration.
 The filename is provided as an argument, and you can optionally specify
-a template to use for rendering the output.`,
-	Args: cobra.ExactArgs(1),
+a template to use for rendering the output.
+
+Pass --wizard to be prompted for the filename and template, picked from
+the built-in templates and any @file templates found in the project,
+interactively instead.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		filename := args[0]
+		wizard, _ := cmd.Flags().GetBool("wizard")
+		filename := ""
+		if len(args) > 0 {
+			filename = args[0]
+		}
+		if !wizard && filename == "" {
+			fmt.Fprintf(os.Stderr, "Error: a filename is required unless --wizard is set\n")
+			os.Exit(1)
+		}
 		template, _ := cmd.Flags().GetString("template")
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -483,6 +2302,22 @@ a template to use for rendering the output.`,
 			os.Exit(1)
 		}
 
+		var newOutput config.Output
+		if wizard {
+			newOutput, err = runAddOutputWizard(filename)
+			if err != nil {
+				if errors.Is(err, errWizardCancelled) {
+					fmt.Println("Aborted.")
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			filename = newOutput.File
+		} else {
+			newOutput = config.Output{File: filename, Template: template}
+		}
+
 		// Check if output already exists
 		for _, output := range cfg.Outputs {
 			if output.File == filename {
@@ -491,11 +2326,6 @@ a template to use for rendering the output.`,
 			}
 		}
 
-		// Add new output
-		newOutput := config.Output{
-			File:     filename,
-			Template: template,
-		}
 		cfg.Outputs = append(cfg.Outputs, newOutput)
 
 		// Save configuration
@@ -505,8 +2335,8 @@ a template to use for rendering the output.`,
 		}
 
 		fmt.Printf("✓ Added output '%s'", filename)
-		if template != "" {
-			fmt.Printf(" with template '%s'", template)
+		if newOutput.Template != "" {
+			fmt.Printf(" with template '%s'", newOutput.Template)
 		}
 		fmt.Printf(" to %s\n", configFile)
 	},
@@ -525,8 +2355,11 @@ var updateRuleCmd = &cobra.Command{
 	Short: "Update an existing rule",
 	Long: `Update an existing rule in your AI rules configuration file.
 You can update the content, priority, or both. If no flags are provided,
-you'll be prompted to enter new content via stdin.`,
-	Args: cobra.ExactArgs(1),
+you'll be prompted to enter new content via stdin, or via $EDITOR when
+stdin is a TTY (force with --editor/--editor=false, pass extra flags with
+--editor-args).`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRuleNames,
 	Run: func(cmd *cobra.Command, args []string) {
 		ruleName := args[0]
 		newContent, _ := cmd.Flags().GetString("content")
@@ -534,7 +2367,7 @@ you'll be prompted to enter new content via stdin.`,
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -565,15 +2398,29 @@ you'll be prompted to enter new content via stdin.`,
 
 		// Update content if not provided via flag
 		if newContent == "" && priority == 0 {
-			fmt.Printf("Current content: %s\n", cfg.Rules[ruleIndex].Content)
-			fmt.Println("Enter new rule content (press Ctrl+D when done, or press Enter to keep current):")
-			content, err := readFromStdin()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
-				os.Exit(1)
-			}
-			if strings.TrimSpace(content) != "" {
-				newContent = content
+			if useEditorForContent(cmd) {
+				editorArgs, _ := cmd.Flags().GetStringSlice("editor-args")
+				edited, changed, err := editInEditor("ai-rulez-rule-*.md",
+					[]string{fmt.Sprintf("Rule: %s", ruleName), fmt.Sprintf("Priority: %d", cfg.Rules[ruleIndex].Priority)},
+					cfg.Rules[ruleIndex].Content, editorArgs)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+					os.Exit(1)
+				}
+				if changed {
+					newContent = edited
+				}
+			} else {
+				fmt.Printf("Current content: %s\n", cfg.Rules[ruleIndex].Content)
+				fmt.Println("Enter new rule content (press Ctrl+D when done, or press Enter to keep current):")
+				content, err := readFromStdin()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+					os.Exit(1)
+				}
+				if strings.TrimSpace(content) != "" {
+					newContent = content
+				}
 			}
 		}
 
@@ -601,7 +2448,9 @@ var updateSectionCmd = &cobra.Command{
 	Short: "Update an existing section",
 	Long: `Update an existing section in your AI rules configuration file.
 You can update the content, priority, or both. If no flags are provided,
-you'll be prompted to enter new content via stdin.`,
+you'll be prompted to enter new content via stdin, or via $EDITOR when
+stdin is a TTY (force with --editor/--editor=false, pass extra flags with
+--editor-args).`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		sectionTitle := args[0]
@@ -610,7 +2459,7 @@ you'll be prompted to enter new content via stdin.`,
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -641,15 +2490,29 @@ you'll be prompted to enter new content via stdin.`,
 
 		// Update content if not provided via flag
 		if newContent == "" && priority == 0 {
-			fmt.Printf("Current content: %s\n", cfg.Sections[sectionIndex].Content)
-			fmt.Println("Enter new section content (press Ctrl+D when done, or press Enter to keep current):")
-			content, err := readFromStdin()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
-				os.Exit(1)
-			}
-			if strings.TrimSpace(content) != "" {
-				newContent = content
+			if useEditorForContent(cmd) {
+				editorArgs, _ := cmd.Flags().GetStringSlice("editor-args")
+				edited, changed, err := editInEditor("ai-rulez-section-*.md",
+					[]string{fmt.Sprintf("Section: %s", sectionTitle), fmt.Sprintf("Priority: %d", cfg.Sections[sectionIndex].Priority)},
+					cfg.Sections[sectionIndex].Content, editorArgs)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+					os.Exit(1)
+				}
+				if changed {
+					newContent = edited
+				}
+			} else {
+				fmt.Printf("Current content: %s\n", cfg.Sections[sectionIndex].Content)
+				fmt.Println("Enter new section content (press Ctrl+D when done, or press Enter to keep current):")
+				content, err := readFromStdin()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading content: %v\n", err)
+					os.Exit(1)
+				}
+				if strings.TrimSpace(content) != "" {
+					newContent = content
+				}
 			}
 		}
 
@@ -677,14 +2540,15 @@ var updateOutputCmd = &cobra.Command{
 	Short: "Update an existing output file configuration",
 	Long: `Update an existing output file in your AI rules configuration.
 You can update the template used for the output file.`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeOutputFiles,
 	Run: func(cmd *cobra.Command, args []string) {
 		filename := args[0]
 		template, _ := cmd.Flags().GetString("template")
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -735,16 +2599,17 @@ var deleteCmd = &cobra.Command{
 
 // deleteRuleCmd represents the delete rule subcommand
 var deleteRuleCmd = &cobra.Command{
-	Use:   "rule [name]",
-	Short: "Delete an existing rule",
-	Long:  `Delete an existing rule from your AI rules configuration file.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "rule [name]",
+	Short:             "Delete an existing rule",
+	Long:              `Delete an existing rule from your AI rules configuration file.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRuleNames,
 	Run: func(cmd *cobra.Command, args []string) {
 		ruleName := args[0]
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -788,16 +2653,17 @@ var deleteRuleCmd = &cobra.Command{
 
 // deleteSectionCmd represents the delete section subcommand
 var deleteSectionCmd = &cobra.Command{
-	Use:   "section [title]",
-	Short: "Delete an existing section",
-	Long:  `Delete an existing section from your AI rules configuration file.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "section [title]",
+	Short:             "Delete an existing section",
+	Long:              `Delete an existing section from your AI rules configuration file.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSectionTitles,
 	Run: func(cmd *cobra.Command, args []string) {
 		sectionTitle := args[0]
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -841,16 +2707,17 @@ var deleteSectionCmd = &cobra.Command{
 
 // deleteOutputCmd represents the delete output subcommand
 var deleteOutputCmd = &cobra.Command{
-	Use:   "output [filename]",
-	Short: "Delete an existing output file configuration",
-	Long:  `Delete an existing output file from your AI rules configuration.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "output [filename]",
+	Short:             "Delete an existing output file configuration",
+	Long:              `Delete an existing output file from your AI rules configuration.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeOutputFiles,
 	Run: func(cmd *cobra.Command, args []string) {
 		filename := args[0]
 		configFile, _ := cmd.Flags().GetString("config")
 
 		if configFile == "" {
-			foundConfig, err := config.FindConfigFile(".")
+			foundConfig, err := resolveConfigFile(".")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
@@ -896,7 +2763,39 @@ func init() {
 	generateCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Process all config files recursively")
 	generateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be generated without writing files")
 	generateCmd.Flags().BoolVar(&updateGitignore, "update-gitignore", false, "Update .gitignore files to include generated output files")
-	initCmd.Flags().StringP("template", "t", "basic", "Template to use (basic, react, typescript)")
+	generateCmd.Flags().BoolVar(&updateIncludes, "update", false, "Accept and re-pin remote includes whose content no longer matches the lockfile")
+	generateCmd.Flags().BoolVar(&updateIncludes, "refresh-includes", false, "Alias for --update")
+	generateCmd.Flags().StringSliceVar(&tags, "tag", nil, "Tags to activate rules, sections, and includes gated by a 'when:' selector (repeatable)")
+	generateCmd.Flags().StringSliceVar(&excludes, "exclude", nil, "Glob patterns (e.g. '**/node_modules/**') to prune from the recursive config search (repeatable)")
+	generateCmd.Flags().StringArrayVar(&setOverrides, "set", nil, "Override a config value, e.g. --set METADATA__VERSION=1.2.3 (repeatable; same KEY shape as AI_RULEZ_<KEY> env vars)")
+	generateCmd.Flags().BoolVar(&noCache, "no-cache", false, "Disable the render cache for this run, even if cache.enabled is set in config")
+	generateCmd.Flags().StringVar(&maxAgeOverride, "max-age", "", "Override cache.maxAge for this run (e.g. --max-age 1h); implies the cache is enabled")
+	generateCmd.Flags().BoolVar(&watchFlag, "watch", false, "Keep running and regenerate on every change, like 'ai-rulez watch' (combine with -r/--recursive to watch every discovered config at once)")
+	validateCmd.Flags().Bool("explain-merge", false, "Show which .local.yaml patch contributed each touched rule/section field")
+	validateCmd.Flags().StringArrayVar(&setOverrides, "set", nil, "Override a config value before validating, e.g. --set METADATA__VERSION=1.2.3 (repeatable; same KEY shape as AI_RULEZ_<KEY> env vars)")
+	lintCmd.Flags().StringArrayVar(&setOverrides, "set", nil, "Override a config value before linting, e.g. --set METADATA__VERSION=1.2.3 (repeatable; same KEY shape as AI_RULEZ_<KEY> env vars)")
+	lintCmd.Flags().String("fail-on", "error", "Lowest severity that makes lint exit non-zero: error or warning")
+	lintCmd.Flags().String("format", "text", "Output format: text or json")
+	lintCmd.Flags().Int("max-content-length", lint.DefaultMaxContentLength, "Longest a rule/section's content may be before AIR005 flags it")
+	diffCmd.Flags().StringArrayVar(&setOverrides, "set", nil, "Override a config value before diffing, e.g. --set METADATA__VERSION=1.2.3 (repeatable; same KEY shape as AI_RULEZ_<KEY> env vars)")
+	diffCmd.Flags().String("format", "unified", "Output format: unified, json, yaml, or table")
+	diffCmd.Flags().Bool("exit-code", false, "Exit 1 if any output would change, like 'git diff --exit-code'")
+	checkCmd.Flags().StringArrayVar(&setOverrides, "set", nil, "Override a config value before checking, e.g. --set METADATA__VERSION=1.2.3 (repeatable; same KEY shape as AI_RULEZ_<KEY> env vars)")
+	checkCmd.Flags().Bool("json", false, "Print {file, existed, changed, unified} objects instead of a colorized/plain diff")
+	checkCmd.Flags().Bool("exit-code", true, "Exit 1 if any output is stale")
+	contextAddCmd.Flags().StringSlice("profile", nil, "Profile(s) to record with the context, shown by 'context show'; no command applies them (repeatable)")
+	contextAddCmd.Flags().String("output-dir", "", "Output base directory to record with the context, shown by 'context show'; generated output is rooted at the config file's directory regardless")
+	_ = contextAddCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+
+	mcpCmd.Flags().String("transport", "stdio", "Transport to serve the MCP server over: stdio or http")
+	mcpCmd.Flags().String("addr", "localhost:8080", "Address to listen on when --transport=http")
+	mcpCmd.Flags().String("auth-token", "", "Bearer token required on HTTP requests when --transport=http (unset disables auth)")
+	initCmd.Flags().StringP("template", "t", "basic", "Template to use (see --list-templates for available names)")
+	initCmd.Flags().BoolVar(&listTemplates, "list-templates", false, "List available templates instead of initializing a project")
+	initCmd.Flags().BoolP("wizard", "i", false, "Interactively prompt for project details instead of using --template")
+	initCmd.Flags().Bool("scaffold", false, "Bootstrap a full project layout (split rules/, templates/, CI workflow, pre-commit config) instead of a single YAML file")
+	initCmd.Flags().StringArrayVar(&setOverrides, "set", nil, "Override a template's config value, e.g. --set METADATA__VERSION=1.2.3 (repeatable; same KEY shape as AI_RULEZ_<KEY> env vars)")
+	_ = initCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
 
 	// Add subcommands to add command
 	addCmd.AddCommand(addRuleCmd)
@@ -913,153 +2812,57 @@ func init() {
 	deleteCmd.AddCommand(deleteSectionCmd)
 	deleteCmd.AddCommand(deleteOutputCmd)
 
+	// Add flags for watch command
+	watchCmd.Flags().StringP("config", "c", "", "Config file to watch (auto-discover if not provided)")
+	watchCmd.Flags().String("exec", "", "Shell command to run after each successful regeneration (e.g. 'git add .')")
+
+	// Add flags for docs command
+	docsCmd.Flags().String("format", "man", "Documentation format to generate: man, md, or rest")
+	docsCmd.Flags().String("output-dir", "./docs", "Directory to write generated documentation into")
+
 	// Add flags for add rule command
 	addRuleCmd.Flags().IntP("priority", "p", 5, "Priority level for the rule (1-10)")
 	addRuleCmd.Flags().StringP("config", "c", "", "Config file to add rule to (auto-discover if not provided)")
+	addRuleCmd.Flags().BoolP("wizard", "i", false, "Interactively prompt for name, priority, and content")
+	addRuleCmd.Flags().Bool("editor", false, "Open $EDITOR/$VISUAL for content instead of reading stdin (default: on when stdin is a TTY)")
+	addRuleCmd.Flags().StringSlice("editor-args", nil, "Extra arguments to pass to the editor, e.g. -w for 'code'")
 
 	// Add flags for add section command
 	addSectionCmd.Flags().IntP("priority", "p", 5, "Priority level for the section")
 	addSectionCmd.Flags().StringP("config", "c", "", "Config file to add section to (auto-discover if not provided)")
+	addSectionCmd.Flags().BoolP("wizard", "i", false, "Interactively prompt for title, priority, and content")
+	addSectionCmd.Flags().Bool("editor", false, "Open $EDITOR/$VISUAL for content instead of reading stdin (default: on when stdin is a TTY)")
+	addSectionCmd.Flags().StringSlice("editor-args", nil, "Extra arguments to pass to the editor, e.g. -w for 'code'")
 
 	// Add flags for add output command
 	addOutputCmd.Flags().StringP("template", "t", "", "Template to use for the output (optional)")
 	addOutputCmd.Flags().StringP("config", "c", "", "Config file to add output to (auto-discover if not provided)")
+	addOutputCmd.Flags().BoolP("wizard", "i", false, "Interactively prompt for filename and template")
+	_ = addOutputCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
 
 	// Add flags for update rule command
 	updateRuleCmd.Flags().StringP("content", "", "", "New content for the rule (optional, will prompt if not provided)")
 	updateRuleCmd.Flags().IntP("priority", "p", 0, "New priority level for the rule (optional)")
 	updateRuleCmd.Flags().StringP("config", "c", "", "Config file to update (auto-discover if not provided)")
+	updateRuleCmd.Flags().Bool("editor", false, "Open $EDITOR/$VISUAL for content instead of reading stdin (default: on when stdin is a TTY)")
+	updateRuleCmd.Flags().StringSlice("editor-args", nil, "Extra arguments to pass to the editor, e.g. -w for 'code'")
 
 	// Add flags for update section command
 	updateSectionCmd.Flags().StringP("content", "", "", "New content for the section (optional, will prompt if not provided)")
 	updateSectionCmd.Flags().IntP("priority", "p", 0, "New priority level for the section (optional)")
 	updateSectionCmd.Flags().StringP("config", "c", "", "Config file to update (auto-discover if not provided)")
+	updateSectionCmd.Flags().Bool("editor", false, "Open $EDITOR/$VISUAL for content instead of reading stdin (default: on when stdin is a TTY)")
+	updateSectionCmd.Flags().StringSlice("editor-args", nil, "Extra arguments to pass to the editor, e.g. -w for 'code'")
 
 	// Add flags for update output command
 	updateOutputCmd.Flags().StringP("template", "t", "", "New template for the output (required)")
-	updateOutputCmd.Flags().StringP("config", "c", "", "Config file to update (auto-discover if not provided)")
-	_ = updateOutputCmd.MarkFlagRequired("template")
-
-	// Add flags for delete commands
-	deleteRuleCmd.Flags().StringP("config", "c", "", "Config file to delete from (auto-discover if not provided)")
-	deleteSectionCmd.Flags().StringP("config", "c", "", "Config file to delete from (auto-discover if not provided)")
-	deleteOutputCmd.Flags().StringP("config", "c", "", "Config file to delete from (auto-discover if not provided)")
-}
-
-func createBasicTemplate(projectName string) *config.Config {
-	return &config.Config{
-		Metadata: config.Metadata{
-			Name:        projectName,
-			Version:     "1.0.0",
-			Description: "AI assistant rules configuration",
-		},
-		Outputs: []config.Output{
-			{File: "claude.md"},
-			{File: ".cursorrules"},
-			{File: ".windsurfrules"},
-		},
-		Rules: []config.Rule{
-			{
-				Name:     "Code Quality",
-				Priority: 10,
-				Content:  "Write clean, readable, and maintainable code following best practices.",
-			},
-			{
-				Name:     "Documentation",
-				Priority: 5,
-				Content:  "Document functions, classes, and complex logic with clear comments.",
-			},
-			{
-				Name:     "Testing",
-				Priority: 5,
-				Content:  "Write unit tests for all new functionality.",
-			},
-		},
-	}
-}
-
-func createReactTemplate(projectName string) *config.Config {
-	return &config.Config{
-		Metadata: config.Metadata{
-			Name:        projectName,
-			Version:     "1.0.0",
-			Description: "React project AI assistant rules",
-		},
-		Outputs: []config.Output{
-			{File: "claude.md"},
-			{File: ".cursorrules"},
-			{File: ".windsurfrules"},
-		},
-		Rules: []config.Rule{
-			{
-				Name:     "React Best Practices",
-				Priority: 10,
-				Content:  "Use functional components with hooks. Prefer composition over inheritance.",
-			},
-			{
-				Name:     "Component Structure",
-				Priority: 10,
-				Content:  "Keep components small and focused. Extract custom hooks for reusable logic.",
-			},
-			{
-				Name:     "State Management",
-				Priority: 5,
-				Content:  "Use useState for local state, useContext for shared state, consider Redux for complex apps.",
-			},
-			{
-				Name:     "Performance",
-				Priority: 5,
-				Content:  "Use React.memo, useMemo, and useCallback to optimize performance when needed.",
-			},
-			{
-				Name:     "Testing",
-				Priority: 5,
-				Content:  "Write unit tests with React Testing Library. Test behavior, not implementation.",
-			},
-		},
-	}
-}
+	updateOutputCmd.Flags().StringP("config", "c", "", "Config file to update (auto-discover if not provided)")
+	_ = updateOutputCmd.MarkFlagRequired("template")
 
-func createTypescriptTemplate(projectName string) *config.Config {
-	return &config.Config{
-		Metadata: config.Metadata{
-			Name:        projectName,
-			Version:     "1.0.0",
-			Description: "TypeScript project AI assistant rules",
-		},
-		Outputs: []config.Output{
-			{File: "claude.md"},
-			{File: ".cursorrules"},
-			{File: ".windsurfrules"},
-		},
-		Rules: []config.Rule{
-			{
-				Name:     "Type Safety",
-				Priority: 10,
-				Content:  "Use strict TypeScript settings. Avoid 'any' type unless absolutely necessary.",
-			},
-			{
-				Name:     "Interface Design",
-				Priority: 10,
-				Content:  "Define clear interfaces for data structures. Use union types for controlled variations.",
-			},
-			{
-				Name:     "Generic Programming",
-				Priority: 5,
-				Content:  "Use generics to create reusable, type-safe functions and classes.",
-			},
-			{
-				Name:     "Error Handling",
-				Priority: 5,
-				Content:  "Use Result/Option patterns or proper error types instead of throwing exceptions.",
-			},
-			{
-				Name:     "Documentation",
-				Priority: 3,
-				Content:  "Use TSDoc comments for public APIs. Document complex type definitions.",
-			},
-		},
-	}
+	// Add flags for delete commands
+	deleteRuleCmd.Flags().StringP("config", "c", "", "Config file to delete from (auto-discover if not provided)")
+	deleteSectionCmd.Flags().StringP("config", "c", "", "Config file to delete from (auto-discover if not provided)")
+	deleteOutputCmd.Flags().StringP("config", "c", "", "Config file to delete from (auto-discover if not provided)")
 }
 
 // listProfilesCmd represents the list-profiles command
@@ -1080,39 +2883,243 @@ Use profiles in your configuration with:
   # or
   profile: ["web-app", "api"]`,
 	Run: func(cmd *cobra.Command, args []string) {
-		profiles, err := config.ListAvailableProfiles()
+		names, err := config.ListAvailableProfiles()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error listing profiles: %v\n", err)
 			os.Exit(1)
 		}
 
-		if len(profiles) == 0 {
-			fmt.Println("No profiles available")
+		result := make(profileListResult, 0, len(names))
+		for _, name := range names {
+			profile, err := config.LoadProfile(name)
+			if err != nil {
+				result = append(result, profileSummary{Name: name, Error: err.Error()})
+				continue
+			}
+			description := profile.Metadata.Description
+			if description == "" {
+				description = "No description available"
+			}
+			result = append(result, profileSummary{Name: name, Description: description, Rules: len(profile.Rules)})
+		}
+
+		mustPrint(result)
+	},
+}
+
+// profileSummary is one entry of `list-profiles` output: a profile's name,
+// description, rule count, and (if it failed to load) the error instead.
+type profileSummary struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Rules       int    `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Error       string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// profileListResult is `list-profiles`' output, rendered as text (the
+// original human-readable listing), a table, or structured JSON/YAML via
+// the global --output flag.
+type profileListResult []profileSummary
+
+func (r profileListResult) String() string {
+	if len(r) == 0 {
+		return "No profiles available"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Available profiles (%d):\n\n", len(r))
+	for _, p := range r {
+		if p.Error != "" {
+			fmt.Fprintf(&b, "  • %s (error loading: %s)\n", p.Name, p.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "  • %s\n", p.Name)
+		fmt.Fprintf(&b, "    %s\n", p.Description)
+		fmt.Fprintf(&b, "    Rules: %d\n\n", p.Rules)
+	}
+	fmt.Fprintln(&b, "Usage:")
+	fmt.Fprintln(&b, "  profile: \"web-app\"           # Single profile")
+	fmt.Fprint(&b, "  profile: [\"web-app\", \"api\"]   # Multiple profiles")
+	return b.String()
+}
+
+func (r profileListResult) Header() []string { return []string{"NAME", "DESCRIPTION", "RULES"} }
+
+func (r profileListResult) Rows() [][]string {
+	rows := make([][]string, len(r))
+	for i, p := range r {
+		if p.Error != "" {
+			rows[i] = []string{p.Name, "error: " + p.Error, ""}
+			continue
+		}
+		rows[i] = []string{p.Name, p.Description, fmt.Sprintf("%d", p.Rules)}
+	}
+	return rows
+}
+
+// contextCmd represents the context command group
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manage named contexts for switching between configurations",
+	Long: `Manage named contexts, each mapping a short name to a config file, a
+default set of profiles, and an optional output base directory. This is
+borrowed from how kubectl/metalctl manage multiple cluster contexts: once a
+context is active, generate/add/update/delete/mcp resolve their config file
+from it instead of requiring --config on every invocation.
+
+Contexts are stored per-user at $XDG_CONFIG_HOME/ai-rulez/contexts.yaml (or
+the platform config directory equivalent), so they persist across projects
+and shells. Use --context on any command to override the active context for
+a single invocation without switching it.`,
+}
+
+// contextListCmd lists known contexts
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List known contexts",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		store, err := rulectx.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading contexts: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(store.Contexts) == 0 {
+			fmt.Println("No contexts defined. Create one with 'ai-rulez context add <name> <config-file>'.")
 			return
 		}
 
-		fmt.Printf("Available profiles (%d):\n\n", len(profiles))
+		names := make([]string, 0, len(store.Contexts))
+		for name := range store.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 
-		for _, profileName := range profiles {
-			profile, err := config.LoadProfile(profileName)
-			if err != nil {
-				fmt.Printf("  • %s (error loading: %v)\n", profileName, err)
-				continue
+		for _, name := range names {
+			ctx := store.Contexts[name]
+			marker := "  "
+			if name == store.Current {
+				marker = "* "
 			}
+			fmt.Printf("%s%s\t%s\n", marker, name, ctx.ConfigFile)
+		}
+	},
+}
 
-			description := "No description available"
-			if profile.Metadata.Description != "" {
-				description = profile.Metadata.Description
-			}
+// contextUseCmd switches the active context
+var contextUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Switch the active context",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		store, err := rulectx.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading contexts: %v\n", err)
+			os.Exit(1)
+		}
+		if err := store.Use(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := store.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving contexts: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Switched to context %q\n", args[0])
+	},
+}
+
+// contextShowCmd prints the details of a context
+var contextShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a context's details (defaults to the active context)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		store, err := rulectx.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading contexts: %v\n", err)
+			os.Exit(1)
+		}
+
+		name := store.Current
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if name == "" {
+			fmt.Fprintln(os.Stderr, "Error: no active context and no name given")
+			os.Exit(1)
+		}
+
+		ctx, err := store.Get(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Name: %s\n", name)
+		fmt.Printf("Config file: %s\n", ctx.ConfigFile)
+		if len(ctx.Profiles) > 0 {
+			fmt.Printf("Profiles: %s\n", strings.Join(ctx.Profiles, ", "))
+		}
+		if ctx.OutputDir != "" {
+			fmt.Printf("Output dir: %s\n", ctx.OutputDir)
+		}
+	},
+}
+
+// contextAddCmd defines or replaces a context
+var contextAddCmd = &cobra.Command{
+	Use:   "add <name> <config-file>",
+	Short: "Define or replace a context",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, configFile := args[0], args[1]
+
+		absConfig, err := filepath.Abs(configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving %q: %v\n", configFile, err)
+			os.Exit(1)
+		}
 
-			fmt.Printf("  • %s\n", profileName)
-			fmt.Printf("    %s\n", description)
-			fmt.Printf("    Rules: %d\n\n", len(profile.Rules))
+		profiles, _ := cmd.Flags().GetStringSlice("profile")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+
+		store, err := rulectx.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading contexts: %v\n", err)
+			os.Exit(1)
+		}
+		store.Add(name, &rulectx.Context{ConfigFile: absConfig, Profiles: profiles, OutputDir: outputDir})
+		if err := store.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving contexts: %v\n", err)
+			os.Exit(1)
 		}
 
-		fmt.Println("Usage:")
-		fmt.Println("  profile: \"web-app\"           # Single profile")
-		fmt.Println("  profile: [\"web-app\", \"api\"]   # Multiple profiles")
+		fmt.Printf("✓ Added context %q -> %s\n", name, absConfig)
+	},
+}
+
+// contextRemoveCmd deletes a context
+var contextRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a context",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		store, err := rulectx.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading contexts: %v\n", err)
+			os.Exit(1)
+		}
+		if err := store.Remove(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := store.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving contexts: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Removed context %q\n", args[0])
 	},
 }
 
@@ -1120,46 +3127,140 @@ Use profiles in your configuration with:
 var mcpCmd = &cobra.Command{
 	Use:   "mcp",
 	Short: "Start MCP server for AI assistant integration",
-	Long: `Start an MCP (Model Context Protocol) server that exposes ai-rulez functionality 
+	Long: `Start an MCP (Model Context Protocol) server that exposes ai-rulez functionality
 to AI assistants like Claude Desktop, Cursor, and other MCP-compatible tools.
 
-The server runs in stdio mode and provides tools for:
+The server provides tools for:
 - Retrieving rules and sections
 - Generating output files
-- Validating configurations
+- Validating and linting configurations
 - Listing available templates
 
+With the default --transport=stdio, the server runs in stdio mode,
+communicating over stdin/stdout for an AI assistant that spawns it as a
+local subprocess.
+
+With --transport=http, the same tools are served over HTTP with Server-Sent
+Events at --addr (default localhost:8080), so remote assistants and
+multi-user editors can connect to one long-running instance - e.g. one
+running in a repo's dev container - instead of each spawning their own
+stdio subprocess. A GET /healthz endpoint always returns 200 for readiness
+probes. With --auth-token set, every other request must carry a matching
+"Authorization: Bearer <token>" header.
+
 Configure in your AI assistant by adding this server to the MCP configuration.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runMCPServer()
+		transport, _ := cmd.Flags().GetString("transport")
+		addr, _ := cmd.Flags().GetString("addr")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		runMCPServer(transport, addr, authToken)
 	},
 }
 
-func runMCPServer() {
+func runMCPServer(transport, addr, authToken string) {
 	// Create MCP server
 	s := server.NewMCPServer(
 		"ai-rulez",
 		Version,
 		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(true, true),
 	)
 
 	// Add ai-rulez tools
 	addAIRulezTools(s)
 
-	// Start stdio server
-	if err := server.ServeStdio(s); err != nil {
+	switch transport {
+	case "", "stdio":
+		if err := server.ServeStdio(s); err != nil {
+			fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
+			os.Exit(1)
+		}
+	case "http":
+		runMCPServerHTTP(s, addr, authToken)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --transport %q: expected stdio or http\n", transport)
+		os.Exit(1)
+	}
+}
+
+// runMCPServerHTTP serves s over HTTP+SSE at addr: GET /sse opens the event
+// stream, POST /message carries client requests, and GET /healthz always
+// reports ready for orchestrators that need a plain liveness check. When
+// authToken is non-empty, every request except /healthz must carry a
+// matching "Authorization: Bearer <token>" header.
+func runMCPServerHTTP(s *server.MCPServer, addr, authToken string) {
+	sse := server.NewSSEServer(s, server.WithBaseURL("http://"+addr))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.Handle("/", requireBearerToken(authToken, sse))
+
+	fmt.Printf("MCP server listening on http://%s (HTTP+SSE transport)\n", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil { //nolint:gosec // dev-facing MCP server, timeouts aren't a priority here
 		fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// requireBearerToken wraps next with a minimal "Authorization: Bearer
+// <token>" check. An empty token disables the check entirely, matching
+// the default (auth-free, loopback-only) stdio experience.
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// configResourceURI identifies the subscribable "current config" resource:
+// agents call resources/subscribe on it to receive notifications when
+// watch_config sees the underlying file reloaded.
+const configResourceURI = "airulez://config"
+
+// mcpServer is the running MCP server, stashed by addAIRulezTools so
+// runConfigWatch can broadcast resource-update notifications from a
+// background goroutine that isn't itself a tool handler.
+var mcpServer *server.MCPServer
+
+// activeConfigWatches tracks the config.Watcher goroutines started by
+// handleWatchConfig, keyed by absolute config file path, so a second
+// watch_config call is a no-op and handleUnwatchConfig can find and stop
+// the right one.
+var (
+	activeConfigWatchesMu sync.Mutex
+	activeConfigWatches   = map[string]context.CancelFunc{}
+)
+
 func addAIRulezTools(s *server.MCPServer) {
+	mcpServer = s
+
+	configResource := mcp.NewResource(configResourceURI, "ai-rulez config",
+		mcp.WithResourceDescription("The currently active/discovered ai-rulez configuration, as JSON. Subscribe to it to be notified when watch_config sees it reloaded."),
+		mcp.WithMIMEType("application/json"),
+	)
+	s.AddResource(configResource, handleConfigResource)
+
 	// Tool: Get rules
 	getRulesTool := mcp.NewTool("get_rules",
 		mcp.WithDescription("Get AI assistant rules from configuration"),
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 		mcp.WithNumber("min_priority",
 			mcp.Description("Minimum priority level to include (optional)"),
 		),
@@ -1175,6 +3276,12 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 	)
 	s.AddTool(getSectionsTool, handleGetSections)
 
@@ -1184,6 +3291,12 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 		mcp.WithBoolean("dry_run",
 			mcp.Description("Show what would be generated without writing files (default: false)"),
 		),
@@ -1196,9 +3309,51 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 	)
 	s.AddTool(validateTool, handleValidate)
 
+	// Tool: Lint config
+	lintTool := mcp.NewTool("lint_config",
+		mcp.WithDescription("Run deep structural and content checks (duplicate names, priority range, template errors, etc.) on AI rules configuration"),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithNumber("max_content_length",
+			mcp.Description("Longest a rule/section's content may be before it's flagged (optional, default 4000)"),
+		),
+	)
+	s.AddTool(lintTool, handleLintConfig)
+
+	// Tool: Diff output
+	diffTool := mcp.NewTool("diff_output",
+		mcp.WithDescription("Preview what generate would change by diffing rendered outputs against what's on disk"),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithString("outputs",
+			mcp.Description("Comma-separated output filenames to limit the diff to (optional, defaults to all outputs)"),
+		),
+	)
+	s.AddTool(diffTool, handleDiffOutput)
+
 	// Tool: List templates
 	templatesTool := mcp.NewTool("list_templates",
 		mcp.WithDescription("List available project templates for initialization"),
@@ -1222,6 +3377,15 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
 	)
 	s.AddTool(addRuleTool, handleAddRule)
 
@@ -1242,6 +3406,15 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
 	)
 	s.AddTool(addSectionTool, handleAddSection)
 
@@ -1258,6 +3431,15 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
 	)
 	s.AddTool(addOutputTool, handleAddOutput)
 
@@ -1277,6 +3459,15 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
 	)
 	s.AddTool(updateRuleTool, handleUpdateRule)
 
@@ -1296,75 +3487,428 @@ func addAIRulezTools(s *server.MCPServer) {
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
+	)
+	s.AddTool(updateSectionTool, handleUpdateSection)
+
+	// Tool: Update output
+	updateOutputTool := mcp.NewTool("update_output",
+		mcp.WithDescription("Update an existing output file in the configuration"),
+		mcp.WithString("filename",
+			mcp.Required(),
+			mcp.Description("The filename of the output to update"),
+		),
+		mcp.WithString("template",
+			mcp.Required(),
+			mcp.Description("New template for the output"),
+		),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
+	)
+	s.AddTool(updateOutputTool, handleUpdateOutput)
+
+	// Tool: Delete rule
+	deleteRuleTool := mcp.NewTool("delete_rule",
+		mcp.WithDescription("Delete an existing rule from the configuration"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the rule to delete"),
+		),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
+	)
+	s.AddTool(deleteRuleTool, handleDeleteRule)
+
+	// Tool: Delete section
+	deleteSectionTool := mcp.NewTool("delete_section",
+		mcp.WithDescription("Delete an existing section from the configuration"),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.Description("The title of the section to delete"),
+		),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
+	)
+	s.AddTool(deleteSectionTool, handleDeleteSection)
+
+	// Tool: Delete output
+	deleteOutputTool := mcp.NewTool("delete_output",
+		mcp.WithDescription("Delete an existing output file from the configuration"),
+		mcp.WithString("filename",
+			mcp.Required(),
+			mcp.Description("The filename of the output to delete"),
+		),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the mutation and preview the result without writing to disk (default: false)"),
+		),
+	)
+	s.AddTool(deleteOutputTool, handleDeleteOutput)
+
+	// Tool: Apply changeset
+	applyChangesetTool := mcp.NewTool("apply_changeset",
+		mcp.WithDescription("Apply a batch of add/update/delete operations on rules, sections, and outputs as a single atomic, validated write, instead of one MCP call per mutation"),
+		mcp.WithString("operations",
+			mcp.Required(),
+			mcp.Description(`JSON array of operations, applied in order to an in-memory copy of the config. Each entry looks like {"op": "add_rule|update_rule|delete_rule|add_section|update_section|delete_section|add_output|update_output|delete_output", "name": "...", "title": "...", "filename": "...", "content": "...", "priority": 5, "template": "..."} (name for rules, title for sections, filename/template for outputs). Any op failure, or the resulting config failing validation, rolls back the whole changeset and writes nothing.`),
+		),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
+	)
+	s.AddTool(applyChangesetTool, handleApplyChangeset)
+
+	// Tool: Watch config
+	watchConfigTool := mcp.NewTool("watch_config",
+		mcp.WithDescription("Start watching a configuration file for out-of-band changes (e.g. a human editing it), notifying subscribers of the airulez://config resource with an added/removed/modified diff each time it's reloaded"),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 	)
-	s.AddTool(updateSectionTool, handleUpdateSection)
+	s.AddTool(watchConfigTool, handleWatchConfig)
 
-	// Tool: Update output
-	updateOutputTool := mcp.NewTool("update_output",
-		mcp.WithDescription("Update an existing output file in the configuration"),
-		mcp.WithString("filename",
-			mcp.Required(),
-			mcp.Description("The filename of the output to update"),
+	// Tool: Unwatch config
+	unwatchConfigTool := mcp.NewTool("unwatch_config",
+		mcp.WithDescription("Stop watching a configuration file previously started with watch_config"),
+		mcp.WithString("config_file",
+			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
-		mcp.WithString("template",
-			mcp.Required(),
-			mcp.Description("New template for the output"),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
 		),
+	)
+	s.AddTool(unwatchConfigTool, handleUnwatchConfig)
+
+	// Tool: Undo
+	undoTool := mcp.NewTool("undo",
+		mcp.WithDescription("Revert the most recent add/update/delete mutation recorded in the configuration's history"),
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 	)
-	s.AddTool(updateOutputTool, handleUpdateOutput)
+	s.AddTool(undoTool, handleUndo)
 
-	// Tool: Delete rule
-	deleteRuleTool := mcp.NewTool("delete_rule",
-		mcp.WithDescription("Delete an existing rule from the configuration"),
-		mcp.WithString("name",
-			mcp.Required(),
-			mcp.Description("The name of the rule to delete"),
-		),
+	// Tool: Redo
+	redoTool := mcp.NewTool("redo",
+		mcp.WithDescription("Re-apply the most recently undone mutation"),
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 	)
-	s.AddTool(deleteRuleTool, handleDeleteRule)
+	s.AddTool(redoTool, handleRedo)
 
-	// Tool: Delete section
-	deleteSectionTool := mcp.NewTool("delete_section",
-		mcp.WithDescription("Delete an existing section from the configuration"),
-		mcp.WithString("title",
-			mcp.Required(),
-			mcp.Description("The title of the section to delete"),
-		),
+	// Tool: List history
+	listHistoryTool := mcp.NewTool("list_history",
+		mcp.WithDescription("List the configuration's recorded mutation history, most recent last"),
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 	)
-	s.AddTool(deleteSectionTool, handleDeleteSection)
+	s.AddTool(listHistoryTool, handleListHistory)
 
-	// Tool: Delete output
-	deleteOutputTool := mcp.NewTool("delete_output",
-		mcp.WithDescription("Delete an existing output file from the configuration"),
-		mcp.WithString("filename",
+	// Tool: Restore snapshot
+	restoreSnapshotTool := mcp.NewTool("restore_snapshot",
+		mcp.WithDescription("Restore the configuration directly from a named snapshot returned by list_history, bypassing the linear undo/redo walk"),
+		mcp.WithString("snapshot",
 			mcp.Required(),
-			mcp.Description("The filename of the output to delete"),
+			mcp.Description("The snapshot filename, as returned in an entry's 'snapshot' field from list_history"),
 		),
 		mcp.WithString("config_file",
 			mcp.Description("Path to configuration file (optional, will auto-discover if not provided)"),
 		),
+		mcp.WithString("context",
+			mcp.Description("Name of a saved context (see 'ai-rulez context') to resolve config_file from, instead of an absolute path (optional)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format for the result: json (default), yaml, or table"),
+		),
 	)
-	s.AddTool(deleteOutputTool, handleDeleteOutput)
+	s.AddTool(restoreSnapshotTool, handleRestoreSnapshot)
 }
 
-func handleGetRules(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+// handleConfigResource serves the "airulez://config" resource: the
+// currently active/discovered configuration, as JSON.
+func handleConfigResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	configFile, err := resolveConfigFile(".")
+	if err != nil {
+		return nil, fmt.Errorf("no configuration file found: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading configuration: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      configResourceURI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// handleWatchConfig starts a config.Watcher for the resolved config file
+// in the background, keyed by its absolute path so a repeat call is a
+// no-op. Each debounced reload broadcasts a resources/updated
+// notification for airulez://config carrying a config.ConfigDiff.
+func handleWatchConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+	absConfig, err := filepath.Abs(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error resolving config file path: %v", err)), nil
+	}
+
+	activeConfigWatchesMu.Lock()
+	if _, already := activeConfigWatches[absConfig]; already {
+		activeConfigWatchesMu.Unlock()
+		return mcpPrint(request, map[string]interface{}{
+			"success":          true,
+			"config_file":      absConfig,
+			"already_watching": true,
+		})
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	activeConfigWatches[absConfig] = cancel
+	activeConfigWatchesMu.Unlock()
+
+	go runConfigWatch(watchCtx, absConfig)
+
+	return mcpPrint(request, map[string]interface{}{
+		"success":     true,
+		"config_file": absConfig,
+		"watching":    true,
+	})
+}
+
+// handleUnwatchConfig stops the watch handleWatchConfig started for the
+// resolved config file, if any.
+func handleUnwatchConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+	absConfig, err := filepath.Abs(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error resolving config file path: %v", err)), nil
+	}
+
+	activeConfigWatchesMu.Lock()
+	cancel, wasWatching := activeConfigWatches[absConfig]
+	delete(activeConfigWatches, absConfig)
+	activeConfigWatchesMu.Unlock()
+
+	if wasWatching {
+		cancel()
+	}
+
+	return mcpPrint(request, map[string]interface{}{
+		"success":      true,
+		"config_file":  absConfig,
+		"was_watching": wasWatching,
+	})
+}
+
+// runConfigWatch runs a config.Watcher for configFile until ctx is
+// cancelled (by handleUnwatchConfig, or the server shutting down),
+// broadcasting a resources/updated notification for airulez://config on
+// every successful debounced reload.
+func runConfigWatch(ctx context.Context, configFile string) {
+	watcher := config.NewWatcher(configFile, 0)
+	events := make(chan config.WatchEvent)
+
+	go func() {
+		for event := range events {
+			if event.Type != config.WatcherReloaded || mcpServer == nil {
+				continue
+			}
+			mcpServer.SendNotificationToAllClients("notifications/resources/updated", map[string]any{
+				"uri":         configResourceURI,
+				"config_file": configFile,
+				"diff":        event.Diff,
+			})
 		}
-		configFile = foundConfig
+	}()
+
+	_ = watcher.Run(ctx, events)
+	close(events)
+}
+
+// handleUndo reverts the most recent mutation saveWithHistory recorded for
+// the resolved config file.
+func handleUndo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	entry, err := history.Undo(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error undoing last change: %v", err)), nil
+	}
+
+	return mcpPrint(request, map[string]interface{}{
+		"success":     true,
+		"config_file": configFile,
+		"undone":      entry,
+	})
+}
+
+// handleRedo re-applies the most recently undone mutation for the resolved
+// config file.
+func handleRedo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	entry, err := history.Redo(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error redoing last undone change: %v", err)), nil
+	}
+
+	return mcpPrint(request, map[string]interface{}{
+		"success":     true,
+		"config_file": configFile,
+		"redone":      entry,
+	})
+}
+
+// handleListHistory lists the resolved config file's recorded mutation
+// history.
+func handleListHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	entries, err := history.List(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error reading history: %v", err)), nil
+	}
+
+	return mcpPrint(request, map[string]interface{}{
+		"config_file": configFile,
+		"entries":     entries,
+	})
+}
+
+// handleRestoreSnapshot restores the resolved config file directly from a
+// named snapshot, bypassing the linear undo/redo walk.
+func handleRestoreSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	snapshot := request.GetString("snapshot", "")
+	if snapshot == "" {
+		return mcp.NewToolResultError("snapshot is required"), nil
+	}
+
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	if err := history.RestoreSnapshot(configFile, snapshot); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error restoring snapshot: %v", err)), nil
+	}
+
+	return mcpPrint(request, map[string]interface{}{
+		"success":     true,
+		"config_file": configFile,
+		"snapshot":    snapshot,
+	})
+}
+
+func handleGetRules(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load configuration
@@ -1399,19 +3943,13 @@ func handleGetRules(ctx context.Context, request mcp.CallToolRequest) (*mcp.Call
 		"metadata":    cfg.Metadata,
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleGetSections(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load configuration
@@ -1428,19 +3966,13 @@ func handleGetSections(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 		"metadata":       cfg.Metadata,
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleGenerate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load configuration
@@ -1462,12 +3994,11 @@ func handleGenerate(ctx context.Context, request mcp.CallToolRequest) (*mcp.Call
 			"total_rules":    len(cfg.Rules),
 			"total_sections": len(cfg.Sections),
 		}
-		jsonResult, _ := json.MarshalIndent(result, "", "  ")
-		return mcp.NewToolResultText(string(jsonResult)), nil
+		return mcpPrint(request, result)
 	}
 
 	// Generate files
-	gen := generator.NewWithBaseDir(filepath.Dir(configFile))
+	gen := newGeneratorForConfig(cfg, filepath.Dir(configFile))
 	err = gen.GenerateAll(cfg)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error generating files: %v", err)), nil
@@ -1480,86 +4011,288 @@ func handleGenerate(ctx context.Context, request mcp.CallToolRequest) (*mcp.Call
 		"success":         true,
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleValidate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Get config file path
-	configFile := request.GetString("config_file", "")
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	// Load and validate configuration
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Configuration validation failed: %v", err)), nil
+	}
+
+	result := map[string]interface{}{
+		"config_file":    configFile,
+		"valid":          true,
+		"metadata":       cfg.Metadata,
+		"total_rules":    len(cfg.Rules),
+		"total_sections": len(cfg.Sections),
+		"total_outputs":  len(cfg.Outputs),
+	}
+
+	return mcpPrint(request, result)
+}
+
+func handleLintConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
+	}
+
+	maxContentLength := int(request.GetFloat("max_content_length", 0))
+	findings := lint.Lint(cfg, configFile, lint.Options{MaxContentLength: maxContentLength})
+
+	errorCount := 0
+	for _, f := range findings {
+		if f.Severity == lint.SeverityError {
+			errorCount++
+		}
+	}
+
+	result := map[string]interface{}{
+		"config_file": configFile,
+		"clean":       len(findings) == 0,
+		"error_count": errorCount,
+		"findings":    findings,
+	}
+
+	return mcpPrint(request, result)
+}
+
+func handleDiffOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
+	}
+
+	gen := generator.NewWithConfigFile(configFile)
+	rendered, err := gen.RenderAll(cfg)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error rendering outputs: %v", err)), nil
+	}
+
+	if wanted := request.GetString("outputs", ""); wanted != "" {
+		filtered := make(map[string][]byte)
+		for _, want := range strings.Split(wanted, ",") {
+			want = strings.TrimSpace(want)
+			content, ok := rendered[want]
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("output %q not found in configuration", want)), nil
+			}
+			filtered[want] = content
+		}
+		rendered = filtered
+	}
+
+	diffs, err := diffutil.Compute(filepath.Dir(configFile), rendered)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error comparing outputs: %v", err)), nil
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].File < diffs[j].File })
+
+	changed := 0
+	for _, d := range diffs {
+		if d.Changed {
+			changed++
+		}
+	}
+
+	result := map[string]interface{}{
+		"config_file": configFile,
+		"changed":     changed,
+		"total":       len(diffs),
+		"diffs":       diffs,
+	}
+
+	return mcpPrint(request, result)
+}
+
+// handleListTemplates enumerates the same scaffold.Registry `ai-rulez init
+// --list-templates` does - the embedded catalog, any user-installed
+// templates, and the optional remote catalog - rather than a hardcoded
+// slice, so a new template added to the catalog shows up here too.
+func handleListTemplates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	registry, err := scaffold.NewRegistry(viper.GetString("template_catalog_url"))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to load template catalog: %v", err)), nil
+	}
+
+	templates := make([]map[string]interface{}, 0, len(registry.List()))
+	for _, tmpl := range registry.List() {
+		outputs := make([]string, 0, len(tmpl.Config.Outputs))
+		for _, output := range tmpl.Config.Outputs {
+			outputs = append(outputs, output.File)
+		}
+		templates = append(templates, map[string]interface{}{
+			"name":        tmpl.Name,
+			"description": tmpl.Description,
+			"outputs":     outputs,
+		})
+	}
+
+	result := map[string]interface{}{
+		"available_templates": templates,
+		"total_templates":     len(templates),
+	}
+
+	return mcpPrint(request, result)
+}
+
+// readFromStdin reads content from standard input until EOF
+func readFromStdin() (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	var content strings.Builder
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				content.WriteString(line)
+				break
+			}
+			return "", err
+		}
+		content.WriteString(line)
+	}
+
+	return strings.TrimSpace(content.String()), nil
+}
+
+// completionConfig loads the config a completion function should complete
+// against: the --config flag if set, otherwise whatever resolveConfigFile
+// picks (--context, the active context, or the nearest discovered config
+// file). Any error (no config found, a broken config) just means no
+// completions are offered, never a shell error.
+func completionConfig(cmd *cobra.Command) *config.Config {
+	configFile, _ := cmd.Flags().GetString("config")
 	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
+		found, err := resolveConfigFile(".")
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+			return nil
 		}
-		configFile = foundConfig
+		configFile = found
 	}
 
-	// Load and validate configuration
 	cfg, err := config.LoadConfig(configFile)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Configuration validation failed: %v", err)), nil
+		return nil
+	}
+	return cfg
+}
+
+// completeRuleNames completes a rule name from the nearest config's rules,
+// for `update rule`/`delete rule`.
+func completeRuleNames(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := completionConfig(cmd)
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		if strings.HasPrefix(rule.Name, toComplete) {
+			names = append(names, rule.Name)
+		}
 	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
 
-	result := map[string]interface{}{
-		"config_file":    configFile,
-		"valid":          true,
-		"metadata":       cfg.Metadata,
-		"total_rules":    len(cfg.Rules),
-		"total_sections": len(cfg.Sections),
-		"total_outputs":  len(cfg.Outputs),
+// completeSectionTitles completes a section title from the nearest
+// config's sections, for `delete section`.
+func completeSectionTitles(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := completionConfig(cmd)
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	titles := make([]string, 0, len(cfg.Sections))
+	for _, section := range cfg.Sections {
+		if strings.HasPrefix(section.Title, toComplete) {
+			titles = append(titles, section.Title)
+		}
+	}
+	return titles, cobra.ShellCompDirectiveNoFileComp
 }
 
-func handleListTemplates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	templates := []map[string]interface{}{
-		{
-			"name":        "basic",
-			"description": "Basic AI rules template with code quality, documentation, and testing rules",
-			"outputs":     []string{"claude.md", ".cursorrules", ".windsurfrules"},
-		},
-		{
-			"name":        "react",
-			"description": "React project template with component structure, state management, and performance rules",
-			"outputs":     []string{"claude.md", ".cursorrules", ".windsurfrules"},
-		},
-		{
-			"name":        "typescript",
-			"description": "TypeScript project template with type safety, interface design, and error handling rules",
-			"outputs":     []string{"claude.md", ".cursorrules", ".windsurfrules"},
-		},
+// completeOutputFiles completes an output filename from the nearest
+// config's outputs, for `update output`/`delete output`.
+func completeOutputFiles(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := completionConfig(cmd)
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	result := map[string]interface{}{
-		"available_templates": templates,
-		"total_templates":     len(templates),
+	files := make([]string, 0, len(cfg.Outputs))
+	for _, output := range cfg.Outputs {
+		if strings.HasPrefix(output.File, toComplete) {
+			files = append(files, output.File)
+		}
 	}
-
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return files, cobra.ShellCompDirectiveNoFileComp
 }
 
-// readFromStdin reads content from standard input until EOF
-func readFromStdin() (string, error) {
-	reader := bufio.NewReader(os.Stdin)
-	var content strings.Builder
+// completeTemplateNames completes the --template flag on `init`/`add
+// output` with the scaffold catalog's template names (built-in,
+// user-installed, and remote, as scaffold.NewRegistry lists them) plus any
+// *.tmpl file discovered under the working tree.
+func completeTemplateNames(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
 
-	for {
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			if err == io.EOF {
-				content.WriteString(line)
-				break
-			}
-			return "", err
+	if registry, err := scaffold.NewRegistry(viper.GetString("template_catalog_url")); err == nil {
+		for _, tmpl := range registry.List() {
+			names = append(names, tmpl.Name)
 		}
-		content.WriteString(line)
 	}
 
-	return strings.TrimSpace(content.String()), nil
+	_ = filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(path, ".tmpl") {
+			names = append(names, "@"+path)
+		}
+		return nil
+	})
+
+	completions := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileNames completes the --profile flag on `context add` with
+// the built-in profile names from config.ListAvailableProfiles.
+func completeProfileNames(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := config.ListAvailableProfiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
 func handleAddRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1576,18 +4309,13 @@ func handleAddRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 
 	priority := int(request.GetFloat("priority", 5))
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -1600,8 +4328,41 @@ func handleAddRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 	}
 	cfg.Rules = append(cfg.Rules, newRule)
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "add_rule", map[string]interface{}{
+		"name":     name,
+		"content":  content,
+		"priority": priority,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -1615,8 +4376,7 @@ func handleAddRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 		"total_rules": len(cfg.Rules),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleAddSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1633,18 +4393,13 @@ func handleAddSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 
 	priority := int(request.GetFloat("priority", 5))
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -1657,8 +4412,41 @@ func handleAddSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 	}
 	cfg.Sections = append(cfg.Sections, newSection)
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "add_section", map[string]interface{}{
+		"title":    title,
+		"content":  content,
+		"priority": priority,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -1672,8 +4460,7 @@ func handleAddSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 		"total_sections": len(cfg.Sections),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleAddOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1685,18 +4472,13 @@ func handleAddOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.Cal
 
 	template := request.GetString("template", "")
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -1715,8 +4497,40 @@ func handleAddOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.Cal
 	}
 	cfg.Outputs = append(cfg.Outputs, newOutput)
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "add_output", map[string]interface{}{
+		"filename": filename,
+		"template": template,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -1730,8 +4544,7 @@ func handleAddOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.Cal
 		"total_outputs": len(cfg.Outputs),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleUpdateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1744,18 +4557,13 @@ func handleUpdateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 	newContent := request.GetString("content", "")
 	priority := int(request.GetFloat("priority", 0))
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -1781,8 +4589,41 @@ func handleUpdateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 		cfg.Rules[ruleIndex].Priority = priority
 	}
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "update_rule", map[string]interface{}{
+		"name":     name,
+		"content":  newContent,
+		"priority": priority,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -1797,8 +4638,7 @@ func handleUpdateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 		"total_rules": len(cfg.Rules),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleUpdateSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1811,18 +4651,13 @@ func handleUpdateSection(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	newContent := request.GetString("content", "")
 	priority := int(request.GetFloat("priority", 0))
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -1848,8 +4683,41 @@ func handleUpdateSection(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		cfg.Sections[sectionIndex].Priority = priority
 	}
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "update_section", map[string]interface{}{
+		"title":    title,
+		"content":  newContent,
+		"priority": priority,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -1864,8 +4732,7 @@ func handleUpdateSection(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		"total_sections": len(cfg.Sections),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleUpdateOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1880,18 +4747,13 @@ func handleUpdateOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 		return mcp.NewToolResultError("Template is required"), nil
 	}
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -1912,8 +4774,40 @@ func handleUpdateOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 	// Update the output
 	cfg.Outputs[outputIndex].Template = template
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "update_output", map[string]interface{}{
+		"filename": filename,
+		"template": template,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -1928,8 +4822,7 @@ func handleUpdateOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 		"total_outputs": len(cfg.Outputs),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleDeleteRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1939,18 +4832,13 @@ func handleDeleteRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 		return mcp.NewToolResultError("Rule name is required"), nil
 	}
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -1971,8 +4859,39 @@ func handleDeleteRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 	// Remove the rule
 	cfg.Rules = append(cfg.Rules[:ruleIndex], cfg.Rules[ruleIndex+1:]...)
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "delete_rule", map[string]interface{}{
+		"name": name,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -1983,8 +4902,7 @@ func handleDeleteRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 		"total_rules": len(cfg.Rules),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleDeleteSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1994,18 +4912,13 @@ func handleDeleteSection(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		return mcp.NewToolResultError("Section title is required"), nil
 	}
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -2026,8 +4939,39 @@ func handleDeleteSection(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	// Remove the section
 	cfg.Sections = append(cfg.Sections[:sectionIndex], cfg.Sections[sectionIndex+1:]...)
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "delete_section", map[string]interface{}{
+		"title": title,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -2038,8 +4982,7 @@ func handleDeleteSection(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		"total_sections": len(cfg.Sections),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
 }
 
 func handleDeleteOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -2049,18 +4992,13 @@ func handleDeleteOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 		return mcp.NewToolResultError("Output filename is required"), nil
 	}
 
-	// Get config file path
-	configFile := request.GetString("config_file", "")
-	if configFile == "" {
-		foundConfig, err := config.FindConfigFile(".")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
-		}
-		configFile = foundConfig
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
 	}
 
 	// Load existing configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, rev, err := mcpStorage.Load(configFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
 	}
@@ -2081,8 +5019,39 @@ func handleDeleteOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 	// Remove the output
 	cfg.Outputs = append(cfg.Outputs[:outputIndex], cfg.Outputs[outputIndex+1:]...)
 
+	// Validate before writing: schema validation, lint, and a trial render
+	// of every output, so a bad template or an out-of-range priority never
+	// reaches disk.
+	if findings := validateMutation(cfg, configFile); len(findings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"validation": map[string]interface{}{
+				"valid":    false,
+				"findings": findings,
+			},
+		})
+	}
+
+	if request.GetBool("dry_run", false) {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     true,
+			"config_file": configFile,
+			"dry_run":     true,
+			"validation": map[string]interface{}{
+				"valid":    true,
+				"findings": []lint.Finding{},
+			},
+		})
+	}
+
 	// Save configuration
-	if err := config.SaveConfig(cfg, configFile); err != nil {
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "delete_output", map[string]interface{}{
+		"filename": filename,
+	}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry", configFile)), nil
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
 	}
 
@@ -2093,6 +5062,244 @@ func handleDeleteOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 		"total_outputs": len(cfg.Outputs),
 	}
 
-	jsonResult, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonResult)), nil
+	return mcpPrint(request, result)
+}
+
+// changesetOp is one operation in an apply_changeset request: add, update,
+// or delete a rule, section, or output, applied to an in-memory copy of
+// the config before anything is written to disk.
+type changesetOp struct {
+	Op       string `json:"op"`
+	Name     string `json:"name,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Priority *int   `json:"priority,omitempty"`
+	Template string `json:"template,omitempty"`
+}
+
+// changesetOpResult reports the outcome of a single changesetOp, keyed by
+// its position in the request's operations array so a caller can
+// correlate a failure back to the op that caused it.
+type changesetOpResult struct {
+	Index   int    `json:"index"`
+	Op      string `json:"op"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// applyChangesetOp mutates cfg in place for a single op, matching the
+// same field semantics as the single-op add/update/delete MCP handlers
+// (e.g. priority 0 on an update means "leave it unchanged").
+func applyChangesetOp(cfg *config.Config, op changesetOp) error {
+	switch op.Op {
+	case "add_rule":
+		if op.Name == "" || op.Content == "" {
+			return fmt.Errorf("add_rule requires name and content")
+		}
+		priority := 5
+		if op.Priority != nil {
+			priority = *op.Priority
+		}
+		cfg.Rules = append(cfg.Rules, config.Rule{Name: op.Name, Priority: priority, Content: op.Content})
+
+	case "update_rule":
+		idx := -1
+		for i, rule := range cfg.Rules {
+			if rule.Name == op.Name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("rule %q not found", op.Name)
+		}
+		if op.Content != "" {
+			cfg.Rules[idx].Content = op.Content
+		}
+		if op.Priority != nil && *op.Priority > 0 {
+			cfg.Rules[idx].Priority = *op.Priority
+		}
+
+	case "delete_rule":
+		idx := -1
+		for i, rule := range cfg.Rules {
+			if rule.Name == op.Name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("rule %q not found", op.Name)
+		}
+		cfg.Rules = append(cfg.Rules[:idx], cfg.Rules[idx+1:]...)
+
+	case "add_section":
+		if op.Title == "" || op.Content == "" {
+			return fmt.Errorf("add_section requires title and content")
+		}
+		priority := 5
+		if op.Priority != nil {
+			priority = *op.Priority
+		}
+		cfg.Sections = append(cfg.Sections, config.Section{Title: op.Title, Priority: priority, Content: op.Content})
+
+	case "update_section":
+		idx := -1
+		for i, section := range cfg.Sections {
+			if section.Title == op.Title {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("section %q not found", op.Title)
+		}
+		if op.Content != "" {
+			cfg.Sections[idx].Content = op.Content
+		}
+		if op.Priority != nil && *op.Priority > 0 {
+			cfg.Sections[idx].Priority = *op.Priority
+		}
+
+	case "delete_section":
+		idx := -1
+		for i, section := range cfg.Sections {
+			if section.Title == op.Title {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("section %q not found", op.Title)
+		}
+		cfg.Sections = append(cfg.Sections[:idx], cfg.Sections[idx+1:]...)
+
+	case "add_output":
+		if op.Filename == "" {
+			return fmt.Errorf("add_output requires filename")
+		}
+		for _, output := range cfg.Outputs {
+			if output.File == op.Filename {
+				return fmt.Errorf("output file %q already exists in configuration", op.Filename)
+			}
+		}
+		cfg.Outputs = append(cfg.Outputs, config.Output{File: op.Filename, Template: op.Template})
+
+	case "update_output":
+		if op.Template == "" {
+			return fmt.Errorf("update_output requires template")
+		}
+		idx := -1
+		for i, output := range cfg.Outputs {
+			if output.File == op.Filename {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("output file %q not found", op.Filename)
+		}
+		cfg.Outputs[idx].Template = op.Template
+
+	case "delete_output":
+		idx := -1
+		for i, output := range cfg.Outputs {
+			if output.File == op.Filename {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("output file %q not found", op.Filename)
+		}
+		cfg.Outputs = append(cfg.Outputs[:idx], cfg.Outputs[idx+1:]...)
+
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+
+	return nil
+}
+
+// handleApplyChangeset applies a batch of changesetOps to a single
+// in-memory copy of the config, then writes once: the first op to fail
+// aborts the changeset (nothing before it is persisted either, since the
+// whole batch never reaches Save), and a resulting config that fails lint
+// validation rolls back the same way. A successful changeset is a single
+// compare-and-swap against mcpStorage, so it races with other writers the
+// same way a single add/update/delete call does.
+func handleApplyChangeset(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rawOps := request.GetString("operations", "")
+	if rawOps == "" {
+		return mcp.NewToolResultError("operations is required (a JSON array of changeset ops)"), nil
+	}
+
+	var ops []changesetOp
+	if err := json.Unmarshal([]byte(rawOps), &ops); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("operations is not valid JSON: %v", err)), nil
+	}
+	if len(ops) == 0 {
+		return mcp.NewToolResultError("operations must contain at least one op"), nil
+	}
+
+	configFile, err := resolveMCPConfigFile(request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No configuration file found: %v", err)), nil
+	}
+
+	cfg, rev, err := mcpStorage.Load(configFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error loading configuration: %v", err)), nil
+	}
+
+	results := make([]changesetOpResult, 0, len(ops))
+	for i, op := range ops {
+		if err := applyChangesetOp(cfg, op); err != nil {
+			results = append(results, changesetOpResult{Index: i, Op: op.Op, Success: false, Error: err.Error()})
+			return mcpPrint(request, map[string]interface{}{
+				"success":     false,
+				"config_file": configFile,
+				"error":       "changeset rolled back: op failed, nothing was written",
+				"results":     results,
+			})
+		}
+		results = append(results, changesetOpResult{Index: i, Op: op.Op, Success: true})
+	}
+
+	findings := lint.Lint(cfg, configFile, lint.Options{})
+	var errorFindings []lint.Finding
+	for _, finding := range findings {
+		if finding.Severity == lint.SeverityError {
+			errorFindings = append(errorFindings, finding)
+		}
+	}
+	if len(errorFindings) > 0 {
+		return mcpPrint(request, map[string]interface{}{
+			"success":     false,
+			"config_file": configFile,
+			"error":       "changeset rolled back: resulting configuration failed validation",
+			"findings":    errorFindings,
+			"results":     results,
+		})
+	}
+
+	if err := saveWithHistory(ctx, configFile, cfg, rev, "apply_changeset", map[string]interface{}{"operations": ops}); err != nil {
+		if errors.Is(err, config.ErrConflict) {
+			return mcp.NewToolResultError(fmt.Sprintf("Conflict: %s was modified by another writer since it was loaded; reload and retry the whole changeset", configFile)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Error saving configuration: %v", err)), nil
+	}
+
+	result := map[string]interface{}{
+		"success":        true,
+		"config_file":    configFile,
+		"applied":        len(ops),
+		"results":        results,
+		"total_rules":    len(cfg.Rules),
+		"total_sections": len(cfg.Sections),
+		"total_outputs":  len(cfg.Outputs),
+	}
+
+	return mcpPrint(request, result)
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+)
+
+func TestApplySetOverrides(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: "main", Version: "1.0.0"},
+		Outputs:  []config.Output{{File: "CLAUDE.md"}},
+		Rules:    []config.Rule{{ID: "rule1", Name: "Rule 1", Priority: 1}},
+	}
+
+	err := applySetOverrides(cfg, []string{
+		"METADATA__VERSION=2.0.0",
+		"OUTPUTS__0__MODE=skip",
+		"rules__rule1__priority=9",
+	})
+	if err != nil {
+		t.Fatalf("applySetOverrides() error = %v", err)
+	}
+
+	if cfg.Metadata.Version != "2.0.0" {
+		t.Errorf("Metadata.Version = %q, want 2.0.0", cfg.Metadata.Version)
+	}
+	if cfg.Outputs[0].Mode != "skip" {
+		t.Errorf("Outputs[0].Mode = %q, want skip", cfg.Outputs[0].Mode)
+	}
+	if cfg.Rules[0].Priority != 9 {
+		t.Errorf("Rules[0].Priority = %d, want 9", cfg.Rules[0].Priority)
+	}
+}
+
+func TestApplySetOverridesInvalidPair(t *testing.T) {
+	cfg := &config.Config{Metadata: config.Metadata{Name: "main"}}
+
+	if err := applySetOverrides(cfg, []string{"not-a-pair"}); err == nil {
+		t.Error("applySetOverrides() expected an error for a malformed --set value")
+	}
+}
+
+func TestApplyCacheFlags_NoCacheDisables(t *testing.T) {
+	cfg := &config.Config{Cache: &config.CacheConfig{Enabled: true, MaxAge: "1h"}}
+
+	applyCacheFlags(cfg, true, "")
+
+	if cfg.Cache != nil {
+		t.Errorf("Cache = %+v, want nil after --no-cache", cfg.Cache)
+	}
+}
+
+func TestApplyCacheFlags_MaxAgeEnablesAndOverrides(t *testing.T) {
+	cfg := &config.Config{}
+
+	applyCacheFlags(cfg, false, "1h")
+
+	if cfg.Cache == nil || !cfg.Cache.Enabled || cfg.Cache.MaxAge != "1h" {
+		t.Errorf("Cache = %+v, want enabled with MaxAge 1h", cfg.Cache)
+	}
+}
+
+func TestApplyCacheFlags_NoFlagsLeavesCacheUntouched(t *testing.T) {
+	cfg := &config.Config{Cache: &config.CacheConfig{Enabled: true, MaxAge: "30m"}}
+
+	applyCacheFlags(cfg, false, "")
+
+	if cfg.Cache.MaxAge != "30m" {
+		t.Errorf("Cache.MaxAge = %q, want unchanged 30m", cfg.Cache.MaxAge)
+	}
+}
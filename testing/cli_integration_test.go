@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/Goldziher/ai-rulez/internal/fsutil"
 )
 
 func TestCLIIntegration(t *testing.T) {
@@ -67,20 +69,28 @@ func copyTestScenarios(t *testing.T, destDir string) error {
 	return nil
 }
 
+// copyDir and copyFile stage scenario fixtures through fsutil.OsFS rather
+// than calling os.ReadFile/os.WriteFile/os.MkdirAll directly, so this
+// staging step uses the same Filesystem abstraction as the config/generator
+// packages under test. It still reads/writes the real tempDir: these tests
+// exec the compiled CLI binary as a subprocess, which can only see real
+// files, so unlike a unit test this one can't stage scenarios in a MemFS.
+var stagingFS = fsutil.NewOsFS()
+
 func copyDir(src, dst string) error {
-	entries, err := os.ReadDir(src)
+	entries, err := stagingFS.ReadDir(src)
 	if err != nil {
 		return err
 	}
-	
-	if err := os.MkdirAll(dst, 0755); err != nil {
+
+	if err := stagingFS.MkdirAll(dst, 0755); err != nil {
 		return err
 	}
-	
+
 	for _, entry := range entries {
 		srcPath := filepath.Join(src, entry.Name())
 		dstPath := filepath.Join(dst, entry.Name())
-		
+
 		if entry.IsDir() {
 			if err := copyDir(srcPath, dstPath); err != nil {
 				return err
@@ -91,15 +101,15 @@ func copyDir(src, dst string) error {
 			}
 		}
 	}
-	
+
 	return nil
 }
 
 func copyFile(src, dst string) error {
-	data, err := os.ReadFile(src)
+	data, err := stagingFS.ReadFile(src)
 	if err != nil {
 		return err
 	}
-	
-	return os.WriteFile(dst, data, 0644)
+
+	return stagingFS.WriteFile(dst, data, 0644)
 }
\ No newline at end of file
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestRecursiveWatchTemplateFiles(t *testing.T) {
+	configFile := filepath.Join("project", "ai_rulez.yaml")
+	cfg := &config.Config{
+		Outputs: []config.Output{
+			{File: "CLAUDE.md", Template: "@templates/claude.tmpl"},
+			{File: "CURSOR.md", Template: "@templates/claude.tmpl"}, // shared template, should dedupe
+			{File: "PLAIN.md"},                                      // no @ template, should be ignored
+		},
+	}
+
+	files := recursiveWatchTemplateFiles(configFile, cfg)
+
+	want := filepath.Join("project", "templates/claude.tmpl")
+	if len(files) != 1 || files[0] != want {
+		t.Errorf("recursiveWatchTemplateFiles() = %v, want [%s]", files, want)
+	}
+}
+
+func TestSyncRecursiveWatchSetAddsAndRemovesWatches(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "ai_rulez.yaml")
+
+	writeConfig := func(content string) {
+		if err := os.WriteFile(configFile, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+	writeConfig(`
+metadata:
+  name: Watch Project
+outputs:
+  - file: CLAUDE.md
+    template: "@claude.tmpl"
+`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "claude.tmpl"), []byte("{{.ProjectName}}"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer fsWatcher.Close()
+
+	watched := make(map[string]bool)
+	owners, err := syncRecursiveWatchSet(fsWatcher, []string{configFile}, watched)
+	if err != nil {
+		t.Fatalf("syncRecursiveWatchSet() error = %v", err)
+	}
+
+	templatePath := filepath.Join(tmpDir, "claude.tmpl")
+	if len(owners[templatePath]) != 1 || owners[templatePath][0] != configFile {
+		t.Errorf("owners[%s] = %v, want [%s]", templatePath, owners[templatePath], configFile)
+	}
+	if !watched[templatePath] {
+		t.Error("expected the @-referenced template to be added to the watch set")
+	}
+
+	// Drop the template reference: the next sync should stop watching it.
+	writeConfig(`
+metadata:
+  name: Watch Project
+outputs:
+  - file: CLAUDE.md
+`)
+	owners, err = syncRecursiveWatchSet(fsWatcher, []string{configFile}, watched)
+	if err != nil {
+		t.Fatalf("syncRecursiveWatchSet() error = %v", err)
+	}
+	if _, ok := owners[templatePath]; ok {
+		t.Error("expected the dropped template to no longer be an owner key")
+	}
+	if watched[templatePath] {
+		t.Error("expected the dropped template to be removed from the watch set")
+	}
+}
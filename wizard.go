@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/Goldziher/ai-rulez/internal/config"
+	"github.com/Goldziher/ai-rulez/internal/scaffold"
+)
+
+// errWizardCancelled is returned by the wizard prompts below when the user
+// declines the final confirmation step, so callers can exit quietly instead
+// of reporting a failure.
+var errWizardCancelled = errors.New("cancelled")
+
+// knownAssistant pairs an AI assistant's display name with the output file
+// ai-rulez conventionally generates for it.
+type knownAssistant struct {
+	Name string
+	File string
+}
+
+// knownAssistants lists the assistants the init wizard offers to target.
+// Cursor and Windsurf match the outputs already shipped by the built-in
+// scaffold templates (see internal/scaffold/templates), which name Claude's
+// file claude.md rather than CLAUDE.md; Copilot's convention lives under
+// .github, same as its own instructions file format.
+var knownAssistants = []knownAssistant{
+	{Name: "Claude", File: "CLAUDE.md"},
+	{Name: "Cursor", File: ".cursorrules"},
+	{Name: "Windsurf", File: ".windsurfrules"},
+	{Name: "GitHub Copilot", File: ".github/copilot-instructions.md"},
+}
+
+// runInitWizard interactively builds the configuration for `ai-rulez init
+// --wizard`, in place of the non-interactive --template flag. The second
+// return value reports whether the user asked for the generated outputs to
+// be added to .gitignore.
+func runInitWizard(projectName string) (*config.Config, bool, error) {
+	if err := survey.AskOne(&survey.Input{
+		Message: "Project name:",
+		Default: projectName,
+	}, &projectName); err != nil {
+		return nil, false, err
+	}
+
+	var description string
+	if err := survey.AskOne(&survey.Input{
+		Message: "Project description (optional):",
+	}, &description); err != nil {
+		return nil, false, err
+	}
+
+	assistantNames := make([]string, len(knownAssistants))
+	for i, a := range knownAssistants {
+		assistantNames[i] = a.Name
+	}
+	var targets []string
+	if err := survey.AskOne(&survey.MultiSelect{
+		Message: "Which AI assistants should ai-rulez generate rules for?",
+		Options: assistantNames,
+		Default: []string{"Claude", "Cursor"},
+	}, &targets); err != nil {
+		return nil, false, err
+	}
+
+	seedExamples := true
+	if err := survey.AskOne(&survey.Confirm{
+		Message: "Seed the project with example rules?",
+		Default: true,
+	}, &seedExamples); err != nil {
+		return nil, false, err
+	}
+
+	wantGitignore := true
+	if err := survey.AskOne(&survey.Confirm{
+		Message: "Add the generated output files to .gitignore?",
+		Default: true,
+	}, &wantGitignore); err != nil {
+		return nil, false, err
+	}
+
+	confirmed := true
+	if err := survey.AskOne(&survey.Confirm{
+		Message: fmt.Sprintf("Create ai_rulez.yaml for %q?", projectName),
+		Default: true,
+	}, &confirmed); err != nil {
+		return nil, false, err
+	}
+	if !confirmed {
+		return nil, false, errWizardCancelled
+	}
+
+	cfg := &config.Config{
+		Metadata: config.Metadata{Name: projectName, Description: description},
+	}
+	targetSet := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		targetSet[t] = true
+	}
+	for _, a := range knownAssistants {
+		if targetSet[a.Name] {
+			cfg.Outputs = append(cfg.Outputs, config.Output{File: a.File})
+		}
+	}
+
+	if seedExamples {
+		registry, err := scaffold.NewRegistry("")
+		if err == nil {
+			if tmpl, err := registry.Get("basic"); err == nil {
+				cfg.Rules = tmpl.Config.Rules
+			}
+		}
+	}
+
+	return cfg, wantGitignore, nil
+}
+
+// runAddRuleWizard interactively gathers the fields `ai-rulez add rule
+// --wizard` needs, prompting for whatever wasn't supplied positionally.
+func runAddRuleWizard(name string) (config.Rule, error) {
+	if name == "" {
+		if err := survey.AskOne(&survey.Input{
+			Message: "Rule name:",
+		}, &name, survey.WithValidator(survey.Required)); err != nil {
+			return config.Rule{}, err
+		}
+	}
+
+	priority, err := promptPriority(5)
+	if err != nil {
+		return config.Rule{}, err
+	}
+
+	var content string
+	if err := survey.AskOne(&survey.Editor{
+		Message:       fmt.Sprintf("Content for rule %q ($EDITOR opens for multi-line input):", name),
+		AppendDefault: true,
+	}, &content, survey.WithValidator(survey.Required)); err != nil {
+		return config.Rule{}, err
+	}
+
+	if err := confirmOrCancel(fmt.Sprintf("Add rule %q with priority %d?", name, priority)); err != nil {
+		return config.Rule{}, err
+	}
+
+	return config.Rule{Name: name, Priority: priority, Content: content}, nil
+}
+
+// runAddSectionWizard is runAddRuleWizard's counterpart for `ai-rulez add
+// section --wizard`.
+func runAddSectionWizard(title string) (config.Section, error) {
+	if title == "" {
+		if err := survey.AskOne(&survey.Input{
+			Message: "Section title:",
+		}, &title, survey.WithValidator(survey.Required)); err != nil {
+			return config.Section{}, err
+		}
+	}
+
+	priority, err := promptPriority(5)
+	if err != nil {
+		return config.Section{}, err
+	}
+
+	var content string
+	if err := survey.AskOne(&survey.Editor{
+		Message:       fmt.Sprintf("Content for section %q ($EDITOR opens for multi-line input):", title),
+		AppendDefault: true,
+	}, &content, survey.WithValidator(survey.Required)); err != nil {
+		return config.Section{}, err
+	}
+
+	if err := confirmOrCancel(fmt.Sprintf("Add section %q with priority %d?", title, priority)); err != nil {
+		return config.Section{}, err
+	}
+
+	return config.Section{Title: title, Priority: priority, Content: content}, nil
+}
+
+// runAddOutputWizard is runAddRuleWizard's counterpart for `ai-rulez add
+// output --wizard`, offering a picker over the same built-in templates and
+// discovered @file templates that completeTemplateNames exposes for shell
+// completion.
+func runAddOutputWizard(filename string) (config.Output, error) {
+	if filename == "" {
+		if err := survey.AskOne(&survey.Input{
+			Message: "Output filename:",
+		}, &filename, survey.WithValidator(survey.Required)); err != nil {
+			return config.Output{}, err
+		}
+	}
+
+	const noTemplate = "(none - use the default rules/sections layout)"
+	options := append([]string{noTemplate}, allTemplateNames()...)
+
+	var choice string
+	if err := survey.AskOne(&survey.Select{
+		Message: "Template to render this output with:",
+		Options: options,
+		Default: noTemplate,
+	}, &choice); err != nil {
+		return config.Output{}, err
+	}
+	if choice == noTemplate {
+		choice = ""
+	}
+
+	if err := confirmOrCancel(fmt.Sprintf("Add output %q?", filename)); err != nil {
+		return config.Output{}, err
+	}
+
+	return config.Output{File: filename, Template: choice}, nil
+}
+
+// allTemplateNames lists every name completeTemplateNames would offer,
+// without the flag-completion machinery's (cmd, toComplete) filtering.
+func allTemplateNames() []string {
+	names, _ := completeTemplateNames(initCmd, nil, "")
+	return names
+}
+
+// promptPriority asks for a priority as free text so the wizard can reject
+// non-numeric input with a clear message, rather than the silent 0 an
+// int-typed survey.Input would fall back to.
+func promptPriority(def int) (int, error) {
+	raw := strconv.Itoa(def)
+	if err := survey.AskOne(&survey.Input{
+		Message: "Priority (higher runs first):",
+		Default: raw,
+	}, &raw); err != nil {
+		return 0, err
+	}
+	priority, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("priority must be a number: %w", err)
+	}
+	return priority, nil
+}
+
+// confirmOrCancel asks a yes/no question defaulting to yes, returning
+// errWizardCancelled if the user declines.
+func confirmOrCancel(message string) error {
+	confirmed := true
+	if err := survey.AskOne(&survey.Confirm{Message: message, Default: true}, &confirmed); err != nil {
+		return err
+	}
+	if !confirmed {
+		return errWizardCancelled
+	}
+	return nil
+}
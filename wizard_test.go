@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestKnownAssistantsHaveUniqueFiles(t *testing.T) {
+	seen := make(map[string]bool, len(knownAssistants))
+	for _, a := range knownAssistants {
+		if a.Name == "" || a.File == "" {
+			t.Fatalf("knownAssistant entry missing name or file: %+v", a)
+		}
+		if seen[a.File] {
+			t.Errorf("output file %q is mapped to more than one assistant", a.File)
+		}
+		seen[a.File] = true
+	}
+}
+
+func TestAllTemplateNamesIncludesBuiltins(t *testing.T) {
+	names := allTemplateNames()
+	found := false
+	for _, name := range names {
+		if name == "basic" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected allTemplateNames to include the built-in 'basic' template, got %v", names)
+	}
+}